@@ -0,0 +1,443 @@
+package cmd
+
+import (
+    "bufio"
+    "fmt"
+    "net/http"
+    "net/url"
+    "os"
+    "strings"
+    "text/tabwriter"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// NewAddonsCommand creates a new addons command
+func NewAddonsCommand(api *api.GhaymahAPI) *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "addons",
+        Short: "Manage managed database add-ons",
+        Long: `Manage the managed Postgres, Redis and Mongo databases attached to applications.
+On each deploy, the connection URL of an add-on, with its credentials, is
+injected into the environment of the attached application: DATABASE_URL for
+postgres, REDIS_URL for redis and MONGODB_URI for mongo, unless another
+variable is given with --env-var. A variable set under envVars: in the config
+file takes precedence, so remove hardcoded hosts from it.
+
+Examples:
+  # Show the plans of a type
+  ghaymah addons plans postgres
+
+  # Create a Postgres add-on for the application, injected as DB_URL
+  ghaymah addons create postgres --plan standard --name shop --env-var DB_URL
+
+  # List add-ons, show one with its connection URL
+  ghaymah addons list
+  ghaymah addons info shop-postgres --show-credentials
+
+  # Replace the password; the application is restarted with the new URL
+  ghaymah addons rotate shop-postgres
+
+  # Take a backup before a migration, and restore it if the migration goes wrong
+  ghaymah addons backups create shop-postgres
+  ghaymah addons backups restore shop-postgres --backup bk-1a2b3c4d`,
+    }
+
+    cmd.AddCommand(
+        newAddonsPlansCommand(api),
+        newAddonsCreateCommand(api),
+        newAddonsListCommand(api),
+        newAddonsInfoCommand(api),
+        newAddonsDestroyCommand(api),
+        newAddonsRotateCommand(api),
+        newAddonsBackupsCommand(api),
+    )
+
+    return cmd
+}
+
+// newAddonsPlansCommand creates the addons plans subcommand
+func newAddonsPlansCommand(api *api.GhaymahAPI) *cobra.Command {
+    return &cobra.Command{
+        Use:       "plans [TYPE]",
+        Short:     "List the plans add-ons can be created with",
+        Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+        ValidArgs: types.AddonTypes,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            addonType := ""
+            if len(args) > 0 {
+                addonType = args[0]
+            }
+
+            plans, err := api.ListAddonPlans(addonType)
+            if err != nil {
+                return fmt.Errorf("failed to list plans: %v", err)
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "TYPE\tPLAN\tMEMORY\tSTORAGE\tCONNECTIONS\tBACKUP DAYS\tHA\tPRICE/MONTH")
+            for _, plan := range plans.Plans {
+                ha := "no"
+                if plan.HighAvailability {
+                    ha = "yes"
+                }
+                fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\t$%.2f\n",
+                    plan.Type,
+                    plan.Name,
+                    plan.Memory,
+                    plan.Storage,
+                    plan.MaxConnections,
+                    plan.BackupDays,
+                    ha,
+                    plan.MonthlyPrice,
+                )
+            }
+            return w.Flush()
+        },
+    }
+}
+
+// newAddonsCreateCommand creates the addons create subcommand
+func newAddonsCreateCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+        options    types.AddonOptions
+    )
+
+    cmd := &cobra.Command{
+        Use:   "create TYPE [ADDON]",
+        Short: "Create an add-on attached to an application",
+        Long: `Create a managed database of the given type (postgres, redis or mongo)
+attached to an application. The add-on is named <app>-<type> unless ADDON is
+given. Its connection URL is injected into the application on the next deploy.
+
+The application is given with --name. When omitted, it is read from appName
+in the config file, or from the app linked with 'ghaymah link'.`,
+        Args:         cobra.RangeArgs(1, 2),
+        SilenceUsage: true,
+        ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+            if len(args) > 0 {
+                return nil, cobra.ShellCompDirectiveNoFileComp
+            }
+            return filterCompletions(types.AddonTypes, toComplete), cobra.ShellCompDirectiveNoFileComp
+        },
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if !isAddonType(args[0]) {
+                return fmt.Errorf("unknown add-on type %q (expected one of %s)", args[0], strings.Join(types.AddonTypes, ", "))
+            }
+            name, err := resolveAppName(nil, appName, configPath, envName)
+            if err != nil {
+                return err
+            }
+
+            options.Type = args[0]
+            options.App = name
+            if len(args) > 1 {
+                options.Name = args[1]
+            }
+
+            addon, err := api.CreateAddon(&options)
+            if err != nil {
+                return addonError("create", options.Name, err)
+            }
+
+            fmt.Printf("Creating %s add-on %s (%s plan, version %s) for %s...\n", addon.Type, addon.Name, addon.Plan, addon.Version, addon.App)
+            fmt.Printf("%s will be injected into %s on its next deploy. Deploy with: ghaymah deploy\n", addon.EnvVar, addon.App)
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&options.Plan, "plan", "hobby", "Plan of the add-on (see 'ghaymah addons plans')")
+    cmd.Flags().StringVar(&options.EnvVar, "env-var", "", "Environment variable holding the connection URL (default: DATABASE_URL, REDIS_URL or MONGODB_URI)")
+    cmd.Flags().StringVar(&appName, "name", "", "Application the add-on is attached to (defaults to the config file or linked app)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("plan", completeAddonPlans(api))
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    return cmd
+}
+
+// newAddonsListCommand creates the addons list subcommand
+func newAddonsListCommand(api *api.GhaymahAPI) *cobra.Command {
+    var appName string
+
+    cmd := &cobra.Command{
+        Use:   "list",
+        Short: "List add-ons",
+        Args:  cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            addons, err := api.ListAddons(appName)
+            if err != nil {
+                return fmt.Errorf("failed to list add-ons: %v", err)
+            }
+            if len(addons.Addons) == 0 {
+                fmt.Println("No add-ons found")
+                return nil
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "NAME\tTYPE\tPLAN\tAPP\tENV VAR\tSTATE\tAGE")
+            for _, addon := range addons.Addons {
+                fmt.Fprintf(w, "%s\t%s %s\t%s\t%s\t%s\t%s\t%s\n",
+                    addon.Name,
+                    addon.Type,
+                    addon.Version,
+                    addon.Plan,
+                    addon.App,
+                    addon.EnvVar,
+                    addon.State,
+                    formatAge(addon.CreatedAt),
+                )
+            }
+            return w.Flush()
+        },
+    }
+
+    cmd.Flags().StringVar(&appName, "name", "", "Only list the add-ons of this application")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+
+    return cmd
+}
+
+// newAddonsInfoCommand creates the addons info subcommand
+func newAddonsInfoCommand(api *api.GhaymahAPI) *cobra.Command {
+    var showCredentials bool
+
+    cmd := &cobra.Command{
+        Use:               "info ADDON",
+        Short:             "Show an add-on and its connection URL",
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAddonNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            addon, err := api.GetAddon(args[0])
+            if err != nil {
+                return addonError("get", args[0], err)
+            }
+
+            connection := addon.URL
+            if !showCredentials {
+                connection = maskPassword(connection)
+            }
+
+            fmt.Printf("Name: %s\n", addon.Name)
+            fmt.Printf("Type: %s %s\n", addon.Type, addon.Version)
+            fmt.Printf("Plan: %s\n", addon.Plan)
+            if addon.Region != "" {
+                fmt.Printf("Region: %s\n", addon.Region)
+            }
+            fmt.Printf("State: %s\n", addon.State)
+            fmt.Printf("Attached to: %s as %s\n", addon.App, addon.EnvVar)
+            fmt.Printf("Connection URL: %s\n", connection)
+            fmt.Printf("Created: %s\n", addon.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+            if addon.CredentialsRotated != nil {
+                fmt.Printf("Credentials rotated: %s\n", addon.CredentialsRotated.Local().Format("2006-01-02 15:04:05"))
+            }
+            return nil
+        },
+    }
+
+    cmd.Flags().BoolVar(&showCredentials, "show-credentials", false, "Show the password in the connection URL")
+
+    return cmd
+}
+
+// newAddonsDestroyCommand creates the addons destroy subcommand
+func newAddonsDestroyCommand(api *api.GhaymahAPI) *cobra.Command {
+    var yes bool
+
+    cmd := &cobra.Command{
+        Use:               "destroy ADDON",
+        Short:             "Destroy an add-on with its data and backups",
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAddonNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if err := confirmAddon(args[0], fmt.Sprintf("Destroying %s deletes its data and backups", args[0]), yes); err != nil {
+                return err
+            }
+
+            if err := api.DestroyAddon(args[0]); err != nil {
+                return addonError("destroy", args[0], err)
+            }
+
+            fmt.Printf("Add-on %s destroyed. Its variable is removed from the application on its next deploy\n", args[0])
+            return nil
+        },
+    }
+
+    cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Don't ask for confirmation")
+
+    return cmd
+}
+
+// newAddonsRotateCommand creates the addons rotate subcommand
+func newAddonsRotateCommand(api *api.GhaymahAPI) *cobra.Command {
+    return &cobra.Command{
+        Use:   "rotate ADDON",
+        Short: "Rotate the credentials of an add-on",
+        Long: `Replace the password of an add-on. The attached application is restarted one
+instance at a time with the new connection URL, and the old password stops
+working once the restart completes.`,
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAddonNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            addon, err := api.RotateAddonCredentials(args[0])
+            if err != nil {
+                return addonError("rotate the credentials of", args[0], err)
+            }
+
+            fmt.Printf("Credentials of %s rotated. %s is restarting with the new %s\n", addon.Name, addon.App, addon.EnvVar)
+            fmt.Printf("Follow the restart with: ghaymah status %s\n", addon.App)
+            return nil
+        },
+    }
+}
+
+// newAddonsBackupsCommand creates the addons backups subcommand
+func newAddonsBackupsCommand(api *api.GhaymahAPI) *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "backups",
+        Short: "Manage the backups of an add-on",
+        Long:  "Manage the backups of an add-on. Add-ons are also backed up daily, and backups are kept for the number of days of their plan.",
+    }
+
+    list := &cobra.Command{
+        Use:               "list ADDON",
+        Short:             "List the backups of an add-on",
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAddonNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            backups, err := api.ListAddonBackups(args[0])
+            if err != nil {
+                return addonError("list the backups of", args[0], err)
+            }
+            if len(backups.Backups) == 0 {
+                fmt.Printf("No backups of %s yet\n", args[0])
+                return nil
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "ID\tTRIGGER\tSIZE\tCREATED")
+            for _, backup := range backups.Backups {
+                fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+                    backup.ID,
+                    backup.Trigger,
+                    formatBytes(backup.SizeBytes),
+                    backup.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+                )
+            }
+            return w.Flush()
+        },
+    }
+
+    create := &cobra.Command{
+        Use:               "create ADDON",
+        Short:             "Take a backup of an add-on",
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAddonNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            backup, err := api.CreateAddonBackup(args[0])
+            if err != nil {
+                return addonError("back up", args[0], err)
+            }
+
+            fmt.Printf("Backup %s of %s created (%s)\n", backup.ID, backup.Addon, formatBytes(backup.SizeBytes))
+            return nil
+        },
+    }
+
+    var (
+        backupID string
+        yes      bool
+    )
+    restore := &cobra.Command{
+        Use:               "restore ADDON",
+        Short:             "Replace the data of an add-on with a backup",
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAddonNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if err := confirmAddon(args[0], fmt.Sprintf("Restoring backup %s replaces the current data of %s", backupID, args[0]), yes); err != nil {
+                return err
+            }
+
+            addon, err := api.RestoreAddonBackup(args[0], backupID)
+            if err != nil {
+                return addonError("restore", args[0], err)
+            }
+
+            fmt.Printf("Restoring %s from backup %s (state: %s)\n", addon.Name, backupID, addon.State)
+            return nil
+        },
+    }
+    restore.Flags().StringVar(&backupID, "backup", "", "ID of the backup to restore (see 'ghaymah addons backups list')")
+    restore.Flags().BoolVarP(&yes, "yes", "y", false, "Don't ask for confirmation")
+    restore.MarkFlagRequired("backup")
+
+    cmd.AddCommand(list, create, restore)
+
+    return cmd
+}
+
+// isAddonType reports whether t is a known add-on type
+func isAddonType(t string) bool {
+    for _, addonType := range types.AddonTypes {
+        if t == addonType {
+            return true
+        }
+    }
+    return false
+}
+
+// maskPassword hides the password of a connection URL
+func maskPassword(connection string) string {
+    u, err := url.Parse(connection)
+    if err != nil || u.User == nil {
+        return connection
+    }
+    if _, ok := u.User.Password(); ok {
+        u.User = url.UserPassword(u.User.Username(), "xxxxx")
+    }
+    return u.String()
+}
+
+// confirmAddon asks to type the name of an add-on before a destructive action
+func confirmAddon(name, warning string, yes bool) error {
+    if yes {
+        return nil
+    }
+    if !isTerminal(os.Stdin) {
+        return fmt.Errorf("%s, confirm with --yes", warning)
+    }
+
+    fmt.Printf("%s. Type %s to confirm: ", warning, name)
+    line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+    if strings.TrimSpace(line) != name {
+        return fmt.Errorf("aborted")
+    }
+    return nil
+}
+
+// addonError explains why the API rejected an action on an add-on
+func addonError(action, name string, err error) error {
+    addon := "add-on"
+    if name != "" {
+        addon += " " + name
+    }
+    switch {
+    case api.IsStatus(err, http.StatusNotFound):
+        return fmt.Errorf("%s", api.StatusMessage(err))
+    case api.IsStatus(err, http.StatusBadRequest), api.IsStatus(err, http.StatusConflict):
+        return fmt.Errorf("can't %s %s: %s", action, addon, api.StatusMessage(err))
+    }
+    return fmt.Errorf("failed to %s %s: %v", action, addon, err)
+}
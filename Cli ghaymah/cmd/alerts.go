@@ -0,0 +1,199 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "text/tabwriter"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// NewAlertsCommand creates a new alerts command
+func NewAlertsCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "alerts",
+        Short: "Manage alert rules on application state and resource usage",
+        Long: `Get notified when an application goes unhealthy or runs out of resources.
+An alert rule watches a condition on an application and notifies its channels
+(see 'ghaymah notifications') when the condition holds for the --for duration,
+and again when it resolves.
+
+Conditions compare a metric with an operator (>, >=, <, <=, ==, !=):
+  cpu, memory, storage   usage in percent of the limit, e.g. "memory >= 90"
+  state                  application state, e.g. "state != running"
+  health                 health checks (healthy, degraded, unhealthy), e.g. "health == unhealthy"
+
+The application name can be given as an argument or with --name. When omitted, it is
+read from appName in the config file, or from the app linked with 'ghaymah link'.
+
+Examples:
+  # Page the on-call channel when the app is unhealthy for 2 minutes
+  ghaymah alerts create my-app --condition "health == unhealthy" --for 2m --channel oncall
+
+  # Warn when CPU usage stays above 80% for 10 minutes
+  ghaymah alerts create my-app --condition "cpu > 80" --for 10m --channel ops-slack
+
+  # List alert rules of the app, or of all apps
+  ghaymah alerts list my-app
+  ghaymah alerts list --all
+
+  # Send a sample notification to check the channels of a rule
+  ghaymah alerts test alert-1
+
+  # Delete a rule
+  ghaymah alerts delete alert-1`,
+    }
+
+    cmd.PersistentFlags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.PersistentFlags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    resolve := func(args []string) (string, error) {
+        return resolveAppName(args, appName, configPath, envName)
+    }
+
+    var (
+        condition string
+        duration  string
+        channels  []string
+        all       bool
+    )
+
+    createCmd := &cobra.Command{
+        Use:               "create [APP_NAME]",
+        Short:             "Create an alert rule",
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve(args)
+            if err != nil {
+                return err
+            }
+
+            rule := &types.AlertRule{App: name, For: duration, Channels: channels}
+            if err := rule.ParseCondition(condition); err != nil {
+                return err
+            }
+            if err := rule.Validate(); err != nil {
+                return err
+            }
+
+            created, err := api.CreateAlert(rule)
+            if err != nil {
+                return fmt.Errorf("failed to create alert: %v", err)
+            }
+
+            fmt.Printf("Alert %s created: notify %s when %s", created.ID, strings.Join(created.Channels, ", "), created.Condition())
+            if created.For != "" {
+                fmt.Printf(" for %s", created.For)
+            }
+            fmt.Println()
+            fmt.Printf("Check the channels with: ghaymah alerts test %s\n", created.ID)
+            return nil
+        },
+    }
+    createCmd.Flags().StringVar(&condition, "condition", "", "Condition firing the alert, e.g. \"cpu > 80\" or \"health == unhealthy\"")
+    createCmd.Flags().StringVar(&duration, "for", "", "How long the condition must hold before firing, e.g. 5m (default: immediately)")
+    createCmd.Flags().StringSliceVar(&channels, "channel", nil, "Notification channel to notify (repeatable)")
+    createCmd.MarkFlagRequired("condition")
+    createCmd.MarkFlagRequired("channel")
+    createCmd.RegisterFlagCompletionFunc("channel", completeNotificationChannels(api))
+
+    listCmd := &cobra.Command{
+        Use:               "list [APP_NAME]",
+        Short:             "List alert rules",
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name := ""
+            if !all {
+                var err error
+                if name, err = resolve(args); err != nil {
+                    return err
+                }
+            } else if len(args) > 0 || appName != "" {
+                return fmt.Errorf("--all can't be combined with an application name")
+            }
+
+            alerts, err := api.ListAlerts(name)
+            if err != nil {
+                return fmt.Errorf("failed to list alerts: %v", err)
+            }
+
+            if len(alerts.Alerts) == 0 {
+                fmt.Println("No alert rules found")
+                return nil
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "ID\tAPP\tCONDITION\tFOR\tCHANNELS\tSTATE")
+            for _, rule := range alerts.Alerts {
+                forDuration, state := "-", "-"
+                if rule.For != "" {
+                    forDuration = rule.For
+                }
+                if rule.State != "" {
+                    state = rule.State
+                }
+                fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", rule.ID, rule.App, rule.Condition(), forDuration, strings.Join(rule.Channels, ","), state)
+            }
+            return w.Flush()
+        },
+    }
+    listCmd.Flags().BoolVar(&all, "all", false, "List alert rules of all applications")
+
+    deleteCmd := &cobra.Command{
+        Use:   "delete ALERT_ID",
+        Short: "Delete an alert rule",
+        Args:  cobra.ExactArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if err := api.DeleteAlert(args[0]); err != nil {
+                return fmt.Errorf("failed to delete alert: %v", err)
+            }
+
+            fmt.Printf("Alert %s deleted\n", args[0])
+            return nil
+        },
+    }
+
+    testCmd := &cobra.Command{
+        Use:          "test ALERT_ID",
+        Short:        "Send a sample notification to the channels of an alert rule",
+        Args:         cobra.ExactArgs(1),
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            result, err := api.TestAlert(args[0])
+            if err != nil {
+                return fmt.Errorf("failed to test alert: %v", err)
+            }
+
+            failed := 0
+            for _, delivery := range result.Deliveries {
+                if delivery.Delivered {
+                    fmt.Printf("Sample notification delivered to %s\n", delivery.Channel)
+                    continue
+                }
+                fmt.Printf("Sample notification to %s failed: %s\n", delivery.Channel, delivery.Error)
+                failed++
+            }
+            if failed > 0 {
+                return fmt.Errorf("%d of %d channels failed", failed, len(result.Deliveries))
+            }
+            return nil
+        },
+    }
+
+    cmd.AddCommand(createCmd, listCmd, deleteCmd, testCmd)
+
+    return cmd
+}
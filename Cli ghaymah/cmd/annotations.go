@@ -0,0 +1,21 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// offlineAnnotation marks commands that work without API credentials
+const offlineAnnotation = "ghaymah/offline"
+
+// RequiresAPI reports whether a command needs the API environment variables.
+// Offline commands (and their subcommands), help and shell completion requests don't.
+func RequiresAPI(c *cobra.Command) bool {
+    switch c.Name() {
+    case "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+        return false
+    }
+    for ; c != nil; c = c.Parent() {
+        if c.Annotations[offlineAnnotation] == "true" {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/config"
+    "ghaymah-cli/pkg/types"
+)
+
+// defaultConfigFile is the configuration file written by init
+const defaultConfigFile = "ghaymah.yaml"
+
+// findConfigFile returns the configuration file to use: the given path when
+// set, otherwise the first ghaymah.{yaml,yml,json,toml} found in the current
+// or a parent directory
+func findConfigFile(path string) (string, error) {
+    if path == "" {
+        return config.Discover(".")
+    }
+    if _, err := os.Stat(path); err != nil {
+        if errors.Is(err, os.ErrNotExist) {
+            return "", fmt.Errorf("config file %s not found", path)
+        }
+        return "", err
+    }
+    return path, nil
+}
+
+// resolveAppName determines the application a command acts on. In order of
+// precedence: the positional argument, the --name flag, appName in the config
+// file (given or discovered) with the settings of envName merged onto it, and
+// the .ghaymah/app link file of the current or a parent directory.
+func resolveAppName(args []string, nameFlag, configPath, envName string) (string, error) {
+    if len(args) > 0 {
+        if nameFlag != "" && nameFlag != args[0] {
+            return "", fmt.Errorf("application name given both as argument (%s) and --name (%s)", args[0], nameFlag)
+        }
+        return args[0], nil
+    }
+
+    if nameFlag != "" {
+        return nameFlag, nil
+    }
+
+    // No config file is fine when discovering, a missing explicit one is not
+    path, err := findConfigFile(configPath)
+    if err == nil {
+        cfg := &config.Config{}
+        if err := cfg.LoadEnvironment(path, envName); err != nil {
+            return "", fmt.Errorf("failed to load config: %v", err)
+        }
+        if cfg.AppName != "" {
+            return cfg.AppName, nil
+        }
+    } else if !errors.Is(err, config.ErrNotFound) || envName != "" {
+        return "", err
+    }
+
+    name, _, err := config.FindLinkedApp(".")
+    if err == nil {
+        return name, nil
+    }
+    if !errors.Is(err, config.ErrNoLink) {
+        return "", err
+    }
+
+    return "", fmt.Errorf("application name is required. Pass it as an argument, use --name, set appName in the config file or run 'ghaymah link'")
+}
+
+// parseSelector parses a label selector of the form key=value[,key=value...]
+func parseSelector(selector string) (map[string]string, error) {
+    labels := map[string]string{}
+    for _, part := range strings.Split(selector, ",") {
+        key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+        if !ok || key == "" {
+            return nil, fmt.Errorf("invalid selector %q, expected key=value[,key=value...]", selector)
+        }
+        labels[key] = value
+    }
+    return labels, nil
+}
+
+// listAppNames returns the names of the applications matching a label
+// selector, or of all applications when the selector is empty
+func listAppNames(api *api.GhaymahAPI, selector map[string]string) ([]string, error) {
+    var (
+        resp *types.AppsResponse
+        err  error
+    )
+    if len(selector) > 0 {
+        resp, err = api.ListAppsByLabel(selector)
+    } else {
+        resp, err = api.ListApps()
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    names := make([]string, 0, len(resp.Apps))
+    for _, app := range resp.Apps {
+        names = append(names, app.Name)
+    }
+    sort.Strings(names)
+    return names, nil
+}
+
+// selectApps returns the applications a command acting on several of them
+// applies to: the names given, the applications matching a label selector,
+// or else the one resolved from the config file or linked app
+func selectApps(api *api.GhaymahAPI, names []string, selector, configPath, envName string) ([]string, error) {
+    if len(names) > 0 && selector != "" {
+        return nil, fmt.Errorf("application names and --selector can't be combined")
+    }
+
+    if selector != "" {
+        labels, err := parseSelector(selector)
+        if err != nil {
+            return nil, err
+        }
+        names, err := listAppNames(api, labels)
+        if err != nil {
+            return nil, fmt.Errorf("failed to list applications: %v", err)
+        }
+        if len(names) == 0 {
+            return nil, fmt.Errorf("no application matches %s", selector)
+        }
+        return names, nil
+    }
+
+    if len(names) == 0 {
+        name, err := resolveAppName(nil, "", configPath, envName)
+        if err != nil {
+            return nil, err
+        }
+        return []string{name}, nil
+    }
+
+    // Keep the given order, without acting twice on the same application
+    seen := map[string]bool{}
+    unique := make([]string, 0, len(names))
+    for _, name := range names {
+        if !seen[name] {
+            seen[name] = true
+            unique = append(unique, name)
+        }
+    }
+    return unique, nil
+}
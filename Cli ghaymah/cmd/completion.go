@@ -0,0 +1,273 @@
+package cmd
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/config"
+)
+
+// completionCacheTTL is how long suggestions fetched from the API are reused
+const completionCacheTTL = time.Minute
+
+// NewCompletionCommand creates a new completion command
+func NewCompletionCommand() *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "completion bash|zsh|fish|powershell",
+        Short: "Generate shell completion scripts",
+        Long: `Generate the autocompletion script for your shell.
+Application names and regions are suggested from your Ghaymah Cloud account.
+
+Examples:
+  # Bash (current session)
+  source <(ghaymah completion bash)
+
+  # Bash (permanently, Linux)
+  ghaymah completion bash > /etc/bash_completion.d/ghaymah
+
+  # Zsh
+  ghaymah completion zsh > "${fpath[1]}/_ghaymah"
+
+  # Fish
+  ghaymah completion fish > ~/.config/fish/completions/ghaymah.fish
+
+  # PowerShell
+  ghaymah completion powershell | Out-String | Invoke-Expression`,
+        Args:                  cobra.ExactValidArgs(1),
+        ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+        DisableFlagsInUseLine: true,
+        Annotations:           map[string]string{offlineAnnotation: "true"},
+        RunE: func(cmd *cobra.Command, args []string) error {
+            root := cmd.Root()
+            switch args[0] {
+            case "bash":
+                return root.GenBashCompletionV2(os.Stdout, true)
+            case "zsh":
+                return root.GenZshCompletion(os.Stdout)
+            case "fish":
+                return root.GenFishCompletion(os.Stdout, true)
+            case "powershell":
+                return root.GenPowerShellCompletionWithDesc(os.Stdout)
+            }
+            return fmt.Errorf("unsupported shell %q", args[0])
+        },
+    }
+
+    return cmd
+}
+
+// completeAppNames suggests application names from the account
+func completeAppNames(api *api.GhaymahAPI) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+    return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        names, err := cachedCompletions("apps", func() ([]string, error) {
+            apps, err := api.ListApps()
+            if err != nil {
+                return nil, err
+            }
+            names := make([]string, 0, len(apps.Apps))
+            for _, app := range apps.Apps {
+                names = append(names, app.Name+"\t"+app.State)
+            }
+            return names, nil
+        })
+        if err != nil {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+    }
+}
+
+// completeRegions suggests the regions applications can be deployed to
+func completeRegions(api *api.GhaymahAPI) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+    return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        regions, err := cachedCompletions("regions", func() ([]string, error) {
+            resp, err := api.ListRegions()
+            if err != nil {
+                return nil, err
+            }
+            regions := make([]string, 0, len(resp.Regions))
+            for _, region := range resp.Regions {
+                regions = append(regions, region.Name+"\t"+region.DisplayName)
+            }
+            return regions, nil
+        })
+        if err != nil {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        return filterCompletions(regions, toComplete), cobra.ShellCompDirectiveNoFileComp
+    }
+}
+
+// completeEnvironments suggests the environments of the config file given
+// with -c or discovered
+func completeEnvironments(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+    configPath, _ := cmd.Flags().GetString("config")
+    path, err := findConfigFile(configPath)
+    if err != nil {
+        return nil, cobra.ShellCompDirectiveNoFileComp
+    }
+    names, err := config.EnvironmentNames(path)
+    if err != nil {
+        return nil, cobra.ShellCompDirectiveNoFileComp
+    }
+    return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeNotificationChannels suggests the notification channels of the account
+func completeNotificationChannels(api *api.GhaymahAPI) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+    return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        channels, err := api.ListNotificationChannels()
+        if err != nil {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        names := make([]string, 0, len(channels.Channels))
+        for _, channel := range channels.Channels {
+            names = append(names, channel.Name+"\t"+channel.Type)
+        }
+        return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+    }
+}
+
+// completeJobNames suggests the scheduled jobs of the application given with
+// --name, or of the config file or linked app
+func completeJobNames(api *api.GhaymahAPI) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+    return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        nameFlag, _ := cmd.Flags().GetString("name")
+        configPath, _ := cmd.Flags().GetString("config")
+        envName, _ := cmd.Flags().GetString("env-name")
+        appName, err := resolveAppName(nil, nameFlag, configPath, envName)
+        if err != nil {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        jobs, err := api.ListJobs(appName)
+        if err != nil {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        names := make([]string, 0, len(jobs.Jobs))
+        for _, job := range jobs.Jobs {
+            names = append(names, job.Name+"\t"+job.Schedule)
+        }
+        return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+    }
+}
+
+// completeVolumeNames suggests the volumes of the account
+func completeVolumeNames(api *api.GhaymahAPI) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+    return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        if len(args) > 0 {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        volumes, err := api.ListVolumes()
+        if err != nil {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        names := make([]string, 0, len(volumes.Volumes))
+        for _, volume := range volumes.Volumes {
+            names = append(names, volume.Name+"\t"+volume.Size)
+        }
+        return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+    }
+}
+
+// completeAddonNames suggests the add-ons of the account
+func completeAddonNames(api *api.GhaymahAPI) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+    return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        if len(args) > 0 {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        addons, err := api.ListAddons("")
+        if err != nil {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        names := make([]string, 0, len(addons.Addons))
+        for _, addon := range addons.Addons {
+            names = append(names, addon.Name+"\t"+addon.Type+" for "+addon.App)
+        }
+        return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+    }
+}
+
+// completeAddonPlans suggests the plans of the add-on type given as argument
+func completeAddonPlans(api *api.GhaymahAPI) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+    return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        if len(args) == 0 {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        plans, err := api.ListAddonPlans(args[0])
+        if err != nil {
+            return nil, cobra.ShellCompDirectiveNoFileComp
+        }
+        names := make([]string, 0, len(plans.Plans))
+        for _, plan := range plans.Plans {
+            names = append(names, plan.Name+"\t"+plan.Memory+" memory, "+plan.Storage+" storage")
+        }
+        return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+    }
+}
+
+// filterCompletions keeps the suggestions starting with the typed prefix
+func filterCompletions(candidates []string, toComplete string) []string {
+    var out []string
+    for _, c := range candidates {
+        if strings.HasPrefix(c, toComplete) {
+            out = append(out, c)
+        }
+    }
+    return out
+}
+
+// completionCache is the on-disk format of cached suggestions
+type completionCache struct {
+    FetchedAt time.Time `json:"fetchedAt"`
+    Values    []string  `json:"values"`
+}
+
+// cachedCompletions returns suggestions from the on-disk cache, calling fetch
+// when the cache is missing or older than completionCacheTTL. The cache is
+// keyed by API URL so different accounts don't share suggestions.
+func cachedCompletions(kind string, fetch func() ([]string, error)) ([]string, error) {
+    path := completionCachePath(kind)
+
+    if path != "" {
+        if data, err := os.ReadFile(path); err == nil {
+            var cache completionCache
+            if json.Unmarshal(data, &cache) == nil && time.Since(cache.FetchedAt) < completionCacheTTL {
+                return cache.Values, nil
+            }
+        }
+    }
+
+    values, err := fetch()
+    if err != nil {
+        return nil, err
+    }
+
+    if path != "" {
+        if data, err := json.Marshal(completionCache{FetchedAt: time.Now(), Values: values}); err == nil {
+            if os.MkdirAll(filepath.Dir(path), 0o700) == nil {
+                os.WriteFile(path, data, 0o600)
+            }
+        }
+    }
+
+    return values, nil
+}
+
+// completionCachePath returns the cache file for a kind of suggestion. The
+// file is keyed by the API URL and token, so accounts don't share suggestions.
+func completionCachePath(kind string) string {
+    dir, err := os.UserCacheDir()
+    if err != nil {
+        return ""
+    }
+    sum := sha256.Sum256([]byte(os.Getenv(config.APIURLEnvVar) + "\n" + os.Getenv(config.APITokenEnvVar)))
+    key := hex.EncodeToString(sum[:8])
+    return filepath.Join(dir, "ghaymah", "completion", fmt.Sprintf("%s-%s.json", kind, key))
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "strings"
+    "github.com/spf13/cobra"
+    "gopkg.in/yaml.v3"
+    "ghaymah-cli/pkg/config"
+)
+
+// NewConfigCommand creates a new config command
+func NewConfigCommand() *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "config",
+        Short: "Work with deploy configuration files",
+        Long: `Inspect and validate deploy configuration files without contacting Ghaymah Cloud.
+
+Examples:
+  # Print the JSON Schema for editor integration
+  ghaymah config schema > ghaymah.schema.json
+
+  # Validate the configuration file of the current project
+  ghaymah config validate
+
+  # Validate several files (e.g., from a pre-commit hook)
+  ghaymah config validate staging.yaml production.yaml
+
+  # Print the configuration deployed to production
+  ghaymah config render --env-name production`,
+        Annotations: map[string]string{offlineAnnotation: "true"},
+    }
+
+    cmd.AddCommand(
+        newConfigSchemaCommand(),
+        newConfigValidateCommand(),
+        newConfigRenderCommand(),
+    )
+
+    return cmd
+}
+
+// newConfigSchemaCommand creates the config schema subcommand
+func newConfigSchemaCommand() *cobra.Command {
+    return &cobra.Command{
+        Use:   "schema",
+        Short: "Print the JSON Schema of the configuration file",
+        Long: `Print the JSON Schema of the configuration file.
+Editors such as VS Code (with the YAML extension) can use it for completion and
+validation by adding this line at the top of ghaymah.yaml:
+
+  # yaml-language-server: $schema=./ghaymah.schema.json`,
+        Args: cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            data, err := json.MarshalIndent(config.Schema(), "", "  ")
+            if err != nil {
+                return fmt.Errorf("failed to generate schema: %v", err)
+            }
+            fmt.Println(string(data))
+            return nil
+        },
+    }
+}
+
+// newConfigValidateCommand creates the config validate subcommand
+func newConfigValidateCommand() *cobra.Command {
+    return &cobra.Command{
+        Use:   "validate [FILE...]",
+        Short: "Validate configuration files",
+        Long: `Validate configuration files against the schema and the rules applied on deploy.
+Problems are reported as file:line:column: message, and the command exits with
+a non-zero status if any file is invalid. Duplicate keys are reported as
+warnings, as on deploy, and don't make a file invalid. YAML, JSON and TOML files are
+supported. Without arguments, the configuration file found in the current or a
+parent directory is validated.`,
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            files := args
+            if len(files) == 0 {
+                path, err := findConfigFile("")
+                if err != nil {
+                    return err
+                }
+                files = []string{path}
+            }
+
+            invalid := 0
+            for _, file := range files {
+                problems, err := config.ValidateFile(file)
+                if err != nil {
+                    fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+                    invalid++
+                    continue
+                }
+                for _, p := range problems {
+                    if p.Line > 0 {
+                        fmt.Fprintf(os.Stderr, "%s:%s\n", file, p.Error())
+                    } else {
+                        fmt.Fprintf(os.Stderr, "%s: %s\n", file, p.Error())
+                    }
+                }
+                if len(config.Invalid(problems)) > 0 {
+                    invalid++
+                    continue
+                }
+                fmt.Printf("%s: valid\n", file)
+            }
+
+            if invalid > 0 {
+                return fmt.Errorf("%d of %d files are invalid", invalid, len(files))
+            }
+            return nil
+        },
+    }
+}
+
+// newConfigRenderCommand creates the config render subcommand
+func newConfigRenderCommand() *cobra.Command {
+    var (
+        configPath string
+        envName    string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "render",
+        Short: "Print the configuration used on deploy",
+        Long: `Print the configuration deploy would use, as YAML. With --env-name, the settings
+of that environment are merged onto the file: first those under environments:,
+then those of the overlay file next to it (e.g., ghaymah.production.yaml).
+Mappings are merged key by key; lists and other values replace the base value.
+The result is validated like the config file before it is printed.`,
+        Args:         cobra.NoArgs,
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            path, err := findConfigFile(configPath)
+            if err != nil {
+                return err
+            }
+
+            cfg := &config.Config{}
+            if err := cfg.LoadEnvironment(path, envName); err != nil {
+                return fmt.Errorf("failed to load config: %v", err)
+            }
+            for _, warning := range cfg.Warnings() {
+                fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+            }
+
+            data, err := yaml.Marshal(cfg)
+            if err != nil {
+                return fmt.Errorf("failed to render config: %v", err)
+            }
+
+            // Overlays that are valid on their own can still be invalid once merged
+            if problems := config.Invalid(config.ValidateData(data, config.FormatYAML)); len(problems) > 0 {
+                messages := make([]string, len(problems))
+                for i, p := range problems {
+                    messages[i] = p.Message
+                    if p.Path != "" {
+                        messages[i] = p.Path + ": " + p.Message
+                    }
+                }
+                return fmt.Errorf("rendered config is invalid:\n  %s", strings.Join(messages, "\n  "))
+            }
+            fmt.Print(string(data))
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    return cmd
+}
@@ -2,22 +2,30 @@ package cmd
 
 import (
     "fmt"
+    "net/http"
+    "os"
     "strings"
     "github.com/spf13/cobra"
     "ghaymah-cli/pkg/api"
     "ghaymah-cli/pkg/config"
+    "ghaymah-cli/pkg/types"
 )
 
 var (
     configFile string
     imageName  string
     appName    string
+    region     string
+    envName    string
+
+    expectedVersion int
 )
 
 // DeployCommand handles application deployment
 type DeployCommand struct {
-    config *config.Config
-    api    api.GhaymahAPI
+    config          *config.Config
+    api             api.GhaymahAPI
+    expectedVersion *int
 }
 
 // NewDeployCommand creates a new deploy command
@@ -31,21 +39,41 @@ func NewDeployCommand(api *api.GhaymahAPI) *cobra.Command {
         Short: "Deploy an application to Ghaymah Cloud",
         Long: `Deploy your application to Ghaymah Cloud platform.
 You can deploy either using a configuration file or directly using flags.
+Without -c, the first ghaymah.yaml, ghaymah.yml, ghaymah.json or ghaymah.toml
+found in the current or a parent directory is used. With --env-name, the
+settings of that environment (under environments: or in an overlay file such
+as ghaymah.production.yaml) are merged onto the file.
+
+The deployment is rejected if the application is locked ('ghaymah lock'), or if
+another release was deployed between reading the current release and deploying,
+so concurrent deploys of the same application can't overwrite each other.
 
 Examples:
   # Deploy using config file
   ghaymah deploy -c config.yaml
 
+  # Deploy the production settings of the config file
+  ghaymah deploy --env-name production
+
   # Deploy using image
   ghaymah deploy --image username/app:tag
 
   # Deploy using image and custom name
-  ghaymah deploy --image username/app:tag --name my-app`,
+  ghaymah deploy --image username/app:tag --name my-app
+
+  # Deploy to a specific region (see 'ghaymah regions')
+  ghaymah deploy --image username/app:tag --region us-east-1
+
+  # Only deploy if release v12 is still the current one (e.g., the one reviewed)
+  ghaymah deploy --expected-version 12`,
         RunE: func(cmd *cobra.Command, args []string) error {
             var cfg *config.Config
 
             // If image is provided, create config from flags
             if imageName != "" {
+                if envName != "" {
+                    return fmt.Errorf("--env-name requires a configuration file and can't be used with --image")
+                }
                 cfg = &config.Config{
                     AppName: appName,
                     Image:   imageName,
@@ -56,51 +84,144 @@ Examples:
                 }
             } else {
                 // Load from config file
+                path, err := findConfigFile(configFile)
+                if err != nil {
+                    return err
+                }
+                if envName != "" {
+                    fmt.Printf("Using config file %s (environment %s)\n", path, envName)
+                } else {
+                    fmt.Printf("Using config file %s\n", path)
+                }
+
                 cfg = &config.Config{}
-                if err := cfg.LoadFromFile(configFile); err != nil {
+                if err := cfg.LoadEnvironment(path, envName); err != nil {
                     return fmt.Errorf("failed to load config: %v", err)
                 }
+                for _, warning := range cfg.Warnings() {
+                    fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+                }
+            }
+
+            // Region flag overrides the config file, including its regions
+            if region != "" {
+                cfg.Region = region
+                cfg.Regions = nil
             }
 
             deployCmd.config = cfg
+            deployCmd.expectedVersion = nil
+            if cmd.Flags().Changed("expected-version") {
+                deployCmd.expectedVersion = &expectedVersion
+            }
 
             return deployCmd.Execute()
         },
     }
 
     // Add flags
-    cmd.Flags().StringVarP(&configFile, "config", "c", "ghaymah.yaml", "path to configuration file")
+    cmd.Flags().StringVarP(&configFile, "config", "c", "", "path to configuration file (YAML, JSON or TOML; default: discovered)")
     cmd.Flags().StringVar(&imageName, "image", "", "Docker image to deploy (e.g., username/app:tag)")
     cmd.Flags().StringVar(&appName, "name", "", "Application name (optional when using --image)")
+    cmd.Flags().StringVar(&region, "region", "", "Region to deploy to (overrides region and regions of the config file)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.Flags().IntVar(&expectedVersion, "expected-version", 0, "Release the deployment replaces (default: the current release; 0 for a new application)")
+
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("region", completeRegions(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
 
     return cmd
 }
 
 // Execute runs the deployment process
 func (d *DeployCommand) Execute() error {
-    if err := d.validateConfig(); err != nil {
+    expected, err := d.prepare()
+    if err != nil {
         return err
     }
 
-    fmt.Printf("Starting deployment of %s...\n", d.config.AppName)
+    if *expected > 0 {
+        fmt.Printf("Starting deployment of %s (replacing release v%d)...\n", d.config.AppName, *expected)
+    } else {
+        fmt.Printf("Starting deployment of %s...\n", d.config.AppName)
+    }
+    if len(d.config.Regions) > 1 {
+        fmt.Printf("Deploying to %d regions in parallel: %s\n", len(d.config.Regions), strings.Join(d.config.Regions, ", "))
+    }
 
     // Deploy application
-    resp, err := d.api.Deploy(d.config)
+    resp, err := d.api.Deploy(d.config, &types.DeployOptions{ExpectedVersion: expected})
     if err != nil {
-        return fmt.Errorf("deployment failed: %v", err)
+        return deployError("deployment", d.config.AppName, err)
     }
 
     fmt.Printf("Successfully deployed! Application ID: %s\n", resp.AppID)
+    for _, r := range resp.Regions {
+        fmt.Printf("  %s: %s %s\n", r.Region, r.State, r.URL)
+    }
+    if len(resp.Regions) > 0 {
+        fmt.Printf("Follow the regions with: ghaymah status %s\n", d.config.AppName)
+    }
+    if d.config.Strategy != nil {
+        fmt.Printf("Rolling out with the %s strategy. Follow it with: ghaymah rollout status %s --watch\n", d.config.Strategy.StrategyType(), d.config.AppName)
+    }
+    for _, binding := range resp.Addons {
+        if binding.Overridden {
+            fmt.Fprintf(os.Stderr, "Warning: envVars sets %s, overriding the connection URL of add-on %s. Remove it to use the add-on\n", binding.EnvVar, binding.Addon)
+            continue
+        }
+        fmt.Printf("Injected %s from %s add-on %s\n", binding.EnvVar, binding.Type, binding.Addon)
+    }
+    if len(d.config.Jobs) > 0 {
+        fmt.Printf("Scheduled %d job(s). List them with: ghaymah jobs list --name %s\n", len(d.config.Jobs), d.config.AppName)
+    }
     return nil
 }
 
+// prepare checks the configuration and its regions, and returns the release
+// the deployment replaces so a concurrent deploy or a lock is detected
+func (d *DeployCommand) prepare() (*int, error) {
+    if err := d.validateConfig(); err != nil {
+        return nil, err
+    }
+    if err := checkRegions(&d.api, d.config.DeployRegions()); err != nil {
+        return nil, err
+    }
+
+    if d.expectedVersion != nil {
+        return d.expectedVersion, nil
+    }
+    version, err := d.currentVersion()
+    if err != nil {
+        return nil, err
+    }
+    return &version, nil
+}
+
+// currentVersion returns the current release of the application, 0 if it
+// doesn't exist yet. Locked applications are reported before deploying.
+func (d *DeployCommand) currentVersion() (int, error) {
+    status, err := d.api.GetStatus(d.config.AppName)
+    if err != nil {
+        if api.IsStatus(err, http.StatusNotFound) {
+            return 0, nil
+        }
+        return 0, fmt.Errorf("failed to read the current release of %s: %v", d.config.AppName, err)
+    }
+    if status.Lock != nil {
+        return 0, lockedError(d.config.AppName, status.Lock)
+    }
+    return status.Version, nil
+}
+
 // validateConfig ensures all required configuration is present
 func (d *DeployCommand) validateConfig() error {
     if d.config == nil {
         return fmt.Errorf("configuration is required")
     }
-    if !d.config.Validate() {
-        return fmt.Errorf("invalid configuration")
+    if err := d.config.Validate(); err != nil {
+        return fmt.Errorf("invalid configuration: %v", err)
     }
     return nil
 }
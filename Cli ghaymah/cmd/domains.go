@@ -0,0 +1,158 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "text/tabwriter"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// NewDomainsCommand creates a new domains command
+func NewDomainsCommand(api *api.GhaymahAPI) *cobra.Command {
+    var appName string
+
+    cmd := &cobra.Command{
+        Use:   "domains",
+        Short: "Manage custom domains and TLS certificates",
+        Long: `Attach your own domains to an application on Ghaymah Cloud.
+After adding a domain, create the DNS records shown and run verify. Once the
+domain is verified, a managed TLS certificate is issued and renewed automatically.
+
+Examples:
+  # Attach a domain
+  ghaymah domains add www.example.com --name my-app
+
+  # List domains with verification and certificate status
+  ghaymah domains list --name my-app
+
+  # Check DNS records and issue the certificate
+  ghaymah domains verify www.example.com --name my-app
+
+  # Detach a domain
+  ghaymah domains remove www.example.com --name my-app`,
+    }
+
+    cmd.PersistentFlags().StringVar(&appName, "name", "", "Application name")
+    cmd.MarkPersistentFlagRequired("name")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+
+    cmd.AddCommand(
+        &cobra.Command{
+            Use:   "add DOMAIN",
+            Short: "Attach a custom domain",
+            Args:  cobra.ExactArgs(1),
+            RunE: func(cmd *cobra.Command, args []string) error {
+                domain, err := api.AddDomain(appName, args[0])
+                if err != nil {
+                    return fmt.Errorf("failed to add domain: %v", err)
+                }
+
+                fmt.Printf("Domain %s added to %s\n", domain.Name, appName)
+                printDNSRecords(domain)
+                fmt.Printf("\nOnce the records are in place, run: ghaymah domains verify %s --name %s\n", domain.Name, appName)
+                return nil
+            },
+        },
+        &cobra.Command{
+            Use:   "list",
+            Short: "List custom domains",
+            Args:  cobra.NoArgs,
+            RunE: func(cmd *cobra.Command, args []string) error {
+                domains, err := api.ListDomains(appName)
+                if err != nil {
+                    return fmt.Errorf("failed to list domains: %v", err)
+                }
+
+                if len(domains.Domains) == 0 {
+                    fmt.Println("No custom domains attached to the application")
+                    return nil
+                }
+
+                w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+                fmt.Fprintln(w, "DOMAIN\tSTATUS\tCERTIFICATE\tISSUER\tEXPIRES")
+                for _, d := range domains.Domains {
+                    certStatus, issuer, expires := "-", "-", "-"
+                    if d.Certificate != nil {
+                        certStatus = d.Certificate.Status
+                        if d.Certificate.Issuer != "" {
+                            issuer = d.Certificate.Issuer
+                        }
+                        if d.Certificate.ExpiresAt != nil {
+                            expires = d.Certificate.ExpiresAt.Format("2006-01-02")
+                        }
+                    }
+                    fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Name, d.Status, certStatus, issuer, expires)
+                }
+                return w.Flush()
+            },
+        },
+        &cobra.Command{
+            Use:   "remove DOMAIN",
+            Short: "Detach a custom domain",
+            Args:  cobra.ExactArgs(1),
+            RunE: func(cmd *cobra.Command, args []string) error {
+                if err := api.RemoveDomain(appName, args[0]); err != nil {
+                    return fmt.Errorf("failed to remove domain: %v", err)
+                }
+
+                fmt.Printf("Domain %s removed from %s\n", args[0], appName)
+                return nil
+            },
+        },
+        &cobra.Command{
+            Use:   "verify DOMAIN",
+            Short: "Verify DNS records and issue the TLS certificate",
+            Args:  cobra.ExactArgs(1),
+            RunE: func(cmd *cobra.Command, args []string) error {
+                domain, err := api.VerifyDomain(appName, args[0])
+                if err != nil {
+                    return fmt.Errorf("failed to verify domain: %v", err)
+                }
+
+                fmt.Printf("Domain: %s\n", domain.Name)
+                fmt.Printf("Status: %s\n", domain.Status)
+                if domain.VerifiedAt != nil {
+                    fmt.Printf("Verified: %s\n", domain.VerifiedAt.Format("2006-01-02 15:04:05"))
+                }
+                if domain.Message != "" {
+                    fmt.Printf("Message: %s\n", domain.Message)
+                }
+
+                if domain.Certificate != nil {
+                    fmt.Println("\nCertificate:")
+                    fmt.Printf("  Status: %s\n", domain.Certificate.Status)
+                    if domain.Certificate.Issuer != "" {
+                        fmt.Printf("  Issuer: %s\n", domain.Certificate.Issuer)
+                    }
+                    if domain.Certificate.ExpiresAt != nil {
+                        fmt.Printf("  Expires: %s\n", domain.Certificate.ExpiresAt.Format("2006-01-02 15:04:05"))
+                    }
+                }
+
+                if domain.Status != "verified" {
+                    printDNSRecords(domain)
+                }
+                return nil
+            },
+        },
+    )
+
+    return cmd
+}
+
+// printDNSRecords shows the DNS records required for a domain
+func printDNSRecords(domain *types.Domain) {
+    if len(domain.DNSRecords) == 0 {
+        return
+    }
+
+    fmt.Println("\nCreate the following DNS records:")
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "  TYPE\tNAME\tVALUE")
+    for _, r := range domain.DNSRecords {
+        fmt.Fprintf(w, "  %s\t%s\t%s\n", r.Type, r.Name, r.Value)
+    }
+    w.Flush()
+}
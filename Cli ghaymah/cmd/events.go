@@ -0,0 +1,183 @@
+package cmd
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "os/signal"
+    "strings"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// eventsPollInterval is how often events --follow checks for new events
+const eventsPollInterval = 2 * time.Second
+
+// NewEventsCommand creates a new events command
+func NewEventsCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+        since      string
+        until      string
+        eventTypes []string
+        limit      int
+        follow     bool
+        format     string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "events [APP_NAME]",
+        Short: "Show what happened to an application",
+        Long: `Show the activity of an application on Ghaymah Cloud: deploys, restarts,
+scaling, OOM kills, health check failures, configuration changes and locks,
+with the user who caused them, or "platform".
+
+--type takes an event type such as deploy.failed, or a category such as
+deploy for all deploy events. --since and --until take a duration before now
+(30m, 6h, 7d) or an RFC3339 timestamp.
+
+The application name can be given as an argument or with --name. When omitted, it is
+read from appName in the config file, or from the app linked with 'ghaymah link'.
+
+Examples:
+  # Events of the last 24 hours
+  ghaymah events my-app
+
+  # Deploys and OOM kills of the last week
+  ghaymah events my-app --since 7d --type deploy,instance.oom_killed
+
+  # Follow new events as they happen
+  ghaymah events my-app --follow
+
+  # Stream events as JSON lines for ingestion
+  ghaymah events my-app --follow --format json | my-ingester`,
+        Args:              cobra.MaximumNArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if format != "text" && format != "json" {
+                return fmt.Errorf("invalid format %q (expected text or json)", format)
+            }
+            if follow && until != "" {
+                return fmt.Errorf("--follow can't be combined with --until")
+            }
+
+            appName, err := resolveAppName(args, appName, configPath, envName)
+            if err != nil {
+                return err
+            }
+
+            now := time.Now()
+            options := &types.EventOptions{Types: eventTypes, Limit: limit}
+            if options.Since, err = parseTimeOrAgo(since, now); err != nil {
+                return fmt.Errorf("invalid --since: %v", err)
+            }
+            if until != "" {
+                if options.Until, err = parseTimeOrAgo(until, now); err != nil {
+                    return fmt.Errorf("invalid --until: %v", err)
+                }
+            }
+
+            events, err := api.GetEvents(appName, options)
+            if err != nil {
+                return fmt.Errorf("failed to get events: %v", err)
+            }
+
+            if len(events.Events) == 0 && format == "text" && !follow {
+                fmt.Println("No events found")
+                return nil
+            }
+            seen := map[string]bool{}
+            for _, event := range events.Events {
+                seen[event.ID] = true
+                printEvent(event, format)
+            }
+            if !follow {
+                return nil
+            }
+
+            ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+            defer stop()
+
+            // Events of the same second as the last one are fetched again, and skipped if already seen.
+            // No other event can be fetched again, so seen only keeps those.
+            options.Limit = 0
+            for {
+                if n := len(events.Events); n > 0 {
+                    options.Since = events.Events[n-1].Timestamp.Truncate(time.Second)
+                    seen = map[string]bool{}
+                    for _, event := range events.Events {
+                        if !event.Timestamp.Before(options.Since) {
+                            seen[event.ID] = true
+                        }
+                    }
+                }
+                select {
+                case <-ctx.Done():
+                    return nil
+                case <-time.After(eventsPollInterval):
+                }
+
+                latest, err := api.GetEvents(appName, options)
+                if err != nil {
+                    fmt.Fprintf(os.Stderr, "Failed to get events: %v\n", err)
+                    continue
+                }
+                if len(latest.Events) > 0 {
+                    events = latest
+                }
+                for _, event := range latest.Events {
+                    if !seen[event.ID] {
+                        seen[event.ID] = true
+                        printEvent(event, format)
+                    }
+                }
+            }
+        },
+    }
+
+    cmd.Flags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.Flags().StringVarP(&since, "since", "s", "24h", "Show events since a duration before now or an RFC3339 timestamp")
+    cmd.Flags().StringVar(&until, "until", "", "Show events before a duration before now or an RFC3339 timestamp")
+    cmd.Flags().StringSliceVarP(&eventTypes, "type", "t", nil, "Event types or categories to show, e.g. deploy,instance.oom_killed (default: all)")
+    cmd.Flags().IntVarP(&limit, "limit", "n", 100, "Maximum number of events, the most recent ones")
+    cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new events as they happen")
+    cmd.Flags().StringVar(&format, "format", "text", "Output format: text, or json for one JSON object per line")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+    cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        categories := []string{"deploy", "app", "instance", "health", "config"}
+        return filterCompletions(append(categories, types.EventTypes...), toComplete), cobra.ShellCompDirectiveNoFileComp
+    })
+    cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+    })
+
+    return cmd
+}
+
+// printEvent prints an event on one line, as text or JSON
+func printEvent(event types.AppEvent, format string) {
+    if format == "json" {
+        data, _ := json.Marshal(event)
+        fmt.Println(string(data))
+        return
+    }
+
+    message := event.Message
+    if event.Instance != "" {
+        message = event.Instance + ": " + message
+    }
+    actor := event.Actor
+    if actor == "" {
+        actor = "-"
+    }
+    fmt.Printf("%s  %-19s  %-16s  %s\n", event.Timestamp.Local().Format("2006-01-02 15:04:05"), event.Type, actor, strings.TrimSpace(message))
+}
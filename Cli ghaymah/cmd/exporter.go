@@ -0,0 +1,164 @@
+package cmd
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net/http"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/exporter"
+)
+
+// minExporterInterval keeps the exporter from flooding the API
+const minExporterInterval = 5 * time.Second
+
+// NewExporterCommand creates a new exporter command
+func NewExporterCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        names      []string
+        configPath string
+        envName    string
+        selector   string
+        all        bool
+        listen     string
+        path       string
+        interval   time.Duration
+    )
+
+    cmd := &cobra.Command{
+        Use:   "exporter [APP_NAME...]",
+        Short: "Expose application status as Prometheus metrics",
+        Long: `Run a Prometheus exporter that fetches the status of applications every
+--interval and serves it in the Prometheus text format: state, release, CPU,
+memory and storage usage, last deployment time, health, deployment lock and
+instance restarts.
+
+Applications are given as arguments or with --name, selected by label with
+--selector or all with --all. Applications matching --selector or --all are
+listed again at each poll, so new ones are exported without a restart.
+Without any of them, the application of the config file or linked app is
+exported.
+
+Examples:
+  # Export two applications on :9105/metrics
+  ghaymah exporter shop api --listen :9105
+
+  # Export every application labeled team=payments, refreshed every minute
+  ghaymah exporter --selector team=payments --interval 1m
+
+  # Prometheus scrape config
+  #   scrape_configs:
+  #     - job_name: ghaymah
+  #       static_configs:
+  #         - targets: ["localhost:9105"]`,
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if interval < minExporterInterval {
+                return fmt.Errorf("--interval must be at least %s", minExporterInterval)
+            }
+
+            apps, err := exporterApps(api, append(args, names...), selector, all, configPath, envName)
+            if err != nil {
+                return err
+            }
+            if _, err := apps(); err != nil {
+                return fmt.Errorf("failed to list applications: %v", err)
+            }
+
+            exp := &exporter.Exporter{
+                Status:   api.GetDetailedStatus,
+                Apps:     apps,
+                Interval: interval,
+                Logf: func(format string, args ...interface{}) {
+                    fmt.Fprintf(os.Stderr, format, args...)
+                },
+            }
+
+            mux := http.NewServeMux()
+            mux.Handle(path, exp)
+            if path != "/" {
+                mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+                    if r.URL.Path != "/" {
+                        http.NotFound(w, r)
+                        return
+                    }
+                    fmt.Fprintf(w, "Ghaymah exporter, metrics are served on %s\n", path)
+                })
+            }
+            server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+            ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+            defer stop()
+
+            errc := make(chan error, 1)
+            go func() {
+                errc <- server.ListenAndServe()
+            }()
+            go exp.Run(ctx)
+
+            fmt.Printf("Serving metrics on %s%s every %s... (Press Ctrl+C to exit)\n", listen, path, interval)
+            select {
+            case err := <-errc:
+                return fmt.Errorf("exporter failed: %v", err)
+            case <-ctx.Done():
+            }
+
+            shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+            defer cancel()
+            if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+                return fmt.Errorf("failed to stop the exporter: %v", err)
+            }
+            return nil
+        },
+    }
+
+    cmd.Flags().StringSliceVar(&names, "name", nil, "Application to export (repeatable)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.Flags().StringVarP(&selector, "selector", "l", "", "Export applications having these labels (key=value[,key=value...])")
+    cmd.Flags().BoolVar(&all, "all", false, "Export all applications")
+    cmd.Flags().StringVar(&listen, "listen", ":9105", "Address to serve metrics on")
+    cmd.Flags().StringVar(&path, "path", "/metrics", "HTTP path of the metrics")
+    cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "Time between status requests")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    return cmd
+}
+
+// exporterApps returns the function listing the applications to export
+func exporterApps(api *api.GhaymahAPI, names []string, selector string, all bool, configPath, envName string) (exporter.AppsFunc, error) {
+    given := 0
+    for _, set := range []bool{len(names) > 0, selector != "", all} {
+        if set {
+            given++
+        }
+    }
+    if given > 1 {
+        return nil, fmt.Errorf("application names, --selector and --all can't be combined")
+    }
+
+    switch {
+    case selector != "":
+        labels, err := parseSelector(selector)
+        if err != nil {
+            return nil, err
+        }
+        return func() ([]string, error) { return listAppNames(api, labels) }, nil
+    case all:
+        return func() ([]string, error) { return listAppNames(api, nil) }, nil
+    case len(names) == 0:
+        name, err := resolveAppName(nil, "", configPath, envName)
+        if err != nil {
+            return nil, err
+        }
+        names = []string{name}
+    }
+    return func() ([]string, error) { return names, nil }, nil
+}
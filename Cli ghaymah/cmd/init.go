@@ -0,0 +1,201 @@
+package cmd
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/config"
+    "ghaymah-cli/pkg/scaffold"
+)
+
+// NewInitCommand creates a new init command
+func NewInitCommand() *cobra.Command {
+    var (
+        dir            string
+        output         string
+        appName        string
+        image          string
+        region         string
+        port           int
+        nonInteractive bool
+        force          bool
+    )
+
+    cmd := &cobra.Command{
+        Use:   "init",
+        Short: "Create a deploy configuration for a project",
+        Long: `Inspect a project directory and write a commented ghaymah.yaml for it.
+The Dockerfile (including EXPOSE lines), package.json, go.mod and PORT usage in
+source files are used to propose the application name, Dockerfile path, port,
+resources and region. You are asked to confirm each value unless --yes is given
+or input is not a terminal.
+
+Examples:
+  # Answer questions interactively
+  ghaymah init
+
+  # Accept detected values
+  ghaymah init --yes
+
+  # Override detected values
+  ghaymah init --yes --name my-app --port 8080 --region eu-west-1`,
+        Args:        cobra.NoArgs,
+        Annotations: map[string]string{offlineAnnotation: "true"},
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if output == "" {
+                output = filepath.Join(dir, defaultConfigFile)
+            }
+            if _, err := os.Stat(output); err == nil && !force {
+                return fmt.Errorf("%s already exists. Use --force to overwrite it", output)
+            }
+
+            project, err := scaffold.Detect(dir)
+            if err != nil {
+                return fmt.Errorf("failed to inspect %s: %v", dir, err)
+            }
+
+            cpu, memory, storage := scaffold.DefaultResources(project.Type)
+            opts := scaffold.Options{
+                Version:        config.CurrentVersion,
+                AppName:        project.Name,
+                DockerfilePath: project.DockerfilePath,
+                Image:          image,
+                Region:         scaffold.DefaultRegion,
+                Port:           project.Port,
+                CPU:            cpu,
+                Memory:         memory,
+                Storage:        storage,
+            }
+            if opts.DockerfilePath == "" {
+                opts.DockerfilePath = "./Dockerfile"
+            }
+
+            fmt.Printf("Detected %s project in %s\n", project.Type, dir)
+            if project.Port != 0 {
+                fmt.Printf("Detected port %d from %s\n", project.Port, project.PortSource)
+            }
+
+            // Flags take precedence over detected values
+            if appName != "" {
+                opts.AppName = appName
+            }
+            if region != "" {
+                opts.Region = region
+            }
+            if port != 0 {
+                opts.Port = port
+            }
+
+            if !nonInteractive && isTerminal(os.Stdin) {
+                p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+                opts.AppName = p.ask("Application name", opts.AppName)
+                if opts.Image == "" {
+                    opts.DockerfilePath = p.ask("Dockerfile path", opts.DockerfilePath)
+                }
+                opts.Region = p.ask("Region", opts.Region)
+                if opts.Port, err = p.askInt("Port (0 for none)", opts.Port); err != nil {
+                    return err
+                }
+                opts.CPU = p.ask("CPU cores", opts.CPU)
+                opts.Memory = p.ask("Memory", opts.Memory)
+                opts.Storage = p.ask("Storage", opts.Storage)
+                if p.err != nil {
+                    return fmt.Errorf("failed to read answer: %v", p.err)
+                }
+            }
+
+            if opts.AppName == "" {
+                return fmt.Errorf("could not propose an application name. Use --name")
+            }
+            if sanitized := scaffold.SanitizeName(opts.AppName); sanitized != opts.AppName {
+                return fmt.Errorf("invalid application name %q (try %q)", opts.AppName, sanitized)
+            }
+
+            data, err := scaffold.Render(opts)
+            if err != nil {
+                return fmt.Errorf("failed to render config: %v", err)
+            }
+
+            // Make sure what we write would be accepted by config validate and deploy
+            if problems := config.Invalid(config.ValidateData(data, config.FormatYAML)); len(problems) > 0 {
+                return fmt.Errorf("generated config is invalid: %v", problems[0])
+            }
+
+            if err := os.WriteFile(output, data, 0o644); err != nil {
+                return fmt.Errorf("failed to write %s: %v", output, err)
+            }
+
+            fmt.Printf("Wrote %s\n", output)
+            if opts.Image == "" {
+                if _, err := os.Stat(filepath.Join(dir, opts.DockerfilePath)); errors.Is(err, os.ErrNotExist) {
+                    fmt.Printf("Warning: %s does not exist yet\n", opts.DockerfilePath)
+                }
+            }
+            fmt.Println("Deploy with: ghaymah deploy")
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&dir, "dir", ".", "Project directory to inspect")
+    cmd.Flags().StringVarP(&output, "output", "o", "", "File to write (default <dir>/ghaymah.yaml)")
+    cmd.Flags().StringVar(&appName, "name", "", "Application name (default detected)")
+    cmd.Flags().StringVar(&image, "image", "", "Docker image to deploy instead of building the Dockerfile")
+    cmd.Flags().StringVar(&region, "region", "", "Deployment region (default "+scaffold.DefaultRegion+")")
+    cmd.Flags().IntVar(&port, "port", 0, "Application port (default detected)")
+    cmd.Flags().BoolVarP(&nonInteractive, "yes", "y", false, "Accept detected values without asking")
+    cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing file")
+
+    return cmd
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+    info, err := f.Stat()
+    if err != nil {
+        return false
+    }
+    return info.Mode()&os.ModeCharDevice != 0
+}
+
+// prompter asks questions with a default answer
+type prompter struct {
+    in  *bufio.Reader
+    out io.Writer
+    err error
+}
+
+// ask prints a question and returns the answer, or def when left empty
+func (p *prompter) ask(question, def string) string {
+    if p.err != nil {
+        return def
+    }
+
+    fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+    line, err := p.in.ReadString('\n')
+    if err != nil && !errors.Is(err, io.EOF) {
+        p.err = err
+        return def
+    }
+
+    answer := strings.TrimSpace(line)
+    if answer == "" {
+        return def
+    }
+    return answer
+}
+
+// askInt asks for a number
+func (p *prompter) askInt(question string, def int) (int, error) {
+    answer := p.ask(question, strconv.Itoa(def))
+    n, err := strconv.Atoi(answer)
+    if err != nil || n < 0 || n > 65535 {
+        return 0, fmt.Errorf("invalid number %q", answer)
+    }
+    return n, nil
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+    "fmt"
+    "net/http"
+    "os"
+    "strings"
+    "text/tabwriter"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/cron"
+    "ghaymah-cli/pkg/types"
+)
+
+// runPollInterval is how often the output of a running job or task is fetched
+const runPollInterval = time.Second
+
+// NewJobsCommand creates a new jobs command
+func NewJobsCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "jobs",
+        Short: "Manage scheduled jobs",
+        Long: `Manage the jobs run on a cron schedule next to an application, such as
+nightly reports or cleanups. Jobs are defined under jobs: in the config file
+and scheduled on deploy:
+
+  jobs:
+    nightly-report:
+      schedule: "0 2 * * *"
+      command: ["npm", "run", "report"]
+      resources:
+        memory: 1G
+      timeout: 30m
+
+Schedules are in UTC. A job runs in a container of the application's image
+with its environment variables, unless image is set.
+
+The application is given with --name. When omitted, it is read from appName
+in the config file, or from the app linked with 'ghaymah link'.
+
+Examples:
+  # List jobs with their next and last run
+  ghaymah jobs list
+
+  # Run a job now and stream its output
+  ghaymah jobs run nightly-report --follow
+
+  # Show the latest runs of a job
+  ghaymah jobs history nightly-report
+
+  # Show the output of the latest run of a job
+  ghaymah jobs logs nightly-report`,
+    }
+
+    cmd.PersistentFlags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.PersistentFlags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    resolve := func() (string, error) {
+        return resolveAppName(nil, appName, configPath, envName)
+    }
+
+    cmd.AddCommand(
+        newJobsListCommand(api, resolve),
+        newJobsRunCommand(api, resolve),
+        newJobsHistoryCommand(api, resolve),
+        newJobsLogsCommand(api, resolve),
+    )
+
+    return cmd
+}
+
+// newJobsListCommand creates the jobs list subcommand
+func newJobsListCommand(api *api.GhaymahAPI, resolve func() (string, error)) *cobra.Command {
+    return &cobra.Command{
+        Use:   "list",
+        Short: "List scheduled jobs",
+        Args:  cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve()
+            if err != nil {
+                return err
+            }
+
+            jobs, err := api.ListJobs(name)
+            if err != nil {
+                return fmt.Errorf("failed to list jobs: %v", err)
+            }
+            if len(jobs.Jobs) == 0 {
+                fmt.Printf("No jobs scheduled for %s. Define them under jobs: in the config file and deploy\n", name)
+                return nil
+            }
+
+            now := time.Now().UTC()
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "NAME\tSCHEDULE\tCOMMAND\tNEXT RUN (UTC)\tLAST RUN")
+            for _, job := range jobs.Jobs {
+                next := "-"
+                if schedule, err := cron.Parse(job.Schedule); err == nil {
+                    if t := schedule.Next(now); !t.IsZero() {
+                        next = t.Format("2006-01-02 15:04")
+                    }
+                }
+                last := "-"
+                if job.LastRun != nil {
+                    last = fmt.Sprintf("%s %s ago", job.LastRun.State, formatAge(job.LastRun.StartedAt))
+                }
+                fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", job.Name, job.Schedule, strings.Join(job.Command, " "), next, last)
+            }
+            return w.Flush()
+        },
+    }
+}
+
+// newJobsRunCommand creates the jobs run subcommand
+func newJobsRunCommand(api *api.GhaymahAPI, resolve func() (string, error)) *cobra.Command {
+    var follow bool
+
+    cmd := &cobra.Command{
+        Use:               "run JOB",
+        Short:             "Run a job now, outside of its schedule",
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeJobNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve()
+            if err != nil {
+                return err
+            }
+
+            run, err := api.RunJob(name, args[0])
+            if err != nil {
+                return runJobError(name, args[0], err)
+            }
+
+            fmt.Printf("Started run %s of job %s\n", run.ID, args[0])
+            if !follow {
+                fmt.Printf("Follow its output with: ghaymah jobs logs --run %s --follow --name %s\n", run.ID, name)
+                return nil
+            }
+            return followRun(api, name, run, true)
+        },
+    }
+
+    cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Stream the output of the run until it finishes")
+
+    return cmd
+}
+
+// newJobsHistoryCommand creates the jobs history subcommand
+func newJobsHistoryCommand(api *api.GhaymahAPI, resolve func() (string, error)) *cobra.Command {
+    var limit int
+
+    cmd := &cobra.Command{
+        Use:               "history [JOB]",
+        Short:             "Show the latest runs of jobs and tasks",
+        Long:              "Show the latest runs of a job, or of all jobs and one-off tasks of the application, most recent first.",
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeJobNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve()
+            if err != nil {
+                return err
+            }
+            job := ""
+            if len(args) > 0 {
+                job = args[0]
+            }
+
+            runs, err := api.GetJobHistory(name, job, limit)
+            if err != nil {
+                return fmt.Errorf("failed to get job history: %v", err)
+            }
+            if len(runs.Runs) == 0 {
+                fmt.Println("No runs found")
+                return nil
+            }
+
+            now := time.Now()
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "ID\tJOB\tTRIGGER\tSTATE\tEXIT\tSTARTED\tDURATION")
+            for _, run := range runs.Runs {
+                exit := "-"
+                if run.ExitCode != nil {
+                    exit = fmt.Sprint(*run.ExitCode)
+                }
+                fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+                    run.ID,
+                    runName(&run),
+                    run.Trigger,
+                    run.State,
+                    exit,
+                    run.StartedAt.Local().Format("2006-01-02 15:04:05"),
+                    run.Duration(now).Round(time.Second),
+                )
+            }
+            return w.Flush()
+        },
+    }
+
+    cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Maximum number of runs to show")
+
+    return cmd
+}
+
+// newJobsLogsCommand creates the jobs logs subcommand
+func newJobsLogsCommand(api *api.GhaymahAPI, resolve func() (string, error)) *cobra.Command {
+    var (
+        runID  string
+        follow bool
+    )
+
+    cmd := &cobra.Command{
+        Use:   "logs [JOB]",
+        Short: "Show the output of a job run or task",
+        Long: `Show the output of the latest run of a job, or of the run or task given with
+--run (see 'ghaymah jobs history' for run IDs).`,
+        Args:              cobra.MaximumNArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeJobNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if (len(args) == 0) == (runID == "") {
+                return fmt.Errorf("give either a job name or --run")
+            }
+            name, err := resolve()
+            if err != nil {
+                return err
+            }
+
+            run := &types.JobRun{ID: runID}
+            if runID == "" {
+                runs, err := api.GetJobHistory(name, args[0], 1)
+                if err != nil {
+                    return fmt.Errorf("failed to get job history: %v", err)
+                }
+                if len(runs.Runs) == 0 {
+                    return fmt.Errorf("job %s of %s has not run yet", args[0], name)
+                }
+                run = &runs.Runs[0]
+            }
+
+            if follow {
+                return followRun(api, name, run, true)
+            }
+
+            logs, err := api.GetRunLogs(name, run.ID, 0)
+            if err != nil {
+                return fmt.Errorf("failed to get run logs: %v", err)
+            }
+            for _, entry := range logs.Entries {
+                fmt.Printf("[%s] %s\n", entry.Timestamp.Format(time.RFC3339), entry.Message)
+            }
+            if !logs.Run.Done() {
+                fmt.Printf("Run %s is still %s. Use --follow to stream its output\n", logs.Run.ID, logs.Run.State)
+            }
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&runID, "run", "", "ID of the run or task")
+    cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Stream the output until the run finishes")
+
+    return cmd
+}
+
+// followRun streams the output of a job run or task until it finishes, with
+// or without timestamps, and returns an error if it failed
+func followRun(api *api.GhaymahAPI, appName string, run *types.JobRun, timestamps bool) error {
+    cursor := 0
+    for {
+        logs, err := api.GetRunLogs(appName, run.ID, cursor)
+        if err != nil {
+            return fmt.Errorf("failed to get run logs: %v", err)
+        }
+        for _, entry := range logs.Entries {
+            if timestamps {
+                fmt.Printf("[%s] %s\n", entry.Timestamp.Format(time.RFC3339), entry.Message)
+            } else {
+                fmt.Println(entry.Message)
+            }
+        }
+        cursor = logs.Next
+
+        if logs.Run.Done() {
+            return runResult(&logs.Run)
+        }
+        time.Sleep(runPollInterval)
+    }
+}
+
+// runJobError explains why starting a run of job failed
+func runJobError(appName, job string, err error) error {
+    if api.IsStatus(err, http.StatusNotFound) {
+        return fmt.Errorf("%s has no job %s. List jobs with: ghaymah jobs list --name %s", appName, job, appName)
+    }
+    return fmt.Errorf("failed to run job: %v", err)
+}
+
+// runResult explains how a finished run ended
+func runResult(run *types.JobRun) error {
+    if run.State == types.RunSucceeded {
+        fmt.Fprintf(os.Stderr, "%s succeeded in %s\n", runName(run), run.Duration(time.Now()).Round(time.Second))
+        return nil
+    }
+    reason := run.Message
+    if run.ExitCode != nil {
+        reason = fmt.Sprintf("exit code %d", *run.ExitCode)
+        if run.Message != "" {
+            reason += ", " + run.Message
+        }
+    }
+    return fmt.Errorf("%s %s failed: %s", runName(run), run.ID, reason)
+}
+
+// runName names the job of a run, or "task" for one-off tasks
+func runName(run *types.JobRun) string {
+    if run.Job == "" {
+        return "task"
+    }
+    return run.Job
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+    "fmt"
+    "net/http"
+    "os"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// lifecyclePollInterval is how often --wait checks the state of applications
+const lifecyclePollInterval = 2 * time.Second
+
+// lifecycleAction describes a change of the running state of applications
+type lifecycleAction struct {
+    use    string
+    short  string
+    long   string
+    verb   string // e.g. "Restarting"
+    noun   string // e.g. "restart", used in error messages
+    target string // state reached once the action is done
+    apply  func(appName string) (*types.AppSummary, error)
+}
+
+// NewRestartCommand creates a new restart command
+func NewRestartCommand(api *api.GhaymahAPI) *cobra.Command {
+    var rolling bool
+
+    cmd := newLifecycleCommand(api, lifecycleAction{
+        use:   "restart [APP_NAME...]",
+        short: "Restart applications",
+        long: `Restart the instances of one or more applications, e.g. to pick up rotated
+secrets or recover from a stuck process. The running release is kept.
+
+By default all instances are restarted at once. With --rolling, instances are
+restarted one at a time so that the application keeps serving traffic.
+Stopped applications are not restarted, use 'ghaymah start' instead.
+
+Examples:
+  # Restart the app of the config file found in this or a parent directory
+  ghaymah restart
+
+  # Restart instance by instance and wait until the app is running again
+  ghaymah restart my-app --rolling --wait
+
+  # Restart every application labeled team=payments
+  ghaymah restart --selector team=payments`,
+        verb:   "Restarting",
+        noun:   "restart",
+        target: types.AppRunning,
+        apply: func(appName string) (*types.AppSummary, error) {
+            return api.RestartApp(appName, rolling)
+        },
+    })
+    cmd.Flags().BoolVar(&rolling, "rolling", false, "Restart instances one at a time")
+
+    return cmd
+}
+
+// NewStopCommand creates a new stop command
+func NewStopCommand(api *api.GhaymahAPI) *cobra.Command {
+    return newLifecycleCommand(api, lifecycleAction{
+        use:   "stop [APP_NAME...]",
+        short: "Stop applications",
+        long: `Stop all instances of one or more applications. Their configuration,
+release, domains and data are kept, and 'ghaymah start' runs them again.
+Stopping an application that is already stopped does nothing.
+
+Examples:
+  ghaymah stop my-app
+
+  # Stop the previews of the shop app and wait until they are stopped
+  ghaymah stop --selector ghaymah.cloud/preview-of=shop --wait`,
+        verb:   "Stopping",
+        noun:   "stop",
+        target: types.AppStopped,
+        apply:  api.StopApp,
+    })
+}
+
+// NewStartCommand creates a new start command
+func NewStartCommand(api *api.GhaymahAPI) *cobra.Command {
+    return newLifecycleCommand(api, lifecycleAction{
+        use:   "start [APP_NAME...]",
+        short: "Start stopped applications",
+        long: `Start one or more applications stopped with 'ghaymah stop', running their
+current release again. Starting an application that is running does nothing.
+
+Examples:
+  ghaymah start my-app --wait
+
+  # Start several applications
+  ghaymah start shop api worker`,
+        verb:   "Starting",
+        noun:   "start",
+        target: types.AppRunning,
+        apply:  api.StartApp,
+    })
+}
+
+// newLifecycleCommand creates a command applying a lifecycle action to the
+// applications given as arguments, with --name or with --selector
+func newLifecycleCommand(api *api.GhaymahAPI, action lifecycleAction) *cobra.Command {
+    var (
+        names      []string
+        configPath string
+        envName    string
+        selector   string
+        wait       bool
+        timeout    time.Duration
+    )
+
+    cmd := &cobra.Command{
+        Use:   action.use,
+        Short: action.short,
+        Long: action.long + `
+
+Applications are given as arguments or with --name, or selected by label with
+--selector. When omitted, the application is read from appName in the config
+file, or from the app linked with 'ghaymah link'.`,
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            apps, err := selectApps(api, append(args, names...), selector, configPath, envName)
+            if err != nil {
+                return err
+            }
+
+            var applied []string
+            failed := 0
+            for _, name := range apps {
+                fmt.Printf("%s %s...\n", action.verb, name)
+                app, err := action.apply(name)
+                if err != nil {
+                    fmt.Fprintf(os.Stderr, "Failed to %s %s: %v\n", action.noun, name, lifecycleError(err))
+                    failed++
+                    continue
+                }
+                fmt.Printf("%s is %s\n", name, app.State)
+                applied = append(applied, name)
+            }
+
+            if wait && len(applied) > 0 {
+                failed += waitForState(api, applied, action.target, timeout)
+            }
+
+            if failed > 0 {
+                return fmt.Errorf("failed to %s %d of %d applications", action.noun, failed, len(apps))
+            }
+            return nil
+        },
+    }
+
+    cmd.Flags().StringSliceVar(&names, "name", nil, "Application to act on (repeatable)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.Flags().StringVarP(&selector, "selector", "l", "", "Act on applications having these labels (key=value[,key=value...])")
+    cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait until the applications are "+action.target)
+    cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "How long --wait waits before failing")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    return cmd
+}
+
+// waitForState polls the status of applications until they all reach the
+// target state, printing their state changes. It returns how many didn't
+// reach it before the timeout.
+func waitForState(api *api.GhaymahAPI, apps []string, target string, timeout time.Duration) int {
+    fmt.Printf("Waiting for %d application(s) to be %s...\n", len(apps), target)
+
+    deadline := time.Now().Add(timeout)
+    pending := map[string]string{}
+    for _, name := range apps {
+        pending[name] = ""
+    }
+
+    for {
+        for _, name := range apps {
+            last, ok := pending[name]
+            if !ok {
+                continue
+            }
+            status, err := api.GetStatus(name)
+            if err != nil {
+                fmt.Fprintf(os.Stderr, "Failed to get status of %s: %v\n", name, err)
+                continue
+            }
+            if status.State == target {
+                fmt.Printf("[%s] %s is %s\n", time.Now().Format("15:04:05"), name, target)
+                delete(pending, name)
+                continue
+            }
+            if status.State != last && last != "" {
+                fmt.Printf("[%s] %s is %s\n", time.Now().Format("15:04:05"), name, status.State)
+            }
+            pending[name] = status.State
+        }
+
+        if len(pending) == 0 {
+            return 0
+        }
+        if time.Now().After(deadline) {
+            for _, name := range apps {
+                if state, ok := pending[name]; ok {
+                    if state == "" {
+                        state = "unknown"
+                    }
+                    fmt.Fprintf(os.Stderr, "Timed out after %s waiting for %s to be %s (state: %s)\n", timeout, name, target, state)
+                }
+            }
+            return len(pending)
+        }
+        time.Sleep(lifecyclePollInterval)
+    }
+}
+
+// lifecycleError explains why the API rejected a lifecycle action
+func lifecycleError(err error) error {
+    if api.IsStatus(err, http.StatusConflict) || api.IsStatus(err, http.StatusNotFound) {
+        return fmt.Errorf("%s", api.StatusMessage(err))
+    }
+    return err
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+    "errors"
+    "fmt"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/config"
+)
+
+// NewLinkCommand creates a new link command
+func NewLinkCommand(api *api.GhaymahAPI) *cobra.Command {
+    var unlink bool
+
+    cmd := &cobra.Command{
+        Use:   "link [APP_NAME]",
+        Short: "Link the current directory to an application",
+        Long: `Link the current directory to an application on Ghaymah Cloud.
+The link is stored in .ghaymah/app, so commands such as status and logs
+run from this directory (or any subdirectory) act on the linked application
+without passing its name.
+
+Examples:
+  # Link the current directory to my-app
+  ghaymah link my-app
+
+  # Show the linked application
+  ghaymah link
+
+  # Remove the link
+  ghaymah link --unlink`,
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if unlink {
+                if err := config.RemoveLink("."); err != nil {
+                    if errors.Is(err, config.ErrNoLink) {
+                        return fmt.Errorf("current directory is not linked to an application")
+                    }
+                    return fmt.Errorf("failed to remove link: %v", err)
+                }
+                fmt.Println("Unlinked current directory")
+                return nil
+            }
+
+            if len(args) == 0 {
+                name, path, err := config.FindLinkedApp(".")
+                if err != nil {
+                    if errors.Is(err, config.ErrNoLink) {
+                        return fmt.Errorf("current directory is not linked to an application. Use 'ghaymah link APP_NAME'")
+                    }
+                    return err
+                }
+                fmt.Printf("Linked to %s (%s)\n", name, path)
+                return nil
+            }
+
+            appName := args[0]
+            if _, err := api.GetStatus(appName); err != nil {
+                return fmt.Errorf("failed to find application %s: %v", appName, err)
+            }
+
+            path, err := config.WriteLink(".", appName)
+            if err != nil {
+                return fmt.Errorf("failed to write link: %v", err)
+            }
+
+            fmt.Printf("Linked current directory to %s (%s)\n", appName, path)
+            return nil
+        },
+    }
+
+    cmd.Flags().BoolVar(&unlink, "unlink", false, "Remove the link of the current directory")
+
+    return cmd
+}
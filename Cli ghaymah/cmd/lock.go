@@ -0,0 +1,134 @@
+package cmd
+
+import (
+    "fmt"
+    "net/http"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// NewLockCommand creates a new lock command
+func NewLockCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+        reason     string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "lock [APP_NAME]",
+        Short: "Freeze deployments of an application",
+        Long: `Freeze deployments of an application, e.g. during an incident or a release
+freeze. While the application is locked, deploys and rollout promotions are
+rejected with the reason given. Use 'ghaymah unlock' to allow them again.
+
+Examples:
+  # Lock deployments with a reason shown to anyone trying to deploy
+  ghaymah lock my-app --reason "incident #42, investigating DB load"
+
+  # Lock the app of the config file found in this or a parent directory
+  ghaymah lock --reason "release freeze"`,
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            appName, err := resolveAppName(args, appName, configPath, envName)
+            if err != nil {
+                return err
+            }
+
+            lock, err := api.LockApp(appName, reason)
+            if err != nil {
+                return fmt.Errorf("failed to lock %s: %v", appName, err)
+            }
+
+            fmt.Printf("Deployments of %s are locked: %s\n", appName, lock.Reason)
+            fmt.Printf("Unlock with: ghaymah unlock %s\n", appName)
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.Flags().StringVar(&reason, "reason", "", "Why deployments are locked")
+    cmd.MarkFlagRequired("reason")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    return cmd
+}
+
+// NewUnlockCommand creates a new unlock command
+func NewUnlockCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "unlock [APP_NAME]",
+        Short: "Allow deployments of a locked application",
+        Long: `Remove the lock set with 'ghaymah lock', allowing deployments again.
+
+Examples:
+  ghaymah unlock my-app`,
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            appName, err := resolveAppName(args, appName, configPath, envName)
+            if err != nil {
+                return err
+            }
+
+            if err := api.UnlockApp(appName); err != nil {
+                return unlockError(appName, err)
+            }
+
+            fmt.Printf("Deployments of %s are allowed again\n", appName)
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    return cmd
+}
+
+// lockedError explains that deployments of an application are locked
+func lockedError(appName string, lock *types.AppLock) error {
+    by := ""
+    if lock.LockedBy != "" {
+        by = " by " + lock.LockedBy
+    }
+    return fmt.Errorf("deployments of %s are locked%s since %s: %s. Run 'ghaymah unlock %s' once it is safe to deploy",
+        appName, by, lock.LockedAt.Format("2006-01-02 15:04:05"), lock.Reason, appName)
+}
+
+// deployError explains why the API rejected a change to the running release,
+// such as a deploy or a rollout promotion
+func deployError(action, appName string, err error) error {
+    switch {
+    case api.IsStatus(err, http.StatusLocked):
+        return fmt.Errorf("%s of %s rejected, deployments are locked: %s. Run 'ghaymah unlock %s' once it is safe to deploy",
+            action, appName, api.StatusMessage(err), appName)
+    case api.IsStatus(err, http.StatusConflict):
+        return fmt.Errorf("%s of %s rejected, another deployment happened in the meantime: %s. Check 'ghaymah status %s' and try again",
+            action, appName, api.StatusMessage(err), appName)
+    }
+    return fmt.Errorf("%s failed: %v", action, err)
+}
+
+// unlockError explains why unlocking appName failed
+func unlockError(appName string, err error) error {
+    if api.IsStatus(err, http.StatusNotFound) {
+        return fmt.Errorf("%s is not locked", appName)
+    }
+    return fmt.Errorf("failed to unlock %s: %v", appName, err)
+}
@@ -11,22 +11,30 @@ import (
 // NewLogsCommand creates a new logs command
 func NewLogsCommand(api *api.GhaymahAPI) *cobra.Command {
     var (
-        appName string
-        follow  bool
-        tail    int
-        since   string
+        appName    string
+        configPath string
+        envName    string
+        follow     bool
+        tail       int
+        since      string
     )
 
     cmd := &cobra.Command{
-        Use:   "logs",
+        Use:   "logs [APP_NAME]",
         Short: "View application logs",
         Long: `View logs from your application running on Ghaymah Cloud.
 You can view historical logs or follow them in real-time. Use flags to customize
 the output according to your needs.
 
+The application name can be given as an argument or with --name. When omitted, it is
+read from appName in the config file, or from the app linked with 'ghaymah link'.
+
 Examples:
   # View recent logs
-  ghaymah logs --name my-app
+  ghaymah logs my-app
+
+  # View logs of the app of the config file found in this or a parent directory
+  ghaymah logs
 
   # Follow logs in real-time
   ghaymah logs --name my-app --follow
@@ -36,9 +44,12 @@ Examples:
 
   # View logs since a specific time
   ghaymah logs --name my-app --since 2024-01-23T00:00:00Z`,
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
         RunE: func(cmd *cobra.Command, args []string) error {
-            if appName == "" {
-                return fmt.Errorf("application name is required. Use --name flag")
+            appName, err := resolveAppName(args, appName, configPath, envName)
+            if err != nil {
+                return err
             }
 
             fmt.Printf("Retrieving logs for application %s...\n", appName)
@@ -79,11 +90,15 @@ Examples:
         },
     }
 
-    cmd.Flags().StringVar(&appName, "name", "", "Application name")
-    cmd.MarkFlagRequired("name")
+    cmd.Flags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
     cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output in real-time")
     cmd.Flags().IntVarP(&tail, "tail", "n", 100, "Number of lines to show from the end of the logs")
     cmd.Flags().StringVarP(&since, "since", "s", "", "Show logs since timestamp (RFC3339 format)")
 
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
     return cmd
 }
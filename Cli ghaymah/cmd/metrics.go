@@ -0,0 +1,228 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/metrics"
+    "ghaymah-cli/pkg/types"
+)
+
+// Size of the charts drawn by the metrics command
+const (
+    chartWidth  = 60
+    chartHeight = 8
+)
+
+// Limits of metrics queries
+const (
+    minMetricsStep   = 10 * time.Second
+    maxMetricsPoints = 11000
+)
+
+// metricsSteps are the resolutions picked when --step is not given
+var metricsSteps = []time.Duration{
+    10 * time.Second, 15 * time.Second, 30 * time.Second,
+    time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+    time.Hour, 2 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// NewMetricsCommand creates a new metrics command
+func NewMetricsCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+        since      string
+        until      string
+        step       time.Duration
+        names      []string
+        format     string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "metrics [APP_NAME]",
+        Short: "Show the metrics history of an application",
+        Long: `Show how the resource usage of your application evolved over a time range,
+as terminal charts with p50, p95 and maximum values. Applications serving HTTP
+also report their request rate, p95 latency and error rate.
+
+--since and --until take a duration before now (30m, 6h, 7d) or an RFC3339 timestamp.
+The resolution is chosen from the range unless --step is given.
+
+The application name can be given as an argument or with --name. When omitted, it is
+read from appName in the config file, or from the app linked with 'ghaymah link'.
+
+Examples:
+  # Charts of the last hour
+  ghaymah metrics my-app
+
+  # CPU and latency over the last 6 hours, one point per minute
+  ghaymah metrics --name my-app --since 6h --step 1m --metric cpu,latency
+
+  # Export a day of metrics for a spreadsheet
+  ghaymah metrics my-app --since 24h --format csv > metrics.csv
+
+  # Export as JSON, with p50/p95/max of each metric
+  ghaymah metrics my-app --since 2024-01-23T00:00:00Z --until 2024-01-24T00:00:00Z --format json`,
+        Args:              cobra.MaximumNArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if format != "chart" && format != "csv" && format != "json" {
+                return fmt.Errorf("invalid format %q (expected chart, csv or json)", format)
+            }
+            for _, name := range names {
+                if !isMetric(name) {
+                    return fmt.Errorf("unknown metric %q (expected one of %s)", name, strings.Join(types.Metrics, ", "))
+                }
+            }
+
+            appName, err := resolveAppName(args, appName, configPath, envName)
+            if err != nil {
+                return err
+            }
+
+            now := time.Now()
+            start, err := parseTimeOrAgo(since, now)
+            if err != nil {
+                return fmt.Errorf("invalid --since: %v", err)
+            }
+            end := now
+            if until != "" {
+                if end, err = parseTimeOrAgo(until, now); err != nil {
+                    return fmt.Errorf("invalid --until: %v", err)
+                }
+            }
+            if !start.Before(end) {
+                return fmt.Errorf("--since must be before --until")
+            }
+
+            if step == 0 {
+                step = autoStep(end.Sub(start))
+            }
+            if step < minMetricsStep {
+                return fmt.Errorf("--step must be at least %s", minMetricsStep)
+            }
+            if points := int(end.Sub(start) / step); points > maxMetricsPoints {
+                return fmt.Errorf("--step %s gives %d points over %s, the maximum is %d. Use a larger --step",
+                    step, points, end.Sub(start).Round(time.Second), maxMetricsPoints)
+            }
+
+            resp, err := api.GetMetrics(appName, &types.MetricsOptions{
+                Start:   start,
+                End:     end,
+                Step:    step,
+                Metrics: names,
+            })
+            if err != nil {
+                return fmt.Errorf("failed to get metrics: %v", err)
+            }
+
+            switch format {
+            case "csv":
+                return metrics.WriteCSV(os.Stdout, resp)
+            case "json":
+                return metrics.WriteJSON(os.Stdout, resp)
+            }
+            printMetrics(appName, resp, names)
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.Flags().StringVarP(&since, "since", "s", "1h", "Start of the range, as a duration before now or an RFC3339 timestamp")
+    cmd.Flags().StringVar(&until, "until", "", "End of the range, as a duration before now or an RFC3339 timestamp (default: now)")
+    cmd.Flags().DurationVar(&step, "step", 0, "Time between points, e.g. 1m (default: chosen from the range)")
+    cmd.Flags().StringSliceVarP(&names, "metric", "m", nil, "Metrics to show: "+strings.Join(types.Metrics, ", ")+" (default: all)")
+    cmd.Flags().StringVar(&format, "format", "chart", "Output format: chart, csv or json")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+    cmd.RegisterFlagCompletionFunc("metric", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        return filterCompletions(types.Metrics, toComplete), cobra.ShellCompDirectiveNoFileComp
+    })
+    cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        return []string{"chart", "csv", "json"}, cobra.ShellCompDirectiveNoFileComp
+    })
+
+    return cmd
+}
+
+// printMetrics shows a chart and the statistics of each series
+func printMetrics(appName string, resp *types.MetricsResponse, requested []string) {
+    fmt.Printf("Metrics of %s from %s to %s (step %s)\n", appName,
+        resp.Start.Local().Format("2006-01-02 15:04"), resp.End.Local().Format("2006-01-02 15:04"),
+        time.Duration(resp.StepSeconds)*time.Second)
+
+    returned := map[string]bool{}
+    for _, series := range resp.Series {
+        returned[series.Name] = true
+
+        stats := metrics.Summarize(series.Points)
+        fmt.Printf("\n%s\n", metrics.Title(series.Name))
+        if stats.Count > 0 {
+            fmt.Printf("  p50 %s  p95 %s  max %s  last %s\n",
+                metrics.FormatValue(stats.P50, series.Unit), metrics.FormatValue(stats.P95, series.Unit),
+                metrics.FormatValue(stats.Max, series.Unit), metrics.FormatValue(stats.Last, series.Unit))
+        }
+        fmt.Print(metrics.Chart(series.Points, series.Unit, chartWidth, chartHeight))
+    }
+
+    if len(requested) == 0 {
+        requested = types.Metrics
+    }
+    var missing []string
+    for _, name := range requested {
+        if !returned[name] {
+            missing = append(missing, name)
+        }
+    }
+    if len(missing) > 0 {
+        fmt.Printf("\nNot available for %s: %s\n", appName, strings.Join(missing, ", "))
+    }
+}
+
+// isMetric reports whether name is a known metric
+func isMetric(name string) bool {
+    for _, metric := range types.Metrics {
+        if metric == name {
+            return true
+        }
+    }
+    return false
+}
+
+// parseTimeOrAgo parses an RFC3339 timestamp, or a duration before now.
+// Durations can also be given in days, e.g. 7d.
+func parseTimeOrAgo(value string, now time.Time) (time.Time, error) {
+    if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+        value = fmt.Sprintf("%dh", days*24)
+    }
+    if d, err := time.ParseDuration(value); err == nil {
+        if d < 0 {
+            return time.Time{}, fmt.Errorf("negative duration %q", value)
+        }
+        return now.Add(-d), nil
+    }
+    t, err := time.Parse(time.RFC3339, value)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("expected a duration such as 6h or 7d, or an RFC3339 timestamp, got %q", value)
+    }
+    return t, nil
+}
+
+// autoStep picks the smallest usual resolution that fits the range in one chart width
+func autoStep(span time.Duration) time.Duration {
+    for _, step := range metricsSteps {
+        if span/step <= chartWidth {
+            return step
+        }
+    }
+    return metricsSteps[len(metricsSteps)-1]
+}
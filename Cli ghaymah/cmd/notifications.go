@@ -0,0 +1,112 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "text/tabwriter"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// NewNotificationsCommand creates a new notifications command
+func NewNotificationsCommand(api *api.GhaymahAPI) *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "notifications",
+        Short: "Manage notification channels used by alerts",
+        Long: `Manage the channels alert notifications are sent to:
+  webhook   JSON POST to any URL
+  slack     Slack-compatible incoming webhook (Slack, Mattermost, Rocket.Chat...)
+  email     one or more email addresses
+
+Examples:
+  # Post to a Slack channel
+  ghaymah notifications create ops-slack --type slack --url https://hooks.slack.com/services/T000/B000/XXXX
+
+  # Email the on-call team
+  ghaymah notifications create oncall --type email --to oncall@example.com,lead@example.com
+
+  # Send alerts to your own service
+  ghaymah notifications create pager --type webhook --url https://alerts.example.com/ghaymah
+
+  # List and delete channels
+  ghaymah notifications list
+  ghaymah notifications delete pager`,
+    }
+
+    var (
+        channelType string
+        channelURL  string
+        to          []string
+    )
+
+    createCmd := &cobra.Command{
+        Use:   "create NAME",
+        Short: "Create a notification channel",
+        Args:  cobra.ExactArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            channel := &types.NotificationChannel{Name: args[0], Type: channelType, URL: channelURL, To: to}
+            if err := channel.Validate(); err != nil {
+                return err
+            }
+
+            created, err := api.CreateNotificationChannel(channel)
+            if err != nil {
+                return fmt.Errorf("failed to create notification channel: %v", err)
+            }
+
+            fmt.Printf("Notification channel %s created (%s to %s)\n", created.Name, created.Type, created.Target())
+            return nil
+        },
+    }
+    createCmd.Flags().StringVar(&channelType, "type", "", "Channel type: webhook, slack or email")
+    createCmd.Flags().StringVar(&channelURL, "url", "", "Webhook URL (webhook and slack channels)")
+    createCmd.Flags().StringSliceVar(&to, "to", nil, "Email addresses (email channels)")
+    createCmd.MarkFlagRequired("type")
+    createCmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        return []string{types.ChannelWebhook, types.ChannelSlack, types.ChannelEmail}, cobra.ShellCompDirectiveNoFileComp
+    })
+
+    listCmd := &cobra.Command{
+        Use:   "list",
+        Short: "List notification channels",
+        Args:  cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            channels, err := api.ListNotificationChannels()
+            if err != nil {
+                return fmt.Errorf("failed to list notification channels: %v", err)
+            }
+
+            if len(channels.Channels) == 0 {
+                fmt.Println("No notification channels found")
+                return nil
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "NAME\tTYPE\tTARGET")
+            for _, channel := range channels.Channels {
+                fmt.Fprintf(w, "%s\t%s\t%s\n", channel.Name, channel.Type, channel.Target())
+            }
+            return w.Flush()
+        },
+    }
+
+    deleteCmd := &cobra.Command{
+        Use:               "delete NAME",
+        Short:             "Delete a notification channel",
+        Args:              cobra.ExactArgs(1),
+        ValidArgsFunction: completeNotificationChannels(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if err := api.DeleteNotificationChannel(args[0]); err != nil {
+                return fmt.Errorf("failed to delete notification channel: %v", err)
+            }
+
+            fmt.Printf("Notification channel %s deleted\n", args[0])
+            return nil
+        },
+    }
+
+    cmd.AddCommand(createCmd, listCmd, deleteCmd)
+
+    return cmd
+}
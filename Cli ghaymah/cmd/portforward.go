@@ -0,0 +1,83 @@
+package cmd
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "os/signal"
+    "github.com/gorilla/websocket"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/portforward"
+)
+
+// NewPortForwardCommand creates a new port-forward command
+func NewPortForwardCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName string
+        address string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "port-forward LOCAL_PORT:REMOTE_PORT [...]",
+        Short: "Forward local ports to an application",
+        Long: `Forward one or more local ports to ports of your application on Ghaymah Cloud.
+Each local TCP connection is tunneled over a single WebSocket connection to the API,
+so ports that are not publicly exposed (databases, admin panels) can be reached locally.
+The tunnel reconnects automatically if the connection drops. Each connection is
+flow controlled on its own, so a slow local client only slows down its own transfer.
+
+Examples:
+  # Forward local port 5432 to port 5432 of the application
+  ghaymah port-forward --name my-app 5432:5432
+
+  # Forward several ports at once
+  ghaymah port-forward --name my-app 5432 8081:8080
+
+  # Listen on all interfaces
+  ghaymah port-forward --name my-app 5432 --address 0.0.0.0`,
+        Args: cobra.MinimumNArgs(1),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if appName == "" {
+                return fmt.Errorf("application name is required. Use --name flag")
+            }
+
+            mappings := make([]portforward.Mapping, 0, len(args))
+            for _, arg := range args {
+                mapping, err := portforward.ParseMapping(arg)
+                if err != nil {
+                    return err
+                }
+                mappings = append(mappings, mapping)
+            }
+
+            forwarder := &portforward.Forwarder{
+                Dial: func() (*websocket.Conn, error) {
+                    return api.DialPortForward(appName)
+                },
+                Address:  address,
+                Mappings: mappings,
+                Logf: func(format string, args ...interface{}) {
+                    fmt.Printf(format, args...)
+                },
+            }
+
+            ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+            defer stop()
+
+            fmt.Printf("Starting port-forward for application %s... (Press Ctrl+C to exit)\n", appName)
+            if err := forwarder.Run(ctx); err != nil {
+                return fmt.Errorf("port-forward failed: %v", err)
+            }
+
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&appName, "name", "", "Application name")
+    cmd.MarkFlagRequired("name")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.Flags().StringVar(&address, "address", "127.0.0.1", "Local address to listen on")
+
+    return cmd
+}
@@ -0,0 +1,419 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "sort"
+    "text/tabwriter"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/config"
+    "ghaymah-cli/pkg/preview"
+    "ghaymah-cli/pkg/types"
+)
+
+// previewEnvironment is the environment applied to previews when the config defines it
+const previewEnvironment = "preview"
+
+// NewPreviewCommand creates a new preview command
+func NewPreviewCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        configPath string
+        branch     string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "preview",
+        Short: "Manage temporary preview applications per git branch",
+        Long: `Deploy a temporary copy of your application for the current git branch, e.g.
+for each pull request. The preview application is named after the application
+and the branch (shop-feature-login for branch feature/login of shop) and is
+labeled with the branch and commit it was deployed from.
+
+Previews are deployed with the settings of the config file, with the preview
+environment merged onto them when defined (see --env-name). Custom domains are
+not attached to previews, scheduled jobs don't run in previews, and volumes,
+which stay mounted by the application, aren't mounted by previews.
+
+Examples:
+  # Deploy or update the preview of the current branch
+  ghaymah preview up
+
+  # Deploy an image built by CI for a branch
+  ghaymah preview up --branch feature/login --image registry/shop:abc123
+
+  # List previews of the application
+  ghaymah preview list
+
+  # Delete the preview of the current branch
+  ghaymah preview down
+
+  # Delete previews older than a week or whose branch was deleted
+  ghaymah preview gc --ttl 168h`,
+    }
+
+    cmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "path to configuration file (default: discovered)")
+    cmd.PersistentFlags().StringVar(&branch, "branch", "", "Git branch of the preview (default: current branch)")
+
+    cmd.AddCommand(
+        newPreviewUpCommand(api, &configPath, &branch),
+        newPreviewDownCommand(api, &configPath, &branch),
+        newPreviewListCommand(api, &configPath),
+        newPreviewGCCommand(api, &configPath),
+    )
+
+    return cmd
+}
+
+// newPreviewUpCommand creates the preview up subcommand
+func newPreviewUpCommand(api *api.GhaymahAPI, configPath, branch *string) *cobra.Command {
+    var (
+        envName string
+        image   string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "up",
+        Short:        "Deploy or update the preview of a branch",
+        Args:         cobra.NoArgs,
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            path, err := findConfigFile(*configPath)
+            if err != nil {
+                return err
+            }
+            if envName == "" {
+                names, _ := config.EnvironmentNames(path)
+                for _, name := range names {
+                    if name == previewEnvironment {
+                        envName = previewEnvironment
+                    }
+                }
+            }
+
+            cfg := &config.Config{}
+            if err := cfg.LoadEnvironment(path, envName); err != nil {
+                return fmt.Errorf("failed to load config: %v", err)
+            }
+            for _, warning := range cfg.Warnings() {
+                fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+            }
+            if cfg.AppName == "" {
+                return fmt.Errorf("appName is required in %s to name previews", path)
+            }
+
+            branchName, err := previewBranch(*branch)
+            if err != nil {
+                return err
+            }
+            commit, err := preview.CurrentCommit(".")
+            if err != nil && image == "" {
+                return fmt.Errorf("failed to read the current commit: %v", err)
+            }
+
+            baseName := cfg.AppName
+            cfg.AppName = preview.AppName(baseName, branchName)
+            if _, err := checkPreviewApp(api, cfg.AppName, baseName, branchName, "deploy over"); err != nil {
+                return err
+            }
+            cfg.Domains = nil
+            cfg.Jobs = nil
+            cfg.Volumes = nil
+            if image != "" {
+                cfg.Image = image
+            }
+            labels := map[string]string{}
+            for k, v := range cfg.Labels {
+                labels[k] = v
+            }
+            for k, v := range preview.Labels(baseName, branchName, commit) {
+                labels[k] = v
+            }
+            cfg.Labels = labels
+
+            // Previews go through the checks of deploy: regions, locks and
+            // concurrent deploys of the same preview
+            deployCmd := &DeployCommand{config: cfg, api: *api}
+            expected, err := deployCmd.prepare()
+            if err != nil {
+                return err
+            }
+
+            if commit != "" {
+                fmt.Printf("Deploying preview %s of %s for branch %s (commit %s)...\n", cfg.AppName, baseName, branchName, commit)
+            } else {
+                fmt.Printf("Deploying preview %s of %s for branch %s...\n", cfg.AppName, baseName, branchName)
+            }
+
+            resp, err := api.Deploy(cfg, &types.DeployOptions{ExpectedVersion: expected})
+            if err != nil {
+                return deployError("preview deployment", cfg.AppName, err)
+            }
+
+            fmt.Println("Preview deployed!")
+            if resp.URL != "" {
+                fmt.Printf("Preview URL: %s\n", resp.URL)
+            }
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment merged onto the config file (default: preview, when defined)")
+    cmd.Flags().StringVar(&image, "image", "", "Docker image to deploy instead of the one of the config file")
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    return cmd
+}
+
+// newPreviewDownCommand creates the preview down subcommand
+func newPreviewDownCommand(api *api.GhaymahAPI, configPath, branch *string) *cobra.Command {
+    return &cobra.Command{
+        Use:          "down",
+        Short:        "Delete the preview of a branch",
+        Args:         cobra.NoArgs,
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            baseName, err := previewBaseName(*configPath)
+            if err != nil {
+                return err
+            }
+            branchName, err := previewBranch(*branch)
+            if err != nil {
+                return err
+            }
+
+            name := preview.AppName(baseName, branchName)
+            exists, err := checkPreviewApp(api, name, baseName, branchName, "delete")
+            if err != nil {
+                return err
+            }
+            if !exists {
+                return fmt.Errorf("no preview of branch %s found", branchName)
+            }
+            if err := api.DeleteApp(name); err != nil {
+                return fmt.Errorf("failed to delete preview %s: %v", name, err)
+            }
+
+            fmt.Printf("Preview %s of branch %s deleted\n", name, branchName)
+            return nil
+        },
+    }
+}
+
+// newPreviewListCommand creates the preview list subcommand
+func newPreviewListCommand(api *api.GhaymahAPI, configPath *string) *cobra.Command {
+    var all bool
+
+    cmd := &cobra.Command{
+        Use:   "list",
+        Short: "List previews",
+        Args:  cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            baseName := ""
+            if !all {
+                name, err := previewBaseName(*configPath)
+                if err != nil {
+                    return err
+                }
+                baseName = name
+            }
+            apps, err := listPreviews(api, baseName)
+            if err != nil {
+                return err
+            }
+            if len(apps) == 0 {
+                fmt.Println("No previews found")
+                return nil
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "NAME\tBRANCH\tCOMMIT\tAGE\tURL")
+            for _, app := range apps {
+                age := "-"
+                if app.DeployedAt != nil {
+                    age = formatAge(*app.DeployedAt)
+                }
+                fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+                    app.Name,
+                    app.Labels[preview.LabelBranch],
+                    app.Labels[preview.LabelCommit],
+                    age,
+                    app.URL,
+                )
+            }
+            return w.Flush()
+        },
+    }
+
+    cmd.Flags().BoolVar(&all, "all", false, "List previews of all applications")
+
+    return cmd
+}
+
+// newPreviewGCCommand creates the preview gc subcommand
+func newPreviewGCCommand(api *api.GhaymahAPI, configPath *string) *cobra.Command {
+    var (
+        all    bool
+        ttl    time.Duration
+        remote string
+        dryRun bool
+    )
+
+    cmd := &cobra.Command{
+        Use:   "gc",
+        Short: "Delete expired previews and previews of deleted branches",
+        Long: `Delete previews deployed longer ago than --ttl, and previews whose branch no
+longer exists on the git remote. If the remote can't be reached, only the TTL
+is checked.
+
+The remote is the one of the current repository, so with --all the branches
+are only checked for previews of the application of the config file. Previews
+of other applications are only collected by TTL.`,
+        Args: cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            // With --all, the config file is only needed to check branches
+            baseName, err := previewBaseName(*configPath)
+            if err != nil && !all {
+                return err
+            }
+            selected := baseName
+            if all {
+                selected = ""
+            }
+            apps, err := listPreviews(api, selected)
+            if err != nil {
+                return err
+            }
+
+            branches, err := preview.RemoteBranches(".", remote)
+            if err != nil {
+                fmt.Fprintf(os.Stderr, "Warning: not checking branches: %v\n", err)
+                branches = nil
+            }
+
+            deleted, failed := 0, 0
+            now := time.Now()
+            for _, app := range apps {
+                appBranches := branches
+                if app.Labels[preview.LabelApp] != baseName {
+                    appBranches = nil
+                }
+                reason := preview.Stale(app, ttl, appBranches, now)
+                if reason == "" {
+                    continue
+                }
+                if dryRun {
+                    fmt.Printf("Would delete %s (%s)\n", app.Name, reason)
+                    continue
+                }
+                if err := api.DeleteApp(app.Name); err != nil {
+                    fmt.Fprintf(os.Stderr, "Failed to delete %s: %v\n", app.Name, err)
+                    failed++
+                    continue
+                }
+                fmt.Printf("Deleted %s (%s)\n", app.Name, reason)
+                deleted++
+            }
+
+            if !dryRun {
+                fmt.Printf("%d of %d previews deleted\n", deleted, len(apps))
+            }
+            if failed > 0 {
+                return fmt.Errorf("failed to delete %d previews", failed)
+            }
+            return nil
+        },
+    }
+
+    cmd.Flags().BoolVar(&all, "all", false, "Collect previews of all applications")
+    cmd.Flags().DurationVar(&ttl, "ttl", 7*24*time.Hour, "Delete previews deployed longer ago than this (0 to disable)")
+    cmd.Flags().StringVar(&remote, "remote", "origin", "Git remote whose branches are checked")
+    cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print the previews that would be deleted")
+
+    return cmd
+}
+
+// previewBranch returns the branch given with --branch or the current one
+func previewBranch(branch string) (string, error) {
+    if branch != "" {
+        return branch, nil
+    }
+    branch, err := preview.CurrentBranch(".")
+    if err != nil {
+        return "", fmt.Errorf("failed to detect the git branch: %v. Use --branch", err)
+    }
+    return branch, nil
+}
+
+// previewBaseName returns the application previews are made of, read from the config file
+func previewBaseName(configPath string) (string, error) {
+    path, err := findConfigFile(configPath)
+    if err != nil {
+        return "", err
+    }
+    cfg := &config.Config{}
+    if err := cfg.LoadFromFile(path); err != nil {
+        return "", fmt.Errorf("failed to load config: %v", err)
+    }
+    if cfg.AppName == "" {
+        return "", fmt.Errorf("appName is required in %s to name previews", path)
+    }
+    return cfg.AppName, nil
+}
+
+// listPreviews returns the previews of an application, or of all
+// applications when baseName is empty, sorted by name
+func listPreviews(api *api.GhaymahAPI, baseName string) ([]types.AppSummary, error) {
+    resp, err := api.ListAppsByLabel(preview.Selector(baseName))
+    if err != nil {
+        return nil, err
+    }
+
+    apps := resp.Apps
+    sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+    return apps, nil
+}
+
+// checkPreviewApp ensures the application called name, if it exists, is the
+// preview of base for branch, so neither a regular application nor the
+// preview of another branch with the same name is replaced or deleted.
+// Reports whether the application exists.
+func checkPreviewApp(api *api.GhaymahAPI, name, base, branch, action string) (bool, error) {
+    apps, err := api.ListApps()
+    if err != nil {
+        return false, fmt.Errorf("failed to list applications: %v", err)
+    }
+    for _, app := range apps.Apps {
+        if app.Name != name {
+            continue
+        }
+        switch {
+        case preview.IsPreviewOf(app, base, branch):
+            return true, nil
+        case app.Labels[preview.LabelPreview] != "true":
+            return true, fmt.Errorf("%s is not a preview application, refusing to %s it", name, action)
+        default:
+            return true, fmt.Errorf("%s is the preview of branch %s of %s, refusing to %s it for branch %s",
+                name, app.Labels[preview.LabelBranch], app.Labels[preview.LabelApp], action, branch)
+        }
+    }
+    return false, nil
+}
+
+// formatAge formats the time elapsed since t, e.g. 3h or 2d
+func formatAge(t time.Time) string {
+    if t.IsZero() {
+        return "-"
+    }
+    d := time.Since(t)
+    switch {
+    case d < time.Minute:
+        return fmt.Sprintf("%ds", int(d.Seconds()))
+    case d < time.Hour:
+        return fmt.Sprintf("%dm", int(d.Minutes()))
+    case d < 24*time.Hour:
+        return fmt.Sprintf("%dh", int(d.Hours()))
+    }
+    return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "sort"
+    "strings"
+    "sync"
+    "text/tabwriter"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/config"
+    "ghaymah-cli/pkg/types"
+)
+
+// Latency measurement settings: each region is pinged a few times and the
+// fastest answer kept, so connection setup doesn't count
+const (
+    pingAttempts = 3
+    pingTimeout  = 2 * time.Second
+)
+
+// NewRegionsCommand creates a new regions command
+func NewRegionsCommand(api *api.GhaymahAPI) *cobra.Command {
+    return &cobra.Command{
+        Use:   "regions",
+        Short: "List the regions applications can be deployed to",
+        Long: `List the regions applications can be deployed to, with how much of their
+capacity is used and the latency from this machine. Limited regions are running
+out of capacity; unavailable regions don't accept new deployments.
+
+Deploy to a region with region: in the config file or --region, or to several
+regions in parallel with regions:.`,
+        Args: cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            regions, err := api.ListRegions()
+            if err != nil {
+                return fmt.Errorf("failed to list regions: %v", err)
+            }
+            if len(regions.Regions) == 0 {
+                fmt.Println("No regions found")
+                return nil
+            }
+
+            latencies := measureLatencies(regions.Regions)
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "NAME\tLOCATION\tSTATUS\tCAPACITY USED\tLATENCY")
+            for _, region := range regions.Regions {
+                name := region.Name
+                if region.Default {
+                    name += " (default)"
+                }
+                latency := "-"
+                if d, ok := latencies[region.Name]; ok {
+                    latency = fmt.Sprintf("%dms", d.Milliseconds())
+                }
+                fmt.Fprintf(w, "%s\t%s\t%s\t%.0f%%\t%s\n", name, region.DisplayName, region.Status, region.CapacityUsed, latency)
+            }
+            return w.Flush()
+        },
+    }
+}
+
+// measureLatencies pings the regions in parallel, leaving out the ones that
+// can't be reached
+func measureLatencies(regions []types.Region) map[string]time.Duration {
+    var (
+        mu        sync.Mutex
+        wg        sync.WaitGroup
+        latencies = map[string]time.Duration{}
+    )
+    client := &http.Client{Timeout: pingTimeout}
+    for _, region := range regions {
+        if region.PingURL == "" {
+            continue
+        }
+        wg.Add(1)
+        go func(region types.Region) {
+            defer wg.Done()
+            best := time.Duration(0)
+            for i := 0; i < pingAttempts; i++ {
+                start := time.Now()
+                resp, err := client.Get(region.PingURL)
+                if err != nil {
+                    continue
+                }
+                d := time.Since(start)
+                // Reading the body lets the next attempt reuse the connection
+                io.Copy(io.Discard, resp.Body)
+                resp.Body.Close()
+                if resp.StatusCode < 200 || resp.StatusCode > 299 {
+                    continue
+                }
+                if best == 0 || d < best {
+                    best = d
+                }
+            }
+            if best > 0 {
+                mu.Lock()
+                latencies[region.Name] = best
+                mu.Unlock()
+            }
+        }(region)
+    }
+    wg.Wait()
+    return latencies
+}
+
+// checkRegions ensures the regions exist and accept new deployments, suggesting
+// the closest name on typos. Regions running out of capacity are warned about.
+func checkRegions(api *api.GhaymahAPI, names []string) error {
+    if len(names) == 0 {
+        return nil
+    }
+    regions, err := api.ListRegions()
+    if err != nil {
+        return fmt.Errorf("failed to list regions: %v", err)
+    }
+
+    known := map[string]types.Region{}
+    available := []string{}
+    for _, region := range regions.Regions {
+        known[region.Name] = region
+        available = append(available, region.Name)
+    }
+    for _, name := range names {
+        region, ok := known[name]
+        switch {
+        case !ok:
+            if suggestion := config.ClosestMatch(name, available); suggestion != "" {
+                return fmt.Errorf("unknown region %q (did you mean %s?). List regions with: ghaymah regions", name, suggestion)
+            }
+            return fmt.Errorf("unknown region %q (expected one of %s)", name, strings.Join(available, ", "))
+        case region.Status == types.RegionUnavailable:
+            return fmt.Errorf("region %s doesn't accept new deployments. List regions with: ghaymah regions", name)
+        case region.Status == types.RegionLimited:
+            fmt.Fprintf(os.Stderr, "Warning: region %s is running out of capacity (%.0f%% used)\n", name, region.CapacityUsed)
+        }
+    }
+    return nil
+}
+
+// printRegions shows the state of the application in each of its regions
+func printRegions(regions []types.RegionStatus) {
+    if len(regions) == 0 {
+        return
+    }
+
+    fmt.Printf("Regions: %s\n", regionsSummary(regions))
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "  REGION\tSTATE\tRELEASE\tINSTANCES\tURL")
+    for _, r := range regions {
+        release := "-"
+        if r.Version > 0 {
+            release = fmt.Sprintf("v%d", r.Version)
+        }
+        fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%s\n", r.Region, r.State, release, r.Instances, r.URL)
+    }
+    w.Flush()
+}
+
+// regionsSummary aggregates the states of the regions of an application,
+// e.g. "running in 2 of 3 (deploying in eu-west-1)"
+func regionsSummary(regions []types.RegionStatus) string {
+    byState := map[string][]string{}
+    for _, r := range regions {
+        byState[r.State] = append(byState[r.State], r.Region)
+    }
+    if len(byState) == 1 {
+        return fmt.Sprintf("%s in all %d", regions[0].State, len(regions))
+    }
+
+    running := len(byState[types.AppRunning])
+    others := []string{}
+    for state, names := range byState {
+        if state != types.AppRunning {
+            others = append(others, fmt.Sprintf("%s in %s", state, strings.Join(names, ", ")))
+        }
+    }
+    sort.Strings(others)
+    return fmt.Sprintf("running in %d of %d (%s)", running, len(regions), strings.Join(others, "; "))
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+    "fmt"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// rolloutPollInterval is how often rollout status --watch checks progress
+const rolloutPollInterval = 2 * time.Second
+
+// NewRolloutCommand creates a new rollout command
+func NewRolloutCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "rollout",
+        Short: "Follow and control progressive deployments",
+        Long: `Follow and control the rollout of a new release deployed with a strategy
+(see strategy: in the configuration file).
+
+Rolling updates replace instances progressively. Blue-green deployments start
+the new release next to the current one and switch all traffic on promote.
+Canary deployments shift traffic in the steps configured, pausing between them.
+A rollout is aborted automatically if the new release fails its health checks.
+
+The application name can be given as an argument or with --name. When omitted, it is
+read from appName in the config file, or from the app linked with 'ghaymah link'.
+
+Examples:
+  # Show the progress of the current rollout
+  ghaymah rollout status my-app
+
+  # Wait until the rollout completes (exits with an error if it is aborted or
+  # takes longer than --timeout, and with a hint if it pauses for promotion)
+  ghaymah rollout status --watch --timeout 30m
+
+  # Move a paused canary to its next step
+  ghaymah rollout promote my-app
+
+  # Skip the remaining steps and send all traffic to the new release
+  ghaymah rollout promote my-app --full
+
+  # Send all traffic back to the previous release
+  ghaymah rollout abort my-app`,
+    }
+
+    cmd.PersistentFlags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.PersistentFlags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    resolve := func(args []string) (string, error) {
+        return resolveAppName(args, appName, configPath, envName)
+    }
+
+    var (
+        watch   bool
+        timeout time.Duration
+        full    bool
+    )
+
+    statusCmd := &cobra.Command{
+        Use:               "status [APP_NAME]",
+        Short:             "Show the progress of the current rollout",
+        Args:              cobra.MaximumNArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve(args)
+            if err != nil {
+                return err
+            }
+
+            rollout, err := api.GetRollout(name)
+            if err != nil {
+                return fmt.Errorf("failed to get rollout: %v", err)
+            }
+            printRollout(name, rollout)
+            if !watch {
+                return nil
+            }
+
+            last := rolloutSummary(rollout)
+            deadline := time.Now().Add(timeout)
+            for !rollout.Done() {
+                // A paused rollout only moves on when promoted
+                if rollout.State == types.RolloutPaused {
+                    fmt.Printf("Rollout paused at step %d/%d, waiting for promotion. Continue it with: ghaymah rollout promote %s\n",
+                        rollout.Step, rollout.TotalSteps, name)
+                    return nil
+                }
+                if timeout > 0 && time.Now().After(deadline) {
+                    return fmt.Errorf("timed out after %s waiting for the rollout of release v%d (%s)", timeout, rollout.Version, rolloutSummary(rollout))
+                }
+                time.Sleep(rolloutPollInterval)
+                if rollout, err = api.GetRollout(name); err != nil {
+                    return fmt.Errorf("failed to get rollout: %v", err)
+                }
+                if summary := rolloutSummary(rollout); summary != last {
+                    fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), summary)
+                    last = summary
+                }
+            }
+
+            if rollout.State == types.RolloutAborted {
+                return fmt.Errorf("rollout of release v%d was aborted: %s", rollout.Version, rollout.Message)
+            }
+            return nil
+        },
+    }
+    statusCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Wait until the rollout completes, is aborted or pauses for promotion")
+    statusCmd.Flags().DurationVar(&timeout, "timeout", 0, "How long --watch waits before failing (0 waits until the rollout ends)")
+
+    promoteCmd := &cobra.Command{
+        Use:               "promote [APP_NAME]",
+        Short:             "Move the rollout to its next step",
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve(args)
+            if err != nil {
+                return err
+            }
+
+            rollout, err := api.PromoteRollout(name, full)
+            if err != nil {
+                return deployError("rollout promotion", name, err)
+            }
+            fmt.Printf("Rollout of %s promoted\n", name)
+            printRollout(name, rollout)
+            return nil
+        },
+    }
+    promoteCmd.Flags().BoolVar(&full, "full", false, "Skip the remaining steps and send all traffic to the new release")
+
+    abortCmd := &cobra.Command{
+        Use:               "abort [APP_NAME]",
+        Short:             "Abort the rollout and restore the previous release",
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve(args)
+            if err != nil {
+                return err
+            }
+
+            rollout, err := api.AbortRollout(name)
+            if err != nil {
+                return deployError("rollout abort", name, err)
+            }
+            fmt.Printf("Rollout of %s aborted\n", name)
+            printRollout(name, rollout)
+            return nil
+        },
+    }
+
+    cmd.AddCommand(statusCmd, promoteCmd, abortCmd)
+
+    return cmd
+}
+
+// printRollout shows the progress of a rollout
+func printRollout(appName string, rollout *types.Rollout) {
+    fmt.Printf("\nApplication: %s\n", appName)
+    fmt.Printf("Strategy: %s\n", rollout.Strategy)
+    if rollout.PreviousVersion > 0 {
+        fmt.Printf("Release: v%d (replacing v%d)\n", rollout.Version, rollout.PreviousVersion)
+    } else {
+        fmt.Printf("Release: v%d\n", rollout.Version)
+    }
+    fmt.Printf("State: %s\n", rollout.State)
+    if rollout.TotalSteps > 0 {
+        fmt.Printf("Step: %d/%d\n", rollout.Step, rollout.TotalSteps)
+    }
+    fmt.Printf("Traffic: %d%% new, %d%% previous\n", rollout.TrafficWeight, 100-rollout.TrafficWeight)
+    if rollout.Health != "" {
+        fmt.Printf("Health: %s\n", rollout.Health)
+    }
+    if rollout.Message != "" {
+        fmt.Printf("Message: %s\n", rollout.Message)
+    }
+    if !rollout.StartedAt.IsZero() {
+        fmt.Printf("Started: %s\n", rollout.StartedAt.Format("2006-01-02 15:04:05"))
+    }
+}
+
+// rolloutSummary describes a rollout on one line, for watching progress
+func rolloutSummary(rollout *types.Rollout) string {
+    summary := rollout.State
+    if rollout.TotalSteps > 0 {
+        summary += fmt.Sprintf(", step %d/%d", rollout.Step, rollout.TotalSteps)
+    }
+    summary += fmt.Sprintf(", %d%% traffic to v%d", rollout.TrafficWeight, rollout.Version)
+    if rollout.Message != "" {
+        summary += ": " + rollout.Message
+    }
+    return summary
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+    "fmt"
+    "strings"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// NewRunCommand creates a new run command
+func NewRunCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+        image      string
+        envVars    []string
+        cpu        string
+        memory     string
+        detach     bool
+    )
+
+    cmd := &cobra.Command{
+        Use:   "run [APP_NAME] [flags] -- COMMAND [ARG...]",
+        Short: "Run a one-off task in a container of an application",
+        Long: `Run a one-off command, such as a database migration, in a new container of an
+application. The container uses the image of the current release and the
+environment variables of the application, and is removed once the command
+exits. Its output is streamed until then, and the exit code is reported.
+
+The application is given before -- or with --name. When omitted, it is read
+from appName in the config file, or from the app linked with 'ghaymah link'.
+Flags of the command itself must come after --.
+
+Examples:
+  # Run migrations with the image of the current release
+  ghaymah run shop -- npm run migrate
+
+  # Run a script with an extra environment variable
+  ghaymah run -e DRY_RUN=1 -- ./scripts/cleanup.sh --older-than 30d
+
+  # Start a long task without waiting for it, then follow it later
+  ghaymah run --detach --memory 2G -- python reindex.py
+  ghaymah jobs logs --run <ID> --follow`,
+        Args:         cobra.MinimumNArgs(1),
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            // Without --, all the arguments are the command
+            var nameArgs []string
+            if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+                nameArgs, args = args[:dash], args[dash:]
+            }
+            if len(nameArgs) > 1 {
+                return fmt.Errorf("expected at most one application name before --, got %s", strings.Join(nameArgs, " "))
+            }
+            if len(args) == 0 {
+                return fmt.Errorf("no command given after --")
+            }
+
+            name, err := resolveAppName(nameArgs, appName, configPath, envName)
+            if err != nil {
+                return err
+            }
+
+            options := &types.TaskOptions{Command: args, Image: image}
+            if cpu != "" || memory != "" {
+                options.Resources = &types.ResourceConfig{CPU: cpu, Memory: memory}
+            }
+            if len(envVars) > 0 {
+                options.EnvVars = map[string]string{}
+                for _, env := range envVars {
+                    key, value, ok := strings.Cut(env, "=")
+                    if !ok || key == "" {
+                        return fmt.Errorf("invalid environment variable %q, expected KEY=VALUE", env)
+                    }
+                    options.EnvVars[key] = value
+                }
+            }
+
+            run, err := api.RunTask(name, options)
+            if err != nil {
+                return fmt.Errorf("failed to run task: %v", err)
+            }
+
+            fmt.Printf("Running %q in %s (task %s)...\n", strings.Join(args, " "), name, run.ID)
+            if detach {
+                fmt.Printf("Follow its output with: ghaymah jobs logs --run %s --follow --name %s\n", run.ID, name)
+                return nil
+            }
+            return followRun(api, name, run, false)
+        },
+    }
+
+    cmd.Flags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.Flags().StringVar(&image, "image", "", "Docker image to run instead of the one of the current release")
+    cmd.Flags().StringArrayVarP(&envVars, "env", "e", nil, "Extra environment variable KEY=VALUE (repeatable)")
+    cmd.Flags().StringVar(&cpu, "cpu", "", "Number of CPU cores of the task (default: the application's)")
+    cmd.Flags().StringVar(&memory, "memory", "", "Memory limit of the task, e.g. 1G (default: the application's)")
+    cmd.Flags().BoolVarP(&detach, "detach", "d", false, "Start the task without streaming its output")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    return cmd
+}
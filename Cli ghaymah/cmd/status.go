@@ -2,48 +2,257 @@ package cmd
 
 import (
     "fmt"
+    "os"
+    "sort"
+    "text/tabwriter"
+    "time"
     "github.com/spf13/cobra"
     "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
 )
 
 // NewStatusCommand creates a new status command
 func NewStatusCommand(api *api.GhaymahAPI) *cobra.Command {
-    var appName string
+    var (
+        appName    string
+        configPath string
+        envName    string
+        detailed   bool
+    )
 
     cmd := &cobra.Command{
-        Use:   "status",
+        Use:   "status [APP_NAME]",
         Short: "Check application status",
         Long: `View the current status of your application on Ghaymah Cloud.
 This includes deployment status, resource usage, and health metrics.
 
-Example:
-  ghaymah status --name my-app`,
+The application name can be given as an argument or with --name. When omitted, it is
+read from appName in the config file, or from the app linked with 'ghaymah link'.
+
+Examples:
+  # Basic status check
+  ghaymah status my-app
+
+  # Use the app of the config file found in this or a parent directory
+  ghaymah status
+
+  # Include instances, recent events, resource limits and health checks
+  ghaymah status --name my-app --detailed`,
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
         RunE: func(cmd *cobra.Command, args []string) error {
-            if appName == "" {
-                return fmt.Errorf("application name is required. Use --name flag")
+            appName, err := resolveAppName(args, appName, configPath, envName)
+            if err != nil {
+                return err
             }
 
             fmt.Printf("Checking status for application %s...\n", appName)
 
-            status, err := api.GetStatus(appName)
+            var status *types.StatusResponse
+            if detailed {
+                status, err = api.GetDetailedStatus(appName)
+            } else {
+                status, err = api.GetStatus(appName)
+            }
             if err != nil {
                 return fmt.Errorf("failed to get status: %v", err)
             }
 
             fmt.Printf("\nStatus: %s\n", status.State)
-            fmt.Printf("Last Deployment: %s\n\n", status.LastDeployment.Format("2006-01-02 15:04:05"))
+            fmt.Printf("Last Deployment: %s\n", status.LastDeployment.Format("2006-01-02 15:04:05"))
+            if status.Version > 0 {
+                fmt.Printf("Release: v%d\n", status.Version)
+            }
+            if status.Lock != nil {
+                fmt.Printf("Locked: %s", status.Lock.Reason)
+                if status.Lock.LockedBy != "" {
+                    fmt.Printf(" (by %s since %s)", status.Lock.LockedBy, status.Lock.LockedAt.Format("2006-01-02 15:04:05"))
+                }
+                fmt.Println()
+            }
+            printRegions(status.Regions)
+            fmt.Println()
 
-            fmt.Println("Resource Usage:")
-            fmt.Printf("  CPU: %.2f%%\n", status.Resources.CPUUsage)
-            fmt.Printf("  Memory: %.2f%%\n", status.Resources.MemoryUsage)
-            fmt.Printf("  Storage: %.2f%%\n", status.Resources.StorageUsage)
+            if detailed && status.Usage != nil {
+                printUsage(status.Usage)
+            } else {
+                fmt.Println("Resource Usage:")
+                fmt.Printf("  CPU: %.2f%%\n", status.Resources.CPUUsage)
+                fmt.Printf("  Memory: %.2f%%\n", status.Resources.MemoryUsage)
+                fmt.Printf("  Storage: %.2f%%\n", status.Resources.StorageUsage)
+            }
+
+            printVolumes(status.Volumes)
+
+            if detailed {
+                printRelease(status.Release)
+                printInstances(status.Instances)
+                printHealth(status)
+                printEvents(status.Events)
+            }
 
             return nil
         },
     }
 
-    cmd.Flags().StringVar(&appName, "name", "", "Application name")
-    cmd.MarkFlagRequired("name")
+    cmd.Flags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.Flags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.Flags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.Flags().BoolVarP(&detailed, "detailed", "d", false, "Show instances, events, resource limits and health checks")
+
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
 
     return cmd
 }
+
+// printHealth shows health check results per instance
+func printHealth(status *types.StatusResponse) {
+    fmt.Println()
+    if status.Health == nil {
+        fmt.Println("Health: no health check configured")
+        return
+    }
+
+    fmt.Printf("Health: %s\n", status.Health.Status)
+    if len(status.Health.Instances) == 0 {
+        return
+    }
+
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "  INSTANCE\tHEALTH\tLAST CHECK\tFAILURES\tLAST FAILURE")
+    for _, inst := range status.Health.Instances {
+        lastCheck := "-"
+        if !inst.LastCheck.IsZero() {
+            lastCheck = inst.LastCheck.Format(time.RFC3339)
+        }
+        lastFailure := "-"
+        if inst.LastFailure != "" {
+            lastFailure = inst.LastFailure
+            if inst.LastFailureAt != nil {
+                lastFailure = fmt.Sprintf("%s (%s)", inst.LastFailure, inst.LastFailureAt.Format(time.RFC3339))
+            }
+        }
+        fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%s\n", inst.Instance, inst.Status, lastCheck, inst.ConsecutiveFailures, lastFailure)
+    }
+    w.Flush()
+}
+
+// printUsage shows resource usage against limits in absolute units
+func printUsage(usage *types.ResourceUsageDetail) {
+    fmt.Println("Resource Usage:")
+    fmt.Printf("  CPU: %.2f / %.2f cores (%s)\n", usage.CPU.Used, usage.CPU.Limit, percentOf(usage.CPU))
+    fmt.Printf("  Memory: %s / %s (%s)\n", formatBytes(usage.Memory.Used), formatBytes(usage.Memory.Limit), percentOf(usage.Memory))
+    fmt.Printf("  Storage: %s / %s (%s)\n", formatBytes(usage.Storage.Used), formatBytes(usage.Storage.Limit), percentOf(usage.Storage))
+}
+
+// printVolumes shows the usage of the volumes mounted by the application
+func printVolumes(volumes []types.VolumeUsage) {
+    if len(volumes) == 0 {
+        return
+    }
+
+    fmt.Println("\nVolumes:")
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "  VOLUME\tMOUNT\tUSED\tSIZE\tUSE%")
+    for _, v := range volumes {
+        mount := v.MountPath
+        if v.ReadOnly {
+            mount += " (ro)"
+        }
+        usage := types.ResourceQuantity{Used: v.UsedBytes, Limit: v.SizeBytes}
+        fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", v.Name, mount, formatBytes(v.UsedBytes), formatBytes(v.SizeBytes), percentOf(usage))
+    }
+    w.Flush()
+}
+
+// printRelease shows the release currently running
+func printRelease(release *types.ReleaseInfo) {
+    if release == nil {
+        return
+    }
+
+    fmt.Println("\nRelease:")
+    fmt.Printf("  Version: v%d\n", release.Version)
+    fmt.Printf("  Image: %s\n", release.Image)
+    if release.ImageDigest != "" {
+        fmt.Printf("  Digest: %s\n", release.ImageDigest)
+    }
+    if !release.DeployedAt.IsZero() {
+        fmt.Printf("  Deployed: %s\n", release.DeployedAt.Format("2006-01-02 15:04:05"))
+    }
+}
+
+// printInstances shows the state and restart count of each instance
+func printInstances(instances []types.InstanceStatus) {
+    if len(instances) == 0 {
+        return
+    }
+
+    fmt.Println("\nInstances:")
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "  INSTANCE\tSTATE\tRESTARTS\tAGE\tLAST EXIT")
+    for _, inst := range instances {
+        age := "-"
+        if !inst.StartedAt.IsZero() {
+            age = time.Since(inst.StartedAt).Round(time.Second).String()
+        }
+        lastExit := "-"
+        if inst.LastExitReason != "" {
+            lastExit = inst.LastExitReason
+        }
+        fmt.Fprintf(w, "  %s\t%s\t%d\t%s\t%s\n", inst.ID, inst.State, inst.Restarts, age, lastExit)
+    }
+    w.Flush()
+}
+
+// printEvents shows recent platform events, oldest first
+func printEvents(events []types.PlatformEvent) {
+    fmt.Println()
+    if len(events) == 0 {
+        fmt.Println("Events: none")
+        return
+    }
+
+    sorted := make([]types.PlatformEvent, len(events))
+    copy(sorted, events)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+    })
+
+    fmt.Println("Recent Events:")
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "  TIME\tTYPE\tREASON\tINSTANCE\tMESSAGE")
+    for _, e := range sorted {
+        instance := "-"
+        if e.Instance != "" {
+            instance = e.Instance
+        }
+        fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", e.Timestamp.Format(time.RFC3339), e.Type, e.Reason, instance, e.Message)
+    }
+    w.Flush()
+}
+
+// percentOf formats a used quantity as a percentage of its limit
+func percentOf(q types.ResourceQuantity) string {
+    if q.Limit <= 0 {
+        return "no limit"
+    }
+    return fmt.Sprintf("%.1f%%", q.Used/q.Limit*100)
+}
+
+// formatBytes renders a byte count using binary units
+func formatBytes(b float64) string {
+    const unit = 1024
+    if b < unit {
+        return fmt.Sprintf("%.0fB", b)
+    }
+    units := []string{"KiB", "MiB", "GiB", "TiB"}
+    value := b / unit
+    i := 0
+    for value >= unit && i < len(units)-1 {
+        value /= unit
+        i++
+    }
+    return fmt.Sprintf("%.1f%s", value, units[i])
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+    "fmt"
+    "net/http"
+    "os"
+    "text/tabwriter"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+)
+
+// NewVolumesCommand creates a new volumes command
+func NewVolumesCommand(api *api.GhaymahAPI) *cobra.Command {
+    cmd := &cobra.Command{
+        Use:   "volumes",
+        Short: "Manage persistent volumes",
+        Long: `Manage named persistent volumes. Unlike resources.storage, whose data is lost
+on redeploy, the data of a volume survives redeploys and restarts. Volumes are
+mounted by listing them under volumes: in the config file:
+
+  volumes:
+    - name: shop-uploads
+      mountPath: /data/uploads
+
+A volume is mounted by one application at a time, and can't be deleted while
+it is mounted: remove it from the config file and deploy first.
+
+Examples:
+  # Create a 10 GiB volume
+  ghaymah volumes create shop-uploads --size 10G
+
+  # List volumes with their usage and the app mounting them
+  ghaymah volumes list
+
+  # Grow a volume (volumes can't shrink)
+  ghaymah volumes resize shop-uploads --size 20G
+
+  # Snapshot a volume, list its snapshots and restore one into a new volume
+  ghaymah volumes snapshot shop-uploads --description "before migration"
+  ghaymah volumes snapshots shop-uploads
+  ghaymah volumes restore shop-uploads --snapshot snap-1a2b3c4d --to shop-uploads-copy`,
+    }
+
+    cmd.AddCommand(
+        newVolumesCreateCommand(api),
+        newVolumesListCommand(api),
+        newVolumesDeleteCommand(api),
+        newVolumesResizeCommand(api),
+        newVolumesSnapshotCommand(api),
+        newVolumesSnapshotsCommand(api),
+        newVolumesRestoreCommand(api),
+    )
+
+    return cmd
+}
+
+// newVolumesCreateCommand creates the volumes create subcommand
+func newVolumesCreateCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        size   string
+        region string
+    )
+
+    cmd := &cobra.Command{
+        Use:          "create NAME",
+        Short:        "Create a volume",
+        Args:         cobra.ExactArgs(1),
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if _, err := types.ParseSize(size); err != nil {
+                return err
+            }
+
+            volume, err := api.CreateVolume(args[0], size, region)
+            if err != nil {
+                return volumeError("create", args[0], err)
+            }
+
+            fmt.Printf("Volume %s created (%s", volume.Name, formatBytes(volume.SizeBytes))
+            if volume.Region != "" {
+                fmt.Printf(" in %s", volume.Region)
+            }
+            fmt.Println(")")
+            fmt.Printf("Mount it by adding it under volumes: in the config file, e.g.\n  volumes:\n    - name: %s\n      mountPath: /data\n", volume.Name)
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&size, "size", "", "Size of the volume, e.g. 10G")
+    cmd.Flags().StringVar(&region, "region", "", "Region of the volume (default: the account's default region)")
+    cmd.MarkFlagRequired("size")
+    cmd.RegisterFlagCompletionFunc("region", completeRegions(api))
+
+    return cmd
+}
+
+// newVolumesListCommand creates the volumes list subcommand
+func newVolumesListCommand(api *api.GhaymahAPI) *cobra.Command {
+    return &cobra.Command{
+        Use:   "list",
+        Short: "List volumes",
+        Args:  cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            volumes, err := api.ListVolumes()
+            if err != nil {
+                return fmt.Errorf("failed to list volumes: %v", err)
+            }
+            if len(volumes.Volumes) == 0 {
+                fmt.Println("No volumes found")
+                return nil
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "NAME\tSIZE\tUSED\tREGION\tSTATE\tMOUNTED BY\tAGE")
+            for _, volume := range volumes.Volumes {
+                usage := types.ResourceQuantity{Used: volume.UsedBytes, Limit: volume.SizeBytes}
+                mountedBy := "-"
+                if volume.AttachedTo != "" {
+                    mountedBy = volume.AttachedTo + ":" + volume.MountPath
+                }
+                region := volume.Region
+                if region == "" {
+                    region = "-"
+                }
+                fmt.Fprintf(w, "%s\t%s\t%s (%s)\t%s\t%s\t%s\t%s\n",
+                    volume.Name,
+                    formatBytes(volume.SizeBytes),
+                    formatBytes(volume.UsedBytes),
+                    percentOf(usage),
+                    region,
+                    volume.State,
+                    mountedBy,
+                    formatAge(volume.CreatedAt),
+                )
+            }
+            return w.Flush()
+        },
+    }
+}
+
+// newVolumesDeleteCommand creates the volumes delete subcommand
+func newVolumesDeleteCommand(api *api.GhaymahAPI) *cobra.Command {
+    return &cobra.Command{
+        Use:               "delete NAME",
+        Short:             "Delete a volume and its data",
+        Long:              "Delete a volume and its data. Its snapshots are deleted too. Mounted volumes can't be deleted.",
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeVolumeNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if err := api.DeleteVolume(args[0]); err != nil {
+                return volumeError("delete", args[0], err)
+            }
+
+            fmt.Printf("Volume %s deleted\n", args[0])
+            return nil
+        },
+    }
+}
+
+// newVolumesResizeCommand creates the volumes resize subcommand
+func newVolumesResizeCommand(api *api.GhaymahAPI) *cobra.Command {
+    var size string
+
+    cmd := &cobra.Command{
+        Use:               "resize NAME",
+        Short:             "Grow a volume",
+        Long:              "Grow a volume to a larger size. Mounted volumes are resized online, without restarting the application.",
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeVolumeNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if _, err := types.ParseSize(size); err != nil {
+                return err
+            }
+
+            volume, err := api.ResizeVolume(args[0], size)
+            if err != nil {
+                return volumeError("resize", args[0], err)
+            }
+
+            fmt.Printf("Volume %s resized to %s\n", volume.Name, formatBytes(volume.SizeBytes))
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&size, "size", "", "New size of the volume, e.g. 20G")
+    cmd.MarkFlagRequired("size")
+
+    return cmd
+}
+
+// newVolumesSnapshotCommand creates the volumes snapshot subcommand
+func newVolumesSnapshotCommand(api *api.GhaymahAPI) *cobra.Command {
+    var description string
+
+    cmd := &cobra.Command{
+        Use:               "snapshot NAME",
+        Short:             "Take a snapshot of a volume",
+        Args:              cobra.ExactArgs(1),
+        ValidArgsFunction: completeVolumeNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            snapshot, err := api.CreateVolumeSnapshot(args[0], description)
+            if err != nil {
+                return volumeError("snapshot", args[0], err)
+            }
+
+            fmt.Printf("Snapshot %s of volume %s created (%s)\n", snapshot.ID, snapshot.Volume, formatBytes(snapshot.SizeBytes))
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&description, "description", "", "Why the snapshot was taken")
+
+    return cmd
+}
+
+// newVolumesSnapshotsCommand creates the volumes snapshots subcommand
+func newVolumesSnapshotsCommand(api *api.GhaymahAPI) *cobra.Command {
+    return &cobra.Command{
+        Use:               "snapshots NAME",
+        Short:             "List the snapshots of a volume",
+        Args:              cobra.ExactArgs(1),
+        ValidArgsFunction: completeVolumeNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            snapshots, err := api.ListVolumeSnapshots(args[0])
+            if err != nil {
+                return volumeError("list the snapshots of", args[0], err)
+            }
+            if len(snapshots.Snapshots) == 0 {
+                fmt.Printf("No snapshots of volume %s\n", args[0])
+                return nil
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "ID\tSIZE\tCREATED\tDESCRIPTION")
+            for _, snapshot := range snapshots.Snapshots {
+                fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+                    snapshot.ID,
+                    formatBytes(snapshot.SizeBytes),
+                    snapshot.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+                    snapshot.Description,
+                )
+            }
+            return w.Flush()
+        },
+    }
+}
+
+// newVolumesRestoreCommand creates the volumes restore subcommand
+func newVolumesRestoreCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        snapshotID string
+        target     string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "restore NAME",
+        Short: "Restore a snapshot of a volume",
+        Long: `Restore a snapshot into a new volume given with --to, or into the volume
+itself, replacing its data. A mounted volume can only be restored in place
+while the application mounting it is stopped ('ghaymah stop').`,
+        Args:              cobra.ExactArgs(1),
+        SilenceUsage:      true,
+        ValidArgsFunction: completeVolumeNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            volume, err := api.RestoreVolumeSnapshot(args[0], snapshotID, target)
+            if err != nil {
+                return volumeError("restore", args[0], err)
+            }
+
+            if target != "" {
+                fmt.Printf("Snapshot %s restored into new volume %s\n", snapshotID, volume.Name)
+            } else {
+                fmt.Printf("Volume %s restored from snapshot %s\n", volume.Name, snapshotID)
+            }
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&snapshotID, "snapshot", "", "ID of the snapshot to restore (see 'ghaymah volumes snapshots')")
+    cmd.Flags().StringVar(&target, "to", "", "Create a new volume with this name instead of replacing the data of the volume")
+    cmd.MarkFlagRequired("snapshot")
+
+    return cmd
+}
+
+// volumeError explains why the API rejected an action on a volume
+func volumeError(action, name string, err error) error {
+    switch {
+    case api.IsStatus(err, http.StatusNotFound):
+        return fmt.Errorf("%s", api.StatusMessage(err))
+    case action == "delete" && api.IsStatus(err, http.StatusConflict):
+        return fmt.Errorf("can't delete volume %s: %s. Remove it from volumes: in the config file and deploy first", name, api.StatusMessage(err))
+    case api.IsStatus(err, http.StatusBadRequest), api.IsStatus(err, http.StatusConflict):
+        return fmt.Errorf("can't %s volume %s: %s", action, name, api.StatusMessage(err))
+    }
+    return fmt.Errorf("failed to %s volume %s: %v", action, name, err)
+}
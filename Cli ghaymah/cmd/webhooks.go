@@ -0,0 +1,290 @@
+package cmd
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "text/tabwriter"
+    "time"
+    "github.com/spf13/cobra"
+    "ghaymah-cli/pkg/api"
+    "ghaymah-cli/pkg/types"
+    "ghaymah-cli/pkg/webhooks"
+)
+
+// webhookSecretEnvVar holds the signing secret used by webhooks verify
+const webhookSecretEnvVar = "GHAYMAH_WEBHOOK_SECRET"
+
+// NewWebhooksCommand creates a new webhooks command
+func NewWebhooksCommand(api *api.GhaymahAPI) *cobra.Command {
+    var (
+        appName    string
+        configPath string
+        envName    string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "webhooks",
+        Short: "Send application events to your own services",
+        Long: `Subscribe URLs to events of an application. Each event is sent as a JSON POST:
+
+  {"id": "evt_...", "type": "deploy.succeeded", "app": "my-app",
+   "timestamp": "2024-01-23T10:00:00Z", "data": {"version": 12, "image": "..."}}
+
+Events: deploy.started, deploy.succeeded, deploy.failed, app.crashed.
+
+Deliveries are signed with the secret of the webhook. The X-Ghaymah-Signature
+header is t=<unix time>,v1=<signature>, the signature being the hex
+HMAC-SHA256 of "<unix time>.<body>" keyed with the secret. Receivers should
+recompute it and reject deliveries older than a few minutes; 'ghaymah webhooks
+verify' does the same locally to debug a receiver.
+
+The application name can be given as an argument or with --name. When omitted, it is
+read from appName in the config file, or from the app linked with 'ghaymah link'.
+remove and test only act on webhooks of that application.
+
+Examples:
+  # Notify the chat-ops bot of deploys (prints the generated signing secret)
+  ghaymah webhooks add my-app --url https://bot.example.com/ghaymah --event deploy.started,deploy.succeeded,deploy.failed
+
+  # List and remove webhooks
+  ghaymah webhooks list my-app
+  ghaymah webhooks remove wh-1 --name my-app
+
+  # Send a sample deploy.failed event
+  ghaymah webhooks test wh-1 --name my-app --event deploy.failed
+
+  # Check the signature of a received payload
+  ghaymah webhooks verify --secret whsec_... --signature "t=1706004000,v1=5f0c..." --payload body.json`,
+    }
+
+    cmd.PersistentFlags().StringVar(&appName, "name", "", "Application name (defaults to the config file or linked app)")
+    cmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "path to configuration file used to detect the application name (default: discovered)")
+    cmd.PersistentFlags().StringVar(&envName, "env-name", "", "Environment whose settings are merged onto the config file (e.g., production)")
+    cmd.RegisterFlagCompletionFunc("name", completeAppNames(api))
+    cmd.RegisterFlagCompletionFunc("env-name", completeEnvironments)
+
+    resolve := func(args []string) (string, error) {
+        return resolveAppName(args, appName, configPath, envName)
+    }
+    completeEvents := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+        return types.WebhookEvents, cobra.ShellCompDirectiveNoFileComp
+    }
+
+    var (
+        hookURL string
+        events  []string
+        secret  string
+        event   string
+    )
+
+    addCmd := &cobra.Command{
+        Use:               "add [APP_NAME]",
+        Short:             "Subscribe a URL to events of an application",
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve(args)
+            if err != nil {
+                return err
+            }
+
+            webhook := &types.Webhook{App: name, URL: hookURL, Events: events, Secret: secret}
+            if len(webhook.Events) == 0 {
+                webhook.Events = types.WebhookEvents
+            }
+            if err := webhook.Validate(); err != nil {
+                return err
+            }
+
+            created, err := api.AddWebhook(webhook)
+            if err != nil {
+                return fmt.Errorf("failed to add webhook: %v", err)
+            }
+
+            fmt.Printf("Webhook %s added: %s receives %s of %s\n", created.ID, created.URL, strings.Join(created.Events, ", "), name)
+            if secret == "" && created.Secret != "" {
+                fmt.Printf("\nSigning secret: %s\n", created.Secret)
+                fmt.Println("Store it now, it won't be shown again.")
+            }
+            fmt.Printf("\nSend a sample event with: ghaymah webhooks test %s --name %s\n", created.ID, name)
+            return nil
+        },
+    }
+    addCmd.Flags().StringVar(&hookURL, "url", "", "URL receiving the events")
+    addCmd.Flags().StringSliceVar(&events, "event", nil, "Event to send (repeatable, default: all events)")
+    addCmd.Flags().StringVar(&secret, "secret", "", "Signing secret (default: generated)")
+    addCmd.MarkFlagRequired("url")
+    addCmd.RegisterFlagCompletionFunc("event", completeEvents)
+
+    listCmd := &cobra.Command{
+        Use:               "list [APP_NAME]",
+        Short:             "List the webhooks of an application",
+        Args:              cobra.MaximumNArgs(1),
+        ValidArgsFunction: completeAppNames(api),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve(args)
+            if err != nil {
+                return err
+            }
+
+            hooks, err := api.ListWebhooks(name)
+            if err != nil {
+                return fmt.Errorf("failed to list webhooks: %v", err)
+            }
+
+            if len(hooks.Webhooks) == 0 {
+                fmt.Println("No webhooks found")
+                return nil
+            }
+
+            w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "ID\tURL\tEVENTS\tCREATED")
+            for _, hook := range hooks.Webhooks {
+                created := "-"
+                if hook.CreatedAt != nil {
+                    created = hook.CreatedAt.Format("2006-01-02 15:04")
+                }
+                fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", hook.ID, hook.URL, strings.Join(hook.Events, ","), created)
+            }
+            return w.Flush()
+        },
+    }
+
+    removeCmd := &cobra.Command{
+        Use:          "remove WEBHOOK_ID",
+        Short:        "Remove a webhook",
+        Args:         cobra.ExactArgs(1),
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve(nil)
+            if err != nil {
+                return err
+            }
+            if err := checkWebhookApp(api, name, args[0]); err != nil {
+                return err
+            }
+
+            if err := api.RemoveWebhook(args[0]); err != nil {
+                return fmt.Errorf("failed to remove webhook: %v", err)
+            }
+
+            fmt.Printf("Webhook %s removed\n", args[0])
+            return nil
+        },
+    }
+
+    testCmd := &cobra.Command{
+        Use:          "test WEBHOOK_ID",
+        Short:        "Send a sample event to a webhook",
+        Args:         cobra.ExactArgs(1),
+        SilenceUsage: true,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            name, err := resolve(nil)
+            if err != nil {
+                return err
+            }
+            if err := checkWebhookApp(api, name, args[0]); err != nil {
+                return err
+            }
+
+            delivery, err := api.TestWebhook(args[0], event)
+            if err != nil {
+                return fmt.Errorf("failed to test webhook: %v", err)
+            }
+
+            if delivery.Error != "" {
+                return fmt.Errorf("delivery of %s failed after %dms: %s", delivery.Event, delivery.DurationMs, delivery.Error)
+            }
+            if delivery.StatusCode < 200 || delivery.StatusCode >= 300 {
+                return fmt.Errorf("delivery of %s failed: the receiver answered HTTP %d in %dms", delivery.Event, delivery.StatusCode, delivery.DurationMs)
+            }
+            fmt.Printf("Sample %s event delivered: HTTP %d in %dms\n", delivery.Event, delivery.StatusCode, delivery.DurationMs)
+            return nil
+        },
+    }
+    testCmd.Flags().StringVar(&event, "event", types.EventDeploySucceeded, "Event to send")
+    testCmd.RegisterFlagCompletionFunc("event", completeEvents)
+
+    cmd.AddCommand(addCmd, listCmd, removeCmd, testCmd, newWebhooksVerifyCommand())
+
+    return cmd
+}
+
+// checkWebhookApp ensures a webhook belongs to the application, so that the
+// ID of a webhook of another application isn't acted on by mistake
+func checkWebhookApp(api *api.GhaymahAPI, appName, id string) error {
+    hooks, err := api.ListWebhooks(appName)
+    if err != nil {
+        return fmt.Errorf("failed to list webhooks: %v", err)
+    }
+    for _, hook := range hooks.Webhooks {
+        if hook.ID == id {
+            return nil
+        }
+    }
+    return fmt.Errorf("webhook %s not found for %s. List its webhooks with: ghaymah webhooks list %s", id, appName, appName)
+}
+
+// newWebhooksVerifyCommand creates the webhooks verify subcommand
+func newWebhooksVerifyCommand() *cobra.Command {
+    var (
+        secret    string
+        header    string
+        payload   string
+        tolerance time.Duration
+    )
+
+    cmd := &cobra.Command{
+        Use:   "verify",
+        Short: "Check the signature of a webhook payload locally",
+        Long: `Check that a payload was signed with the secret of a webhook, as a receiver
+should. The payload is read from --payload, or from stdin. The secret can also
+be set with the ` + webhookSecretEnvVar + ` environment variable.`,
+        Args:         cobra.NoArgs,
+        SilenceUsage: true,
+        Annotations:  map[string]string{offlineAnnotation: "true"},
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if secret == "" {
+                secret = os.Getenv(webhookSecretEnvVar)
+            }
+            if secret == "" {
+                return fmt.Errorf("signing secret is required. Use --secret or set %s", webhookSecretEnvVar)
+            }
+
+            var (
+                body []byte
+                err  error
+            )
+            if payload == "" || payload == "-" {
+                body, err = io.ReadAll(os.Stdin)
+            } else {
+                body, err = os.ReadFile(payload)
+            }
+            if err != nil {
+                return fmt.Errorf("failed to read payload: %v", err)
+            }
+
+            signedAt, err := webhooks.Verify(secret, body, header, tolerance, time.Now())
+            if errors.Is(err, webhooks.ErrSignatureMismatch) {
+                return fmt.Errorf("%v. Check the secret, and that the payload is the raw body as received (no reformatting or trailing newline)", err)
+            }
+            if err != nil {
+                return err
+            }
+
+            fmt.Printf("Signature valid (signed at %s)\n", signedAt.Format("2006-01-02 15:04:05"))
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&secret, "secret", "", "Signing secret of the webhook (default: $"+webhookSecretEnvVar+")")
+    cmd.Flags().StringVar(&header, "signature", "", "Value of the "+webhooks.SignatureHeader+" header")
+    cmd.Flags().StringVar(&payload, "payload", "", "File containing the raw request body (default: stdin)")
+    cmd.Flags().DurationVar(&tolerance, "tolerance", 5*time.Minute, "Reject signatures older or newer than this (0 to disable)")
+    cmd.MarkFlagRequired("signature")
+
+    return cmd
+}
@@ -3,8 +3,13 @@ module ghaymah-cli
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/gorilla/websocket v1.5.3
 	github.com/spf13/cobra v1.8.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -2,6 +2,8 @@ package main
 
 import (
     "fmt"
+    "io"
+    "log/slog"
     "os"
 
     "github.com/spf13/cobra"
@@ -11,32 +13,85 @@ import (
 )
 
 func main() {
-    // Get API configuration from environment variables
-    apiURL, apiToken, err := config.GetAPIConfig()
-    if err != nil {
-        fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-        fmt.Fprintf(os.Stderr, "\nPlease set the following environment variables:\n")
-        fmt.Fprintf(os.Stderr, "  %s: The URL of the Ghaymah Cloud API\n", config.APIURLEnvVar)
-        fmt.Fprintf(os.Stderr, "  %s: Your Ghaymah Cloud API token\n", config.APITokenEnvVar)
-        os.Exit(1)
-    }
+    // Get API configuration from environment variables. Commands that need
+    // the API check it before running, so offline commands work without it.
+    apiURL, apiToken, envErr := config.GetAPIConfig()
 
     // Create API client
     api := api.NewGhaymahAPI(apiURL, apiToken)
 
+    var (
+        verbose   bool
+        debug     bool
+        logFormat string
+    )
+
     // Create root command
     rootCmd := &cobra.Command{
         Use:   "ghaymah",
         Short: "Command line interface for Ghaymah Cloud",
         Long: `Ghaymah CLI is a powerful tool for managing your applications on Ghaymah Cloud.
 Deploy, monitor, and manage your applications with simple commands.`,
+        // main prints the error once
+        SilenceErrors: true,
+        PersistentPreRunE: func(c *cobra.Command, args []string) error {
+            if envErr != nil && cmd.RequiresAPI(c) {
+                c.SilenceUsage = true
+                return fmt.Errorf("%v\n\nPlease set the following environment variables:\n  %s: The URL of the Ghaymah Cloud API\n  %s: Your Ghaymah Cloud API token",
+                    envErr, config.APIURLEnvVar, config.APITokenEnvVar)
+            }
+
+            if !verbose && !debug {
+                return nil
+            }
+
+            level := slog.LevelInfo
+            if debug {
+                level = slog.LevelDebug
+            }
+
+            logger, err := newLogger(os.Stderr, logFormat, level)
+            if err != nil {
+                return err
+            }
+            api.SetLogger(logger)
+            return nil
+        },
     }
 
+    rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log each API request with status, latency and request ID")
+    rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Like --verbose, also logging redacted request and response headers and bodies")
+    rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Format of verbose and debug logs (text or json)")
+
     // Add commands
     rootCmd.AddCommand(
         cmd.NewDeployCommand(api),
+        cmd.NewRolloutCommand(api),
+        cmd.NewLockCommand(api),
+        cmd.NewUnlockCommand(api),
+        cmd.NewRestartCommand(api),
+        cmd.NewStopCommand(api),
+        cmd.NewStartCommand(api),
+        cmd.NewRunCommand(api),
+        cmd.NewJobsCommand(api),
+        cmd.NewVolumesCommand(api),
+        cmd.NewAddonsCommand(api),
+        cmd.NewRegionsCommand(api),
         cmd.NewStatusCommand(api),
         cmd.NewLogsCommand(api),
+        cmd.NewEventsCommand(api),
+        cmd.NewMetricsCommand(api),
+        cmd.NewExporterCommand(api),
+        cmd.NewAlertsCommand(api),
+        cmd.NewNotificationsCommand(api),
+        cmd.NewWebhooksCommand(api),
+        cmd.NewPortForwardCommand(api),
+        cmd.NewDomainsCommand(api),
+        cmd.NewLinkCommand(api),
+        cmd.NewPreviewCommand(api),
+        cmd.NewInitCommand(),
+        cmd.NewConfigCommand(),
+        cmd.NewCompletionCommand(),
     )
 
     // Execute
@@ -45,3 +100,17 @@ Deploy, monitor, and manage your applications with simple commands.`,
         os.Exit(1)
     }
 }
+
+// newLogger creates the logger used for HTTP tracing
+func newLogger(w io.Writer, format string, level slog.Level) (*slog.Logger, error) {
+    opts := &slog.HandlerOptions{Level: level}
+
+    switch format {
+    case "text":
+        return slog.New(slog.NewTextHandler(w, opts)), nil
+    case "json":
+        return slog.New(slog.NewJSONHandler(w, opts)), nil
+    default:
+        return nil, fmt.Errorf("invalid log format %q (expected text or json)", format)
+    }
+}
@@ -6,6 +6,7 @@ import (
     "net/url"
     "strconv"
     "time"
+    "github.com/gorilla/websocket"
     "ghaymah-cli/pkg/config"
     "ghaymah-cli/pkg/types"
 )
@@ -23,7 +24,7 @@ func NewGhaymahAPI(baseURL, token string) *GhaymahAPI {
 }
 
 // Deploy deploys an application to Ghaymah Cloud
-func (api *GhaymahAPI) Deploy(config *config.Config) (*types.DeployResponse, error) {
+func (api *GhaymahAPI) Deploy(config *config.Config, options *types.DeployOptions) (*types.DeployResponse, error) {
     endpoint := "/apps"
     
     payload := map[string]interface{}{
@@ -38,9 +39,37 @@ func (api *GhaymahAPI) Deploy(config *config.Config) (*types.DeployResponse, err
     if config.Region != "" {
         payload["region"] = config.Region
     }
+    if len(config.Regions) > 0 {
+        payload["regions"] = config.Regions
+    }
     if config.Resources != (types.ResourceConfig{}) {
         payload["resources"] = config.Resources
     }
+    if len(config.Domains) > 0 {
+        payload["domains"] = config.Domains
+    }
+    if len(config.Labels) > 0 {
+        payload["labels"] = config.Labels
+    }
+    if config.HealthCheck != nil {
+        healthCheck := *config.HealthCheck
+        healthCheck.Type = healthCheck.CheckType()
+        payload["healthCheck"] = healthCheck
+    }
+    if config.Strategy != nil {
+        strategy := *config.Strategy
+        strategy.Type = strategy.StrategyType()
+        payload["strategy"] = strategy
+    }
+    if len(config.Jobs) > 0 {
+        payload["jobs"] = config.Jobs
+    }
+    if len(config.Volumes) > 0 {
+        payload["volumes"] = config.Volumes
+    }
+    if options != nil && options.ExpectedVersion != nil {
+        payload["expectedVersion"] = *options.ExpectedVersion
+    }
 
     resp, err := api.client.post(endpoint, payload)
     if err != nil {
@@ -57,8 +86,25 @@ func (api *GhaymahAPI) Deploy(config *config.Config) (*types.DeployResponse, err
 
 // GetStatus gets the status of an application
 func (api *GhaymahAPI) GetStatus(appName string) (*types.StatusResponse, error) {
-    endpoint := fmt.Sprintf("/apps/status?name=%s", url.QueryEscape(appName))
-    
+    return api.getStatus(appName, false)
+}
+
+// GetDetailedStatus gets the status of an application including instances,
+// recent platform events, absolute resource usage and the current release
+func (api *GhaymahAPI) GetDetailedStatus(appName string) (*types.StatusResponse, error) {
+    return api.getStatus(appName, true)
+}
+
+// getStatus fetches the status, optionally asking for the detailed breakdown
+func (api *GhaymahAPI) getStatus(appName string, detailed bool) (*types.StatusResponse, error) {
+    params := url.Values{}
+    params.Add("name", appName)
+    if detailed {
+        params.Add("detailed", "true")
+    }
+
+    endpoint := fmt.Sprintf("/apps/status?%s", params.Encode())
+
     resp, err := api.client.get(endpoint)
     if err != nil {
         return nil, fmt.Errorf("failed to get status: %w", err)
@@ -104,6 +150,892 @@ func (api *GhaymahAPI) GetLogs(appName string, options *types.LogOptions) (*type
     return &logsResp, nil
 }
 
+// GetMetrics gets the metrics history of an application
+func (api *GhaymahAPI) GetMetrics(appName string, options *types.MetricsOptions) (*types.MetricsResponse, error) {
+    params := url.Values{}
+    params.Add("name", appName)
+
+    if options != nil {
+        if !options.Start.IsZero() {
+            params.Add("start", options.Start.Format(time.RFC3339))
+        }
+        if !options.End.IsZero() {
+            params.Add("end", options.End.Format(time.RFC3339))
+        }
+        if options.Step > 0 {
+            params.Add("step", strconv.Itoa(int(options.Step.Seconds())))
+        }
+        for _, metric := range options.Metrics {
+            params.Add("metric", metric)
+        }
+    }
+
+    endpoint := fmt.Sprintf("/apps/metrics?%s", params.Encode())
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get metrics: %w", err)
+    }
+
+    var metricsResp types.MetricsResponse
+    if err := json.Unmarshal(resp, &metricsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &metricsResp, nil
+}
+
+// GetEvents gets the activity events of an application, oldest first
+func (api *GhaymahAPI) GetEvents(appName string, options *types.EventOptions) (*types.EventsResponse, error) {
+    params := url.Values{}
+    params.Add("name", appName)
+
+    if options != nil {
+        if !options.Since.IsZero() {
+            params.Add("since", options.Since.Format(time.RFC3339))
+        }
+        if !options.Until.IsZero() {
+            params.Add("until", options.Until.Format(time.RFC3339))
+        }
+        for _, eventType := range options.Types {
+            params.Add("type", eventType)
+        }
+        if options.Limit > 0 {
+            params.Add("limit", strconv.Itoa(options.Limit))
+        }
+    }
+
+    endpoint := fmt.Sprintf("/apps/events?%s", params.Encode())
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get events: %w", err)
+    }
+
+    var eventsResp types.EventsResponse
+    if err := json.Unmarshal(resp, &eventsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &eventsResp, nil
+}
+
+// ListApps lists the applications of the account
+func (api *GhaymahAPI) ListApps() (*types.AppsResponse, error) {
+    resp, err := api.client.get("/apps")
+    if err != nil {
+        return nil, fmt.Errorf("failed to list apps: %w", err)
+    }
+
+    var appsResp types.AppsResponse
+    if err := json.Unmarshal(resp, &appsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &appsResp, nil
+}
+
+// ListAppsByLabel lists the applications having all the given labels
+func (api *GhaymahAPI) ListAppsByLabel(selector map[string]string) (*types.AppsResponse, error) {
+    params := url.Values{}
+    for key, value := range selector {
+        params.Add("label", key+"="+value)
+    }
+
+    resp, err := api.client.get(fmt.Sprintf("/apps?%s", params.Encode()))
+    if err != nil {
+        return nil, fmt.Errorf("failed to list apps: %w", err)
+    }
+
+    var appsResp types.AppsResponse
+    if err := json.Unmarshal(resp, &appsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &appsResp, nil
+}
+
+// DeleteApp deletes an application and its resources
+func (api *GhaymahAPI) DeleteApp(appName string) error {
+    endpoint := fmt.Sprintf("/apps?name=%s", url.QueryEscape(appName))
+
+    if err := api.client.delete(endpoint); err != nil {
+        return fmt.Errorf("failed to delete app: %w", err)
+    }
+
+    return nil
+}
+
+// ListRegions lists the regions applications can be deployed to
+func (api *GhaymahAPI) ListRegions() (*types.RegionsResponse, error) {
+    resp, err := api.client.get("/regions")
+    if err != nil {
+        return nil, fmt.Errorf("failed to list regions: %w", err)
+    }
+
+    var regionsResp types.RegionsResponse
+    if err := json.Unmarshal(resp, &regionsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &regionsResp, nil
+}
+
+// AddDomain attaches a custom domain to an application
+func (api *GhaymahAPI) AddDomain(appName, domain string) (*types.Domain, error) {
+    payload := map[string]interface{}{
+        "name":   appName,
+        "domain": domain,
+    }
+
+    resp, err := api.client.post("/apps/domains", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to add domain: %w", err)
+    }
+
+    var domainResp types.Domain
+    if err := json.Unmarshal(resp, &domainResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &domainResp, nil
+}
+
+// ListDomains lists the custom domains of an application
+func (api *GhaymahAPI) ListDomains(appName string) (*types.DomainsResponse, error) {
+    endpoint := fmt.Sprintf("/apps/domains?name=%s", url.QueryEscape(appName))
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list domains: %w", err)
+    }
+
+    var domainsResp types.DomainsResponse
+    if err := json.Unmarshal(resp, &domainsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &domainsResp, nil
+}
+
+// RemoveDomain detaches a custom domain from an application
+func (api *GhaymahAPI) RemoveDomain(appName, domain string) error {
+    params := url.Values{}
+    params.Add("name", appName)
+    params.Add("domain", domain)
+
+    if err := api.client.delete(fmt.Sprintf("/apps/domains?%s", params.Encode())); err != nil {
+        return fmt.Errorf("failed to remove domain: %w", err)
+    }
+
+    return nil
+}
+
+// VerifyDomain checks the DNS records of a custom domain and issues its certificate
+func (api *GhaymahAPI) VerifyDomain(appName, domain string) (*types.Domain, error) {
+    payload := map[string]interface{}{
+        "name":   appName,
+        "domain": domain,
+    }
+
+    resp, err := api.client.post("/apps/domains/verify", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to verify domain: %w", err)
+    }
+
+    var domainResp types.Domain
+    if err := json.Unmarshal(resp, &domainResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &domainResp, nil
+}
+
+// GetRollout gets the progress of the latest rollout of an application
+func (api *GhaymahAPI) GetRollout(appName string) (*types.Rollout, error) {
+    endpoint := fmt.Sprintf("/apps/rollout?name=%s", url.QueryEscape(appName))
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get rollout: %w", err)
+    }
+
+    var rollout types.Rollout
+    if err := json.Unmarshal(resp, &rollout); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &rollout, nil
+}
+
+// PromoteRollout moves a rollout to its next step, or completes it when full is set
+func (api *GhaymahAPI) PromoteRollout(appName string, full bool) (*types.Rollout, error) {
+    payload := map[string]interface{}{
+        "name": appName,
+        "full": full,
+    }
+
+    resp, err := api.client.post("/apps/rollout/promote", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to promote rollout: %w", err)
+    }
+
+    var rollout types.Rollout
+    if err := json.Unmarshal(resp, &rollout); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &rollout, nil
+}
+
+// AbortRollout stops a rollout and sends all traffic back to the previous release
+func (api *GhaymahAPI) AbortRollout(appName string) (*types.Rollout, error) {
+    payload := map[string]interface{}{
+        "name": appName,
+    }
+
+    resp, err := api.client.post("/apps/rollout/abort", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to abort rollout: %w", err)
+    }
+
+    var rollout types.Rollout
+    if err := json.Unmarshal(resp, &rollout); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &rollout, nil
+}
+
+// LockApp freezes deployments of an application until it is unlocked
+func (api *GhaymahAPI) LockApp(appName, reason string) (*types.AppLock, error) {
+    payload := map[string]interface{}{
+        "name":   appName,
+        "reason": reason,
+    }
+
+    resp, err := api.client.post("/apps/lock", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to lock app: %w", err)
+    }
+
+    var lock types.AppLock
+    if err := json.Unmarshal(resp, &lock); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &lock, nil
+}
+
+// UnlockApp allows deployments of a locked application again
+func (api *GhaymahAPI) UnlockApp(appName string) error {
+    endpoint := fmt.Sprintf("/apps/lock?name=%s", url.QueryEscape(appName))
+
+    if err := api.client.delete(endpoint); err != nil {
+        return fmt.Errorf("failed to unlock app: %w", err)
+    }
+
+    return nil
+}
+
+// RestartApp restarts the instances of an application. A rolling restart
+// restarts them one at a time, so that the application keeps serving traffic.
+func (api *GhaymahAPI) RestartApp(appName string, rolling bool) (*types.AppSummary, error) {
+    payload := map[string]interface{}{
+        "name":    appName,
+        "rolling": rolling,
+    }
+
+    resp, err := api.client.post("/apps/restart", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to restart app: %w", err)
+    }
+
+    return parseAppSummary(resp)
+}
+
+// StopApp stops all instances of an application, keeping its configuration
+func (api *GhaymahAPI) StopApp(appName string) (*types.AppSummary, error) {
+    resp, err := api.client.post("/apps/stop", map[string]interface{}{"name": appName})
+    if err != nil {
+        return nil, fmt.Errorf("failed to stop app: %w", err)
+    }
+
+    return parseAppSummary(resp)
+}
+
+// StartApp starts a stopped application again
+func (api *GhaymahAPI) StartApp(appName string) (*types.AppSummary, error) {
+    resp, err := api.client.post("/apps/start", map[string]interface{}{"name": appName})
+    if err != nil {
+        return nil, fmt.Errorf("failed to start app: %w", err)
+    }
+
+    return parseAppSummary(resp)
+}
+
+// parseAppSummary parses the new state of an application returned by a lifecycle request
+func parseAppSummary(resp []byte) (*types.AppSummary, error) {
+    var app types.AppSummary
+    if err := json.Unmarshal(resp, &app); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+    return &app, nil
+}
+
+// ListJobs lists the scheduled jobs of an application with their last run
+func (api *GhaymahAPI) ListJobs(appName string) (*types.JobsResponse, error) {
+    endpoint := fmt.Sprintf("/apps/jobs?name=%s", url.QueryEscape(appName))
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list jobs: %w", err)
+    }
+
+    var jobsResp types.JobsResponse
+    if err := json.Unmarshal(resp, &jobsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &jobsResp, nil
+}
+
+// RunJob runs a scheduled job now, outside of its schedule
+func (api *GhaymahAPI) RunJob(appName, job string) (*types.JobRun, error) {
+    payload := map[string]interface{}{
+        "name": appName,
+        "job":  job,
+    }
+
+    resp, err := api.client.post("/apps/jobs/run", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to run job: %w", err)
+    }
+
+    return parseJobRun(resp)
+}
+
+// GetJobHistory lists the latest runs of a job, most recent first. All jobs
+// and tasks of the application are listed when job is empty.
+func (api *GhaymahAPI) GetJobHistory(appName, job string, limit int) (*types.JobRunsResponse, error) {
+    params := url.Values{}
+    params.Add("name", appName)
+    if job != "" {
+        params.Add("job", job)
+    }
+    if limit > 0 {
+        params.Add("limit", strconv.Itoa(limit))
+    }
+
+    endpoint := fmt.Sprintf("/apps/jobs/runs?%s", params.Encode())
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get job history: %w", err)
+    }
+
+    var runsResp types.JobRunsResponse
+    if err := json.Unmarshal(resp, &runsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &runsResp, nil
+}
+
+// GetRunLogs gets the output of a job run or task from a cursor, 0 for the
+// beginning, along with the state of the run
+func (api *GhaymahAPI) GetRunLogs(appName, runID string, cursor int) (*types.RunLogsResponse, error) {
+    params := url.Values{}
+    params.Add("name", appName)
+    params.Add("run", runID)
+    params.Add("cursor", strconv.Itoa(cursor))
+
+    endpoint := fmt.Sprintf("/apps/jobs/logs?%s", params.Encode())
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get run logs: %w", err)
+    }
+
+    var logsResp types.RunLogsResponse
+    if err := json.Unmarshal(resp, &logsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &logsResp, nil
+}
+
+// RunTask launches a one-off task container of an application, e.g. to run
+// database migrations with the image of the current release
+func (api *GhaymahAPI) RunTask(appName string, options *types.TaskOptions) (*types.JobRun, error) {
+    payload := map[string]interface{}{
+        "name":    appName,
+        "command": options.Command,
+    }
+    if options.Image != "" {
+        payload["image"] = options.Image
+    }
+    if options.Resources != nil {
+        payload["resources"] = options.Resources
+    }
+    if len(options.EnvVars) > 0 {
+        payload["env"] = options.EnvVars
+    }
+
+    resp, err := api.client.post("/apps/tasks", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to run task: %w", err)
+    }
+
+    return parseJobRun(resp)
+}
+
+// parseJobRun parses a job run or task returned by the API
+func parseJobRun(resp []byte) (*types.JobRun, error) {
+    var run types.JobRun
+    if err := json.Unmarshal(resp, &run); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+    return &run, nil
+}
+
+// ListVolumes lists the persistent volumes of the account
+func (api *GhaymahAPI) ListVolumes() (*types.VolumesResponse, error) {
+    resp, err := api.client.get("/volumes")
+    if err != nil {
+        return nil, fmt.Errorf("failed to list volumes: %w", err)
+    }
+
+    var volumesResp types.VolumesResponse
+    if err := json.Unmarshal(resp, &volumesResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &volumesResp, nil
+}
+
+// CreateVolume creates an empty persistent volume of the given size, e.g. 10G
+func (api *GhaymahAPI) CreateVolume(name, size, region string) (*types.Volume, error) {
+    payload := map[string]interface{}{
+        "name": name,
+        "size": size,
+    }
+    if region != "" {
+        payload["region"] = region
+    }
+
+    resp, err := api.client.post("/volumes", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create volume: %w", err)
+    }
+
+    return parseVolume(resp)
+}
+
+// DeleteVolume deletes a volume and its data. Volumes mounted by an
+// application can't be deleted.
+func (api *GhaymahAPI) DeleteVolume(name string) error {
+    endpoint := fmt.Sprintf("/volumes?name=%s", url.QueryEscape(name))
+
+    if err := api.client.delete(endpoint); err != nil {
+        return fmt.Errorf("failed to delete volume: %w", err)
+    }
+
+    return nil
+}
+
+// ResizeVolume grows a volume to the given size. Volumes can't shrink.
+func (api *GhaymahAPI) ResizeVolume(name, size string) (*types.Volume, error) {
+    payload := map[string]interface{}{
+        "name": name,
+        "size": size,
+    }
+
+    resp, err := api.client.post("/volumes/resize", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to resize volume: %w", err)
+    }
+
+    return parseVolume(resp)
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume, most recent first
+func (api *GhaymahAPI) ListVolumeSnapshots(volume string) (*types.VolumeSnapshotsResponse, error) {
+    endpoint := fmt.Sprintf("/volumes/snapshots?volume=%s", url.QueryEscape(volume))
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list snapshots: %w", err)
+    }
+
+    var snapshotsResp types.VolumeSnapshotsResponse
+    if err := json.Unmarshal(resp, &snapshotsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &snapshotsResp, nil
+}
+
+// CreateVolumeSnapshot takes a snapshot of a volume
+func (api *GhaymahAPI) CreateVolumeSnapshot(volume, description string) (*types.VolumeSnapshot, error) {
+    payload := map[string]interface{}{
+        "volume": volume,
+    }
+    if description != "" {
+        payload["description"] = description
+    }
+
+    resp, err := api.client.post("/volumes/snapshots", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create snapshot: %w", err)
+    }
+
+    var snapshot types.VolumeSnapshot
+    if err := json.Unmarshal(resp, &snapshot); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &snapshot, nil
+}
+
+// RestoreVolumeSnapshot restores a snapshot of a volume, replacing its data,
+// or into a new volume named target when set
+func (api *GhaymahAPI) RestoreVolumeSnapshot(volume, snapshotID, target string) (*types.Volume, error) {
+    payload := map[string]interface{}{
+        "name":     volume,
+        "snapshot": snapshotID,
+    }
+    if target != "" {
+        payload["to"] = target
+    }
+
+    resp, err := api.client.post("/volumes/restore", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to restore snapshot: %w", err)
+    }
+
+    return parseVolume(resp)
+}
+
+// parseVolume parses a volume returned by the API
+func parseVolume(resp []byte) (*types.Volume, error) {
+    var volume types.Volume
+    if err := json.Unmarshal(resp, &volume); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+    return &volume, nil
+}
+
+// ListAddonPlans lists the plans add-ons can be created with, of one type or of all types
+func (api *GhaymahAPI) ListAddonPlans(addonType string) (*types.AddonPlansResponse, error) {
+    endpoint := "/addons/plans"
+    if addonType != "" {
+        endpoint = fmt.Sprintf("/addons/plans?type=%s", url.QueryEscape(addonType))
+    }
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list add-on plans: %w", err)
+    }
+
+    var plansResp types.AddonPlansResponse
+    if err := json.Unmarshal(resp, &plansResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &plansResp, nil
+}
+
+// ListAddons lists the add-ons of an application, or of all applications when appName is empty
+func (api *GhaymahAPI) ListAddons(appName string) (*types.AddonsResponse, error) {
+    endpoint := "/addons"
+    if appName != "" {
+        endpoint = fmt.Sprintf("/addons?app=%s", url.QueryEscape(appName))
+    }
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list add-ons: %w", err)
+    }
+
+    var addonsResp types.AddonsResponse
+    if err := json.Unmarshal(resp, &addonsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &addonsResp, nil
+}
+
+// CreateAddon provisions a managed database attached to an application
+func (api *GhaymahAPI) CreateAddon(options *types.AddonOptions) (*types.Addon, error) {
+    resp, err := api.client.post("/addons", options)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create add-on: %w", err)
+    }
+
+    return parseAddon(resp)
+}
+
+// GetAddon gets an add-on, including its connection URL
+func (api *GhaymahAPI) GetAddon(name string) (*types.Addon, error) {
+    resp, err := api.client.get(fmt.Sprintf("/addons/info?name=%s", url.QueryEscape(name)))
+    if err != nil {
+        return nil, fmt.Errorf("failed to get add-on: %w", err)
+    }
+
+    return parseAddon(resp)
+}
+
+// DestroyAddon deletes an add-on with its data and backups
+func (api *GhaymahAPI) DestroyAddon(name string) error {
+    if err := api.client.delete(fmt.Sprintf("/addons?name=%s", url.QueryEscape(name))); err != nil {
+        return fmt.Errorf("failed to destroy add-on: %w", err)
+    }
+
+    return nil
+}
+
+// RotateAddonCredentials replaces the password of an add-on. The attached
+// application is restarted with the new connection URL.
+func (api *GhaymahAPI) RotateAddonCredentials(name string) (*types.Addon, error) {
+    resp, err := api.client.post("/addons/rotate", map[string]string{"name": name})
+    if err != nil {
+        return nil, fmt.Errorf("failed to rotate credentials: %w", err)
+    }
+
+    return parseAddon(resp)
+}
+
+// ListAddonBackups lists the backups of an add-on, most recent first
+func (api *GhaymahAPI) ListAddonBackups(name string) (*types.AddonBackupsResponse, error) {
+    resp, err := api.client.get(fmt.Sprintf("/addons/backups?name=%s", url.QueryEscape(name)))
+    if err != nil {
+        return nil, fmt.Errorf("failed to list backups: %w", err)
+    }
+
+    var backupsResp types.AddonBackupsResponse
+    if err := json.Unmarshal(resp, &backupsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &backupsResp, nil
+}
+
+// CreateAddonBackup takes a backup of an add-on
+func (api *GhaymahAPI) CreateAddonBackup(name string) (*types.AddonBackup, error) {
+    resp, err := api.client.post("/addons/backups", map[string]string{"name": name})
+    if err != nil {
+        return nil, fmt.Errorf("failed to create backup: %w", err)
+    }
+
+    var backup types.AddonBackup
+    if err := json.Unmarshal(resp, &backup); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &backup, nil
+}
+
+// RestoreAddonBackup replaces the data of an add-on with one of its backups
+func (api *GhaymahAPI) RestoreAddonBackup(name, backupID string) (*types.Addon, error) {
+    payload := map[string]string{
+        "name":   name,
+        "backup": backupID,
+    }
+
+    resp, err := api.client.post("/addons/restore", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to restore backup: %w", err)
+    }
+
+    return parseAddon(resp)
+}
+
+// parseAddon parses an add-on returned by the API
+func parseAddon(resp []byte) (*types.Addon, error) {
+    var addon types.Addon
+    if err := json.Unmarshal(resp, &addon); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+    return &addon, nil
+}
+
+// ListAlerts lists the alert rules of an application, or of all applications when appName is empty
+func (api *GhaymahAPI) ListAlerts(appName string) (*types.AlertsResponse, error) {
+    endpoint := "/alerts"
+    if appName != "" {
+        endpoint = fmt.Sprintf("/alerts?app=%s", url.QueryEscape(appName))
+    }
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list alerts: %w", err)
+    }
+
+    var alertsResp types.AlertsResponse
+    if err := json.Unmarshal(resp, &alertsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &alertsResp, nil
+}
+
+// CreateAlert creates an alert rule
+func (api *GhaymahAPI) CreateAlert(rule *types.AlertRule) (*types.AlertRule, error) {
+    resp, err := api.client.post("/alerts", rule)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create alert: %w", err)
+    }
+
+    var created types.AlertRule
+    if err := json.Unmarshal(resp, &created); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &created, nil
+}
+
+// DeleteAlert deletes an alert rule
+func (api *GhaymahAPI) DeleteAlert(id string) error {
+    if err := api.client.delete(fmt.Sprintf("/alerts?id=%s", url.QueryEscape(id))); err != nil {
+        return fmt.Errorf("failed to delete alert: %w", err)
+    }
+
+    return nil
+}
+
+// TestAlert sends a sample notification of an alert rule to its channels
+func (api *GhaymahAPI) TestAlert(id string) (*types.AlertTestResponse, error) {
+    payload := map[string]interface{}{
+        "id": id,
+    }
+
+    resp, err := api.client.post("/alerts/test", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to test alert: %w", err)
+    }
+
+    var testResp types.AlertTestResponse
+    if err := json.Unmarshal(resp, &testResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &testResp, nil
+}
+
+// ListNotificationChannels lists the notification channels of the account
+func (api *GhaymahAPI) ListNotificationChannels() (*types.NotificationChannelsResponse, error) {
+    resp, err := api.client.get("/notifications")
+    if err != nil {
+        return nil, fmt.Errorf("failed to list notification channels: %w", err)
+    }
+
+    var channelsResp types.NotificationChannelsResponse
+    if err := json.Unmarshal(resp, &channelsResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &channelsResp, nil
+}
+
+// CreateNotificationChannel creates a notification channel
+func (api *GhaymahAPI) CreateNotificationChannel(channel *types.NotificationChannel) (*types.NotificationChannel, error) {
+    resp, err := api.client.post("/notifications", channel)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create notification channel: %w", err)
+    }
+
+    var created types.NotificationChannel
+    if err := json.Unmarshal(resp, &created); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &created, nil
+}
+
+// DeleteNotificationChannel deletes a notification channel
+func (api *GhaymahAPI) DeleteNotificationChannel(name string) error {
+    if err := api.client.delete(fmt.Sprintf("/notifications?name=%s", url.QueryEscape(name))); err != nil {
+        return fmt.Errorf("failed to delete notification channel: %w", err)
+    }
+
+    return nil
+}
+
+// ListWebhooks lists the webhooks of an application
+func (api *GhaymahAPI) ListWebhooks(appName string) (*types.WebhooksResponse, error) {
+    endpoint := fmt.Sprintf("/apps/webhooks?name=%s", url.QueryEscape(appName))
+
+    resp, err := api.client.get(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list webhooks: %w", err)
+    }
+
+    var webhooksResp types.WebhooksResponse
+    if err := json.Unmarshal(resp, &webhooksResp); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &webhooksResp, nil
+}
+
+// AddWebhook subscribes a URL to events of an application. The API generates
+// the signing secret when none is given.
+func (api *GhaymahAPI) AddWebhook(webhook *types.Webhook) (*types.Webhook, error) {
+    resp, err := api.client.post("/apps/webhooks", webhook)
+    if err != nil {
+        return nil, fmt.Errorf("failed to add webhook: %w", err)
+    }
+
+    var created types.Webhook
+    if err := json.Unmarshal(resp, &created); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &created, nil
+}
+
+// RemoveWebhook deletes a webhook
+func (api *GhaymahAPI) RemoveWebhook(id string) error {
+    if err := api.client.delete(fmt.Sprintf("/apps/webhooks?id=%s", url.QueryEscape(id))); err != nil {
+        return fmt.Errorf("failed to remove webhook: %w", err)
+    }
+
+    return nil
+}
+
+// TestWebhook sends a sample event to a webhook and reports the delivery
+func (api *GhaymahAPI) TestWebhook(id, event string) (*types.WebhookDelivery, error) {
+    payload := map[string]interface{}{
+        "id":    id,
+        "event": event,
+    }
+
+    resp, err := api.client.post("/apps/webhooks/test", payload)
+    if err != nil {
+        return nil, fmt.Errorf("failed to test webhook: %w", err)
+    }
+
+    var delivery types.WebhookDelivery
+    if err := json.Unmarshal(resp, &delivery); err != nil {
+        return nil, fmt.Errorf("failed to parse response: %w", err)
+    }
+
+    return &delivery, nil
+}
+
+// DialPortForward opens the multiplexed port-forward tunnel of an application
+func (api *GhaymahAPI) DialPortForward(appName string) (*websocket.Conn, error) {
+    endpoint := fmt.Sprintf("/apps/port-forward?name=%s", url.QueryEscape(appName))
+
+    conn, err := api.client.dialWebSocket(endpoint)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open port-forward tunnel: %w", err)
+    }
+
+    return conn, nil
+}
+
 // Helper methods for making HTTP requests
 // TODO: Implement these methods
 // func (api *GhaymahAPI) get(endpoint string) ([]byte, error)
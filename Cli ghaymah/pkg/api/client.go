@@ -5,8 +5,12 @@ import (
     "encoding/json"
     "fmt"
     "io"
+    "log/slog"
     "net/http"
+    "strings"
     "time"
+
+    "github.com/gorilla/websocket"
 )
 
 // httpClient is the interface for making HTTP requests
@@ -19,6 +23,7 @@ type client struct {
     httpClient httpClient
     baseURL    string
     token      string
+    logger     *slog.Logger
 }
 
 // newClient creates a new HTTP client
@@ -69,6 +74,51 @@ func (c *client) delete(endpoint string) error {
     return err
 }
 
+// dialWebSocket opens an authenticated WebSocket connection to the endpoint
+func (c *client) dialWebSocket(endpoint string) (*websocket.Conn, error) {
+    wsURL := c.baseURL + endpoint
+    if strings.HasPrefix(wsURL, "https://") {
+        wsURL = "wss://" + strings.TrimPrefix(wsURL, "https://")
+    } else if strings.HasPrefix(wsURL, "http://") {
+        wsURL = "ws://" + strings.TrimPrefix(wsURL, "http://")
+    }
+
+    header := http.Header{}
+    header.Set("Authorization", "Bearer "+c.token)
+    header.Set(requestIDHeader, newRequestID())
+
+    if c.logger != nil {
+        c.logger.Debug("websocket dial", slog.String("url", wsURL), slog.Any("headers", redactHeaders(header)))
+    }
+
+    dialer := websocket.Dialer{HandshakeTimeout: time.Second * 30}
+    start := time.Now()
+    conn, resp, err := dialer.Dial(wsURL, header)
+    if c.logger != nil {
+        attrs := []any{
+            slog.String("url", wsURL),
+            slog.Duration("latency", time.Since(start)),
+            slog.String("request_id", header.Get(requestIDHeader)),
+        }
+        if resp != nil {
+            attrs = append(attrs, slog.Int("status", resp.StatusCode))
+        }
+        if err != nil {
+            attrs = append(attrs, slog.String("error", err.Error()))
+        }
+        c.logger.Info("websocket dial", attrs...)
+    }
+    if err != nil {
+        if resp != nil {
+            body, _ := io.ReadAll(resp.Body)
+            resp.Body.Close()
+            return nil, fmt.Errorf("websocket handshake failed with status %d: %s", resp.StatusCode, string(body))
+        }
+        return nil, fmt.Errorf("websocket dial failed: %w", err)
+    }
+    return conn, nil
+}
+
 // newRequest creates a new HTTP request
 func (c *client) newRequest(method, endpoint string, payload interface{}) (*http.Request, error) {
     var body io.Reader
@@ -88,25 +138,32 @@ func (c *client) newRequest(method, endpoint string, payload interface{}) (*http
     req.Header.Set("Authorization", "Bearer "+c.token)
     req.Header.Set("Content-Type", "application/json")
     req.Header.Set("Accept", "application/json")
+    req.Header.Set(requestIDHeader, newRequestID())
 
     return req, nil
 }
 
 // doRequest performs the HTTP request
 func (c *client) doRequest(req *http.Request) ([]byte, error) {
+    c.traceRequest(req)
+    start := time.Now()
+
     resp, err := c.httpClient.Do(req)
     if err != nil {
+        c.traceResponse(req, nil, nil, time.Since(start), err)
         return nil, fmt.Errorf("request failed: %w", err)
     }
     defer resp.Body.Close()
 
     body, err := io.ReadAll(resp.Body)
     if err != nil {
+        c.traceResponse(req, resp, nil, time.Since(start), err)
         return nil, fmt.Errorf("failed to read response body: %w", err)
     }
+    c.traceResponse(req, resp, body, time.Since(start), nil)
 
     if resp.StatusCode >= 400 {
-        return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+        return nil, &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
     }
 
     return body, nil
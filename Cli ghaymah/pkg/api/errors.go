@@ -0,0 +1,32 @@
+package api
+
+import (
+    "errors"
+    "fmt"
+)
+
+// StatusError is returned when the API answers with an error status
+type StatusError struct {
+    StatusCode int
+    Message    string
+}
+
+// Error formats the status and the message returned by the API
+func (e *StatusError) Error() string {
+    return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsStatus reports whether err was caused by the API answering with the given status
+func IsStatus(err error, statusCode int) bool {
+    var statusErr *StatusError
+    return errors.As(err, &statusErr) && statusErr.StatusCode == statusCode
+}
+
+// StatusMessage returns the message of the API error wrapped by err, or err's text
+func StatusMessage(err error) string {
+    var statusErr *StatusError
+    if errors.As(err, &statusErr) {
+        return statusErr.Message
+    }
+    return err.Error()
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "io"
+    "log/slog"
+    "net/http"
+    "net/url"
+    "strings"
+    "time"
+)
+
+// requestIDHeader carries the request ID used to correlate client and server logs
+const requestIDHeader = "X-Request-ID"
+
+// redacted replaces sensitive values in traced headers and bodies
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are substrings of header and JSON keys whose values are masked
+var sensitiveKeys = []string{"authorization", "token", "password", "secret", "apikey", "api_key", "credential", "cookie"}
+
+// SetLogger enables HTTP tracing. Requests are logged at info level; headers
+// and bodies are logged at debug level with sensitive values masked.
+func (api *GhaymahAPI) SetLogger(logger *slog.Logger) {
+    api.client.logger = logger
+}
+
+// newRequestID returns a random ID for a request
+func newRequestID() string {
+    b := make([]byte, 8)
+    if _, err := rand.Read(b); err != nil {
+        return ""
+    }
+    return hex.EncodeToString(b)
+}
+
+// traceRequest logs headers and body of an outgoing request at debug level
+func (c *client) traceRequest(req *http.Request) {
+    if c.logger == nil || !c.logger.Enabled(context.Background(), slog.LevelDebug) {
+        return
+    }
+
+    attrs := []any{
+        slog.String("method", req.Method),
+        slog.String("url", req.URL.String()),
+        slog.String("request_id", req.Header.Get(requestIDHeader)),
+        slog.Any("headers", redactHeaders(req.Header)),
+    }
+    if req.GetBody != nil {
+        if body, err := req.GetBody(); err == nil {
+            data, _ := io.ReadAll(body)
+            attrs = append(attrs, slog.String("body", redactBody(data)))
+        }
+    }
+    c.logger.Debug("http request", attrs...)
+}
+
+// traceResponse logs the outcome of a request, adding headers and body at debug level
+func (c *client) traceResponse(req *http.Request, resp *http.Response, body []byte, latency time.Duration, err error) {
+    if c.logger == nil {
+        return
+    }
+
+    requestID := req.Header.Get(requestIDHeader)
+    if resp != nil && resp.Header.Get(requestIDHeader) != "" {
+        requestID = resp.Header.Get(requestIDHeader)
+    }
+
+    attrs := []any{
+        slog.String("method", req.Method),
+        slog.String("url", req.URL.String()),
+        slog.Duration("latency", latency),
+        slog.String("request_id", requestID),
+    }
+
+    if err != nil {
+        c.logger.Error("http request failed", append(attrs, slog.String("error", err.Error()))...)
+        return
+    }
+
+    attrs = append(attrs, slog.Int("status", resp.StatusCode))
+    if c.logger.Enabled(context.Background(), slog.LevelDebug) {
+        attrs = append(attrs,
+            slog.Any("headers", redactHeaders(resp.Header)),
+            slog.String("body", redactBody(body)),
+        )
+    }
+    c.logger.Info("http response", attrs...)
+}
+
+// isSensitive reports whether a header or field name holds a secret
+func isSensitive(key string) bool {
+    key = strings.ToLower(key)
+    for _, s := range sensitiveKeys {
+        if strings.Contains(key, s) {
+            return true
+        }
+    }
+    return false
+}
+
+// redactHeaders flattens headers, masking sensitive values
+func redactHeaders(h http.Header) map[string]string {
+    out := make(map[string]string, len(h))
+    for key, values := range h {
+        if isSensitive(key) {
+            out[key] = redacted
+            continue
+        }
+        out[key] = strings.Join(values, ", ")
+    }
+    return out
+}
+
+// redactBody masks sensitive fields of a JSON body; other bodies are returned as is
+func redactBody(data []byte) string {
+    if len(data) == 0 {
+        return ""
+    }
+
+    var v interface{}
+    if err := json.Unmarshal(data, &v); err != nil {
+        return string(data)
+    }
+
+    masked, err := json.Marshal(redactValue(v))
+    if err != nil {
+        return string(data)
+    }
+    return string(masked)
+}
+
+// redactValue walks decoded JSON masking values stored under sensitive keys,
+// and the passwords of URLs such as database connection strings
+func redactValue(v interface{}) interface{} {
+    switch val := v.(type) {
+    case map[string]interface{}:
+        for key, inner := range val {
+            if isSensitive(key) {
+                val[key] = redacted
+            } else {
+                val[key] = redactValue(inner)
+            }
+        }
+        return val
+    case []interface{}:
+        for i, inner := range val {
+            val[i] = redactValue(inner)
+        }
+        return val
+    case string:
+        return redactURL(val)
+    default:
+        return v
+    }
+}
+
+// redactURL masks the password of a URL, e.g. postgres://app:[REDACTED]@db:5432/app.
+// Other strings are returned as is.
+func redactURL(s string) string {
+    if !strings.Contains(s, "://") {
+        return s
+    }
+    u, err := url.Parse(s)
+    if err != nil || u.User == nil {
+        return s
+    }
+    if _, ok := u.User.Password(); !ok {
+        return s
+    }
+    user := u.User.Username()
+    u.User = nil
+    return strings.Replace(u.String(), "://", "://"+user+":"+redacted+"@", 1)
+}
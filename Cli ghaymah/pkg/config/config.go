@@ -1,46 +1,179 @@
 package config
 
 import (
+    "fmt"
     "os"
-    "gopkg.in/yaml.v3"
+    "path"
+    "sort"
     "ghaymah-cli/pkg/types"
 )
 
 // Config represents the main configuration for the application
 type Config struct {
-    AppName        string                `yaml:"appName"`
-    Image          string                `yaml:"image,omitempty"`
-    DockerfilePath string                `yaml:"dockerfilePath,omitempty"`
-    EnvVars        map[string]string     `yaml:"envVars,omitempty"`
-    Region         string                `yaml:"region,omitempty"`
-    Resources      types.ResourceConfig  `yaml:"resources,omitempty"`
+    Version        int                        `yaml:"version,omitempty"`
+    AppName        string                     `yaml:"appName"`
+    Image          string                     `yaml:"image,omitempty"`
+    DockerfilePath string                     `yaml:"dockerfilePath,omitempty"`
+    EnvVars        map[string]string          `yaml:"envVars,omitempty"`
+    Region         string                     `yaml:"region,omitempty"`
+    Regions        []string                   `yaml:"regions,omitempty"`
+    Resources      types.ResourceConfig       `yaml:"resources,omitempty"`
+    Domains        []string                   `yaml:"domains,omitempty"`
+    Labels         map[string]string          `yaml:"labels,omitempty"`
+    HealthCheck    *types.HealthCheckConfig   `yaml:"healthCheck,omitempty"`
+    Strategy       *types.StrategyConfig      `yaml:"strategy,omitempty"`
+    Jobs           map[string]types.JobConfig `yaml:"jobs,omitempty"`
+    Volumes        []types.VolumeMount        `yaml:"volumes,omitempty"`
+
+    warnings []string
 }
 
-// LoadFromFile loads configuration from a YAML file
+// LoadFromFile loads configuration from a YAML, JSON or TOML file, chosen by
+// extension. Unknown keys are rejected; duplicate keys are reported by Warnings.
+// The environments section is ignored.
 func (c *Config) LoadFromFile(path string) error {
+    return c.LoadEnvironment(path, "")
+}
+
+// LoadEnvironment loads configuration like LoadFromFile, then deep-merges the
+// settings of the named environment onto it: those under environments: in the
+// file, then those of the overlay file next to it (e.g. ghaymah.production.yaml).
+func (c *Config) LoadEnvironment(path, env string) error {
     data, err := os.ReadFile(path)
     if err != nil {
         return err
     }
-    return yaml.Unmarshal(data, c)
+
+    warnings, err := decodeStrict(data, path, env, c)
+    c.warnings = warnings
+    return err
 }
 
-// Validate ensures all required fields are properly set
-func (c *Config) Validate() bool {
+// Warnings returns problems found while loading that didn't prevent it,
+// prefixed with the file they were found in
+func (c *Config) Warnings() []string {
+    return c.warnings
+}
+
+// Validate ensures all required fields are properly set, returning the first
+// problem found
+func (c *Config) Validate() error {
     // AppName is always required
     if c.AppName == "" {
-        return false
+        return fmt.Errorf("appName is required")
     }
 
     // Either Image or DockerfilePath must be provided
     if c.Image == "" && c.DockerfilePath == "" {
-        return false
+        return fmt.Errorf("image or dockerfilePath is required")
+    }
+
+    // If a health check is specified, validate it
+    if c.HealthCheck != nil {
+        if err := c.HealthCheck.Validate(); err != nil {
+            return err
+        }
+    }
+
+    // If a deployment strategy is specified, validate it
+    if c.Strategy != nil {
+        if err := c.Strategy.Validate(); err != nil {
+            return err
+        }
+    }
+
+    // If jobs are specified, validate them
+    if err := c.ValidateJobs(); err != nil {
+        return err
+    }
+
+    // If volumes are mounted, validate them
+    if err := c.ValidateVolumes(); err != nil {
+        return err
     }
 
-    // If resources are specified, validate them
-    if c.Resources != (types.ResourceConfig{}) {
-        return true // Removed the call to Validate() as it's not defined for types.ResourceConfig
+    // If several regions are given, validate them
+    return c.ValidateRegions()
+}
+
+// DeployRegions returns the regions the application is deployed to, none
+// meaning the account's default region
+func (c *Config) DeployRegions() []string {
+    if len(c.Regions) > 0 {
+        return c.Regions
+    }
+    if c.Region != "" {
+        return []string{c.Region}
+    }
+    return nil
+}
+
+// ValidateRegions ensures region and regions aren't both set, that each
+// region is listed once, and that volumes, which are in a single region, are
+// only mounted in one region
+func (c *Config) ValidateRegions() error {
+    if c.Region != "" && len(c.Regions) > 0 {
+        return fmt.Errorf("region and regions can't both be set, list %s under regions", c.Region)
+    }
+    if len(c.Regions) > 1 && len(c.Volumes) > 0 {
+        return fmt.Errorf("volumes can't be mounted by an application deployed to several regions, a volume is in a single region")
+    }
+    seen := map[string]bool{}
+    for i, region := range c.Regions {
+        switch {
+        case region == "":
+            return fmt.Errorf("regions[%d]: region name is required", i)
+        case seen[region]:
+            return fmt.Errorf("regions[%d]: %s is listed twice", i, region)
+        }
+        seen[region] = true
     }
+    return nil
+}
+
+// JobNames returns the names of the scheduled jobs, sorted
+func (c *Config) JobNames() []string {
+    names := make([]string, 0, len(c.Jobs))
+    for name := range c.Jobs {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
 
-    return true
+// ValidateJobs ensures the scheduled jobs are named and defined correctly
+func (c *Config) ValidateJobs() error {
+    for _, name := range c.JobNames() {
+        if !resourceNameRe.MatchString(name) {
+            return fmt.Errorf("jobs.%s: invalid name (use lowercase letters, digits and dashes)", name)
+        }
+        job := c.Jobs[name]
+        if err := job.Validate(); err != nil {
+            return fmt.Errorf("jobs.%s: %v", name, err)
+        }
+    }
+    return nil
+}
+
+// ValidateVolumes ensures each volume is mounted once, at an absolute path
+func (c *Config) ValidateVolumes() error {
+    names := map[string]bool{}
+    paths := map[string]bool{}
+    for i, volume := range c.Volumes {
+        switch {
+        case volume.Name == "":
+            return fmt.Errorf("volumes[%d]: name is required", i)
+        case !resourceNameRe.MatchString(volume.Name):
+            return fmt.Errorf("volumes[%d]: invalid name %q (use lowercase letters, digits and dashes)", i, volume.Name)
+        case names[volume.Name]:
+            return fmt.Errorf("volumes[%d]: volume %s is mounted twice", i, volume.Name)
+        case !path.IsAbs(volume.MountPath):
+            return fmt.Errorf("volumes[%d]: mountPath must be an absolute path, got %q", i, volume.MountPath)
+        case paths[path.Clean(volume.MountPath)]:
+            return fmt.Errorf("volumes[%d]: another volume is mounted at %s", i, volume.MountPath)
+        }
+        names[volume.Name] = true
+        paths[path.Clean(volume.MountPath)] = true
+    }
+    return nil
 }
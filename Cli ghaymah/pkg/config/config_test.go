@@ -0,0 +1,104 @@
+package config
+
+import (
+    "strings"
+    "testing"
+    "ghaymah-cli/pkg/types"
+)
+
+func TestValidateVolumes(t *testing.T) {
+    tests := []struct {
+        name    string
+        volumes []types.VolumeMount
+        wantErr string
+    }{
+        {name: "none"},
+        {name: "valid", volumes: []types.VolumeMount{{Name: "data", MountPath: "/data"}, {Name: "uploads-2", MountPath: "/var/uploads", ReadOnly: true}}},
+        {name: "missing name", volumes: []types.VolumeMount{{MountPath: "/data"}}, wantErr: "volumes[0]: name is required"},
+        {name: "uppercase name", volumes: []types.VolumeMount{{Name: "Data", MountPath: "/data"}}, wantErr: `invalid name "Data"`},
+        {name: "trailing dash", volumes: []types.VolumeMount{{Name: "data-", MountPath: "/data"}}, wantErr: `invalid name "data-"`},
+        {name: "mounted twice", volumes: []types.VolumeMount{{Name: "data", MountPath: "/a"}, {Name: "data", MountPath: "/b"}}, wantErr: "volumes[1]: volume data is mounted twice"},
+        {name: "relative path", volumes: []types.VolumeMount{{Name: "data", MountPath: "data"}}, wantErr: "must be an absolute path"},
+        {name: "same path", volumes: []types.VolumeMount{{Name: "a", MountPath: "/data"}, {Name: "b", MountPath: "/data/"}}, wantErr: "another volume is mounted at /data/"},
+    }
+    for _, tt := range tests {
+        err := (&Config{Volumes: tt.volumes}).ValidateVolumes()
+        switch {
+        case tt.wantErr == "" && err != nil:
+            t.Errorf("%s: unexpected error %v", tt.name, err)
+        case tt.wantErr != "" && err == nil:
+            t.Errorf("%s: expected an error containing %q", tt.name, tt.wantErr)
+        case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+            t.Errorf("%s: error %q doesn't contain %q", tt.name, err, tt.wantErr)
+        }
+    }
+}
+
+func TestResourceNamesInSchema(t *testing.T) {
+    // The schema applies the same naming rule to jobs and volumes as deploy
+    data := []byte(`appName: shop
+image: shop:1
+jobs:
+  Nightly:
+    schedule: "@daily"
+    command: [report]
+volumes:
+  - name: Data
+    mountPath: /data
+`)
+    problems := ValidateData(data, FormatYAML)
+    if len(problems) != 2 {
+        t.Fatalf("got %d problems, want 2: %v", len(problems), problems)
+    }
+    if p := problems[0]; p.Line != 4 || p.Path != "jobs.Nightly" {
+        t.Errorf("first problem = %v, want the job name on line 4", p)
+    }
+    if p := problems[1]; p.Line != 8 || p.Path != "volumes[0].name" {
+        t.Errorf("second problem = %v, want the volume name on line 8", p)
+    }
+}
+
+func TestValidateRegions(t *testing.T) {
+    volumes := []types.VolumeMount{{Name: "data", MountPath: "/data"}}
+    tests := []struct {
+        name    string
+        config  Config
+        wantErr string
+    }{
+        {name: "one region", config: Config{Region: "eu-west-1", Volumes: volumes}},
+        {name: "several regions", config: Config{Regions: []string{"eu-west-1", "us-east-1"}}},
+        {name: "one region listed", config: Config{Regions: []string{"eu-west-1"}, Volumes: volumes}},
+        {name: "both keys", config: Config{Region: "eu-west-1", Regions: []string{"us-east-1"}}, wantErr: "region and regions can't both be set"},
+        {name: "listed twice", config: Config{Regions: []string{"eu-west-1", "eu-west-1"}}, wantErr: "regions[1]: eu-west-1 is listed twice"},
+        {name: "empty name", config: Config{Regions: []string{""}}, wantErr: "regions[0]: region name is required"},
+        {name: "volumes in several regions", config: Config{Regions: []string{"eu-west-1", "us-east-1"}, Volumes: volumes}, wantErr: "volumes can't be mounted by an application deployed to several regions"},
+    }
+    for _, tt := range tests {
+        err := tt.config.ValidateRegions()
+        switch {
+        case tt.wantErr == "" && err != nil:
+            t.Errorf("%s: unexpected error %v", tt.name, err)
+        case tt.wantErr != "" && err == nil:
+            t.Errorf("%s: expected an error containing %q", tt.name, tt.wantErr)
+        case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+            t.Errorf("%s: error %q doesn't contain %q", tt.name, err, tt.wantErr)
+        }
+    }
+}
+
+func TestRegionsInSchema(t *testing.T) {
+    tests := []struct {
+        content string
+        want    string
+    }{
+        {content: "appName: shop\nimage: shop:1\nregions: []\n", want: "3:10: regions: must have at least 1 item(s)"},
+        {content: "appName: shop\nimage: shop:1\nregions: [eu-west-1, us-east-1, eu-west-1]\n", want: "3:33: regions[2]: eu-west-1 is listed twice"},
+        {content: "appName: shop\nimage: shop:1\nregions: [eu-west-1, us-east-1]\nvolumes:\n  - name: data\n    mountPath: /data\n", want: "volumes can't be mounted by an application deployed to several regions"},
+    }
+    for _, tt := range tests {
+        problems := ValidateData([]byte(tt.content), FormatYAML)
+        if len(problems) != 1 || !strings.Contains(problems[0].Error(), tt.want) {
+            t.Errorf("%q: got problems %v, want %q", tt.content, problems, tt.want)
+        }
+    }
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// FileNames are the configuration files searched for, in order of preference
+var FileNames = []string{"ghaymah.yaml", "ghaymah.yml", "ghaymah.json", "ghaymah.toml"}
+
+// ErrNotFound is returned when no configuration file is found
+var ErrNotFound = errors.New("no configuration file found")
+
+// Discover searches dir and its parents for a configuration file and returns
+// its path. In each directory the names in FileNames are tried in order.
+func Discover(dir string) (string, error) {
+    start, err := filepath.Abs(dir)
+    if err != nil {
+        return "", err
+    }
+
+    for dir := start; ; {
+        for _, name := range FileNames {
+            path := filepath.Join(dir, name)
+            if info, err := os.Stat(path); err == nil && !info.IsDir() {
+                return relativePath(path), nil
+            }
+        }
+
+        parent := filepath.Dir(dir)
+        if parent == dir {
+            break
+        }
+        dir = parent
+    }
+
+    return "", fmt.Errorf("%w: looked for %s in %s and its parent directories. Use -c or run 'ghaymah init'",
+        ErrNotFound, strings.Join(FileNames, ", "), start)
+}
+
+// relativePath shortens path relative to the working directory when possible
+func relativePath(path string) string {
+    wd, err := os.Getwd()
+    if err != nil {
+        return path
+    }
+    if rel, err := filepath.Rel(wd, path); err == nil {
+        return rel
+    }
+    return path
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+    "errors"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+// assertSameFile fails unless got, possibly relative to the working
+// directory, is the file want
+func assertSameFile(t *testing.T, got, want string) {
+    t.Helper()
+    abs, err := filepath.Abs(got)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if abs != want {
+        t.Errorf("found %s, want %s", abs, want)
+    }
+}
+
+func TestDiscoverParentDirectories(t *testing.T) {
+    root := t.TempDir()
+    rootFile := writeFile(t, root, "ghaymah.toml", "appName = \"shop\"\n")
+    writeFile(t, root, "services/api/src/main.go", "package main\n")
+
+    path, err := Discover(filepath.Join(root, "services", "api", "src"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    assertSameFile(t, path, rootFile)
+
+    // The nearest directory wins, then the order of FileNames
+    writeFile(t, root, "services/api/ghaymah.json", "{}")
+    nearest := writeFile(t, root, "services/api/ghaymah.yml", "appName: api\n")
+    path, err = Discover(filepath.Join(root, "services", "api", "src"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    assertSameFile(t, path, nearest)
+}
+
+func TestDiscoverNotFound(t *testing.T) {
+    _, err := Discover(t.TempDir())
+    if !errors.Is(err, ErrNotFound) {
+        t.Errorf("got %v, want ErrNotFound", err)
+    }
+}
+
+func TestFormatsLoadTheSameConfig(t *testing.T) {
+    files := map[string]string{
+        "ghaymah.yaml": `appName: shop
+image: shop:1
+envVars:
+  LOG_LEVEL: debug
+domains: [shop.example.com, www.shop.example.com]
+healthCheck:
+  path: /healthz
+  port: 8080
+volumes:
+  - name: data
+    mountPath: /data
+`,
+        "ghaymah.json": `{
+  "appName": "shop",
+  "image": "shop:1",
+  "envVars": {"LOG_LEVEL": "debug"},
+  "domains": ["shop.example.com", "www.shop.example.com"],
+  "healthCheck": {"path": "/healthz", "port": 8080},
+  "volumes": [{"name": "data", "mountPath": "/data"}]
+}
+`,
+        "ghaymah.toml": `appName = "shop"
+image = "shop:1"
+domains = ["shop.example.com", "www.shop.example.com"]
+
+[envVars]
+LOG_LEVEL = "debug"
+
+[healthCheck]
+path = "/healthz"
+port = 8080
+
+[[volumes]]
+name = "data"
+mountPath = "/data"
+`,
+    }
+
+    var want *Config
+    for name, content := range files {
+        cfg := &Config{}
+        if err := cfg.LoadFromFile(writeFile(t, t.TempDir(), name, content)); err != nil {
+            t.Fatalf("%s: %v", name, err)
+        }
+        if problems := ValidateData([]byte(content), FormatOf(name)); len(problems) > 0 {
+            t.Errorf("%s: unexpected problems %v", name, problems)
+        }
+        if want == nil {
+            want = cfg
+        } else if !reflect.DeepEqual(cfg, want) {
+            t.Errorf("%s loaded as %+v, want %+v", name, cfg, want)
+        }
+    }
+    if want.HealthCheck == nil || want.HealthCheck.Port != 8080 || len(want.Volumes) != 1 {
+        t.Errorf("unexpected config %+v", want)
+    }
+}
+
+func TestFormatsReportTheSameProblems(t *testing.T) {
+    yamlProblems := ValidateData([]byte("appName: shop\nimage: shop:1\nhealthCheck:\n  prot: 8080\n"), FormatYAML)
+    jsonProblems := ValidateData([]byte(`{"appName": "shop", "image": "shop:1", "healthCheck": {"prot": 8080}}`), FormatJSON)
+    tomlProblems := ValidateData([]byte("appName = \"shop\"\nimage = \"shop:1\"\n[healthCheck]\nprot = 8080\n"), FormatTOML)
+
+    for name, problems := range map[string][]ValidationError{"yaml": yamlProblems, "json": jsonProblems, "toml": tomlProblems} {
+        if len(problems) != 1 || problems[0].Path != "healthCheck.prot" || problems[0].Message != "unknown field (did you mean port?)" {
+            t.Errorf("%s: got %v, want the unknown field healthCheck.prot", name, problems)
+        }
+    }
+    // Only the TOML document has no positions
+    if yamlProblems[0].Line != 4 || jsonProblems[0].Line != 1 || tomlProblems[0].Line != 0 {
+        t.Errorf("lines %d, %d and %d, want 4, 1 and none", yamlProblems[0].Line, jsonProblems[0].Line, tomlProblems[0].Line)
+    }
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "reflect"
+    "regexp"
+    "sort"
+    "strings"
+    "gopkg.in/yaml.v3"
+)
+
+// EnvironmentsKey is the configuration key holding per-environment overlays
+const EnvironmentsKey = "environments"
+
+// resourceNameRe matches valid names of environments, jobs and volumes
+var resourceNameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// overlaySchema returns the schema of an environment overlay: any field of the
+// base configuration except version, none of them required
+func overlaySchema() map[string]interface{} {
+    schema := schemaFor(reflect.TypeOf(Config{}), "")
+    properties := schema["properties"].(map[string]interface{})
+    delete(properties, "version")
+    schema["description"] = "Settings merged onto the base configuration for this environment"
+    return schema
+}
+
+// OverlayFile returns the path of the overlay file of an environment for a
+// base configuration file, e.g. ghaymah.production.yaml for ghaymah.yaml.
+// The first existing file among the supported extensions is returned, or ""
+// if there is none.
+func OverlayFile(path, env string) string {
+    ext := filepath.Ext(path)
+    stem := strings.TrimSuffix(path, ext)
+    for _, name := range FileNames {
+        candidate := stem + "." + env + filepath.Ext(name)
+        if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+            return candidate
+        }
+    }
+    return ""
+}
+
+// EnvironmentNames lists the environments defined for a configuration file,
+// both under environments: and as overlay files next to it
+func EnvironmentNames(path string) ([]string, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    root, err := parseDocument(data, FormatOf(path))
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", path, err)
+    }
+
+    seen := map[string]bool{}
+    if root != nil {
+        if envs := mappingValue(root, EnvironmentsKey); envs != nil && envs.Kind == yaml.MappingNode {
+            for i := 0; i+1 < len(envs.Content); i += 2 {
+                seen[envs.Content[i].Value] = true
+            }
+        }
+    }
+
+    ext := filepath.Ext(path)
+    prefix := filepath.Base(strings.TrimSuffix(path, ext)) + "."
+    entries, err := os.ReadDir(filepath.Dir(path))
+    if err != nil {
+        return nil, err
+    }
+    for _, entry := range entries {
+        stem := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+        if entry.IsDir() || !strings.HasPrefix(stem, prefix) || !isConfigExt(filepath.Ext(entry.Name())) {
+            continue
+        }
+        if env := strings.TrimPrefix(stem, prefix); resourceNameRe.MatchString(env) {
+            seen[env] = true
+        }
+    }
+
+    names := make([]string, 0, len(seen))
+    for name := range seen {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names, nil
+}
+
+// isConfigExt reports whether ext is the extension of a supported format
+func isConfigExt(ext string) bool {
+    for _, name := range FileNames {
+        if filepath.Ext(name) == ext {
+            return true
+        }
+    }
+    return false
+}
+
+// removeEnvironments takes the environments section out of a document and
+// returns the overlays by name
+func removeEnvironments(root *yaml.Node) (map[string]*yaml.Node, error) {
+    overlays := map[string]*yaml.Node{}
+    for i := 0; i+1 < len(root.Content); i += 2 {
+        if root.Content[i].Value != EnvironmentsKey {
+            continue
+        }
+        envs := root.Content[i+1]
+        root.Content = append(root.Content[:i:i], root.Content[i+2:]...)
+
+        if envs.Kind == yaml.ScalarNode && envs.Tag == "!!null" {
+            return overlays, nil
+        }
+        if envs.Kind != yaml.MappingNode {
+            return nil, errors.New(location(envs.Line, "environments must be a mapping of environment names to settings"))
+        }
+        for j := 0; j+1 < len(envs.Content); j += 2 {
+            name, overlay := envs.Content[j], envs.Content[j+1]
+            if !resourceNameRe.MatchString(name.Value) {
+                return nil, errors.New(location(name.Line, fmt.Sprintf("invalid environment name %q (use lowercase letters, digits and dashes)", name.Value)))
+            }
+            if overlay.Kind == yaml.ScalarNode && overlay.Tag == "!!null" {
+                overlay = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+            }
+            if overlay.Kind != yaml.MappingNode {
+                return nil, errors.New(location(overlay.Line, fmt.Sprintf("environment %s must be a mapping of settings", name.Value)))
+            }
+            overlays[name.Value] = overlay
+        }
+        return overlays, nil
+    }
+    return overlays, nil
+}
+
+// applyEnvironment merges the overlays of env onto root: first the one under
+// environments:, then the overlay file next to path. Returns the warnings
+// found in the overlay file.
+func applyEnvironment(root *yaml.Node, overlays map[string]*yaml.Node, path, env string) ([]string, error) {
+    if !resourceNameRe.MatchString(env) {
+        return nil, fmt.Errorf("invalid environment name %q (use lowercase letters, digits and dashes)", env)
+    }
+
+    overlay, inline := overlays[env]
+    if inline {
+        mergeNodes(root, overlay)
+    }
+
+    file := OverlayFile(path, env)
+    if file == "" {
+        if !inline {
+            return nil, unknownEnvironmentError(path, env)
+        }
+        return nil, nil
+    }
+
+    data, err := os.ReadFile(file)
+    if err != nil {
+        return nil, err
+    }
+    fileOverlay, err := parseDocument(data, FormatOf(file))
+    if err != nil {
+        return nil, fmt.Errorf("%s: %w", file, err)
+    }
+    if fileOverlay == nil {
+        return nil, nil
+    }
+
+    var warnings []string
+    for _, dup := range removeDuplicateKeys(fileOverlay) {
+        warnings = append(warnings, fmt.Sprintf("%s: %s", file, location(dup.Line, dup.Message)))
+    }
+
+    var unknown []string
+    checkUnknownFields(fileOverlay, overlaySchema(), "", &unknown)
+    if len(unknown) > 0 {
+        return warnings, fmt.Errorf("%s: %s", file, strings.Join(unknown, "\n"))
+    }
+
+    mergeNodes(root, fileOverlay)
+    return warnings, nil
+}
+
+// unknownEnvironmentError explains how to define a missing environment
+func unknownEnvironmentError(path, env string) error {
+    ext := filepath.Ext(path)
+    overlay := strings.TrimSuffix(path, ext) + "." + env + ext
+    message := fmt.Sprintf("environment %q is not defined. Add it under environments: in %s or create %s", env, path, overlay)
+    if names, err := EnvironmentNames(path); err == nil && len(names) > 0 {
+        message += fmt.Sprintf(" (defined: %s)", strings.Join(names, ", "))
+    }
+    return errors.New(message)
+}
+
+// mergeNodes deep-merges overlay onto base and returns the result. Mappings
+// are merged key by key; any other value, including lists, replaces the base
+// value. A null value clears the base value.
+func mergeNodes(base, overlay *yaml.Node) *yaml.Node {
+    if base == nil || base.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+        return overlay
+    }
+
+    for i := 0; i+1 < len(overlay.Content); i += 2 {
+        key, value := overlay.Content[i], overlay.Content[i+1]
+        merged := false
+        for j := 0; j+1 < len(base.Content); j += 2 {
+            if base.Content[j].Value == key.Value {
+                base.Content[j+1] = mergeNodes(base.Content[j+1], value)
+                merged = true
+                break
+            }
+        }
+        if !merged {
+            base.Content = append(base.Content, key, value)
+        }
+    }
+    return base
+}
+
+// cloneNode returns a deep copy of a node tree
+func cloneNode(node *yaml.Node) *yaml.Node {
+    if node == nil {
+        return nil
+    }
+    clone := *node
+    clone.Content = make([]*yaml.Node, len(node.Content))
+    for i, child := range node.Content {
+        clone.Content[i] = cloneNode(child)
+    }
+    return &clone
+}
+
+// mappingValue returns the value of a mapping entry, or nil if absent
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+    for i := 0; i+1 < len(node.Content); i += 2 {
+        if node.Content[i].Value == key {
+            return node.Content[i+1]
+        }
+    }
+    return nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+    "ghaymah-cli/pkg/types"
+    "gopkg.in/yaml.v3"
+)
+
+const environmentsConfig = `appName: shop
+image: shop:1
+envVars:
+  LOG_LEVEL: debug
+  CACHE: redis
+resources:
+  cpu: "1"
+  memory: 512M
+domains: [shop.example.com, www.shop.example.com]
+healthCheck:
+  path: /healthz
+environments:
+  production:
+    envVars:
+      LOG_LEVEL: warn
+      SENTRY: "on"
+    resources:
+      memory: 2G
+    domains: [shop.example.org]
+    healthCheck: null
+  staging: {}
+`
+
+func TestLoadEnvironmentDeepMerge(t *testing.T) {
+    path := writeFile(t, t.TempDir(), "ghaymah.yaml", environmentsConfig)
+
+    cfg := &Config{}
+    if err := cfg.LoadEnvironment(path, "production"); err != nil {
+        t.Fatal(err)
+    }
+    // Mappings are merged key by key, lists are replaced and null clears
+    if want := map[string]string{"LOG_LEVEL": "warn", "CACHE": "redis", "SENTRY": "on"}; !reflect.DeepEqual(cfg.EnvVars, want) {
+        t.Errorf("envVars = %v, want %v", cfg.EnvVars, want)
+    }
+    if want := (types.ResourceConfig{CPU: "1", Memory: "2G"}); cfg.Resources != want {
+        t.Errorf("resources = %+v, want %+v", cfg.Resources, want)
+    }
+    if want := []string{"shop.example.org"}; !reflect.DeepEqual(cfg.Domains, want) {
+        t.Errorf("domains = %v, want %v", cfg.Domains, want)
+    }
+    if cfg.HealthCheck != nil {
+        t.Errorf("healthCheck = %+v, want it cleared", cfg.HealthCheck)
+    }
+
+    // Without an environment, the overlays are ignored
+    base := &Config{}
+    if err := base.LoadFromFile(path); err != nil {
+        t.Fatal(err)
+    }
+    if base.EnvVars["LOG_LEVEL"] != "debug" || len(base.Domains) != 2 || base.HealthCheck == nil {
+        t.Errorf("base config %+v was changed by the overlays", base)
+    }
+}
+
+func TestLoadEnvironmentOverlayFile(t *testing.T) {
+    dir := t.TempDir()
+    path := writeFile(t, dir, "ghaymah.yaml", environmentsConfig)
+    writeFile(t, dir, "ghaymah.production.json", `{"image": "shop:2", "envVars": {"LOG_LEVEL": "error"}}`)
+
+    // The overlay file is merged after the inline overlay
+    cfg := &Config{}
+    if err := cfg.LoadEnvironment(path, "production"); err != nil {
+        t.Fatal(err)
+    }
+    if cfg.Image != "shop:2" || cfg.EnvVars["LOG_LEVEL"] != "error" || cfg.EnvVars["SENTRY"] != "on" {
+        t.Errorf("got image %q and envVars %v, want both overlays merged", cfg.Image, cfg.EnvVars)
+    }
+
+    names, err := EnvironmentNames(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := []string{"production", "staging"}; !reflect.DeepEqual(names, want) {
+        t.Errorf("EnvironmentNames() = %v, want %v", names, want)
+    }
+}
+
+func TestLoadUnknownEnvironment(t *testing.T) {
+    path := writeFile(t, t.TempDir(), "ghaymah.yaml", environmentsConfig)
+    err := (&Config{}).LoadEnvironment(path, "qa")
+    if err == nil || !strings.Contains(err.Error(), `environment "qa" is not defined`) || !strings.Contains(err.Error(), "(defined: production, staging)") {
+        t.Errorf("got %v, want the undefined environment and the defined ones", err)
+    }
+}
+
+func TestMergeNodes(t *testing.T) {
+    parse := func(s string) *yaml.Node {
+        var doc yaml.Node
+        if err := yaml.Unmarshal([]byte(s), &doc); err != nil {
+            t.Fatal(err)
+        }
+        return doc.Content[0]
+    }
+
+    merged := mergeNodes(parse("a: {b: 1, c: [1, 2]}\nd: x\n"), parse("a: {c: [3], e: 2}\nf: y\n"))
+    var got map[string]interface{}
+    if err := merged.Decode(&got); err != nil {
+        t.Fatal(err)
+    }
+    want := map[string]interface{}{
+        "a": map[string]interface{}{"b": 1, "c": []interface{}{3}, "e": 2},
+        "d": "x",
+        "f": "y",
+    }
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("merged = %v, want %v", got, want)
+    }
+}
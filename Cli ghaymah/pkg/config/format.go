@@ -0,0 +1,66 @@
+package config
+
+import (
+    "fmt"
+    "path/filepath"
+    "strings"
+    "github.com/BurntSushi/toml"
+    "gopkg.in/yaml.v3"
+)
+
+// Supported configuration file formats
+const (
+    FormatYAML = "yaml"
+    FormatJSON = "json"
+    FormatTOML = "toml"
+)
+
+// FormatOf returns the format of a configuration file from its extension,
+// defaulting to YAML
+func FormatOf(path string) string {
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".json":
+        return FormatJSON
+    case ".toml":
+        return FormatTOML
+    default:
+        return FormatYAML
+    }
+}
+
+// parseDocument parses a configuration file into a YAML node tree so all
+// formats share the same checks. JSON is parsed as YAML, which keeps line
+// numbers; TOML nodes carry no line numbers. Returns nil for an empty file.
+func parseDocument(data []byte, format string) (*yaml.Node, error) {
+    if format == FormatTOML {
+        var values map[string]interface{}
+        if err := toml.Unmarshal(data, &values); err != nil {
+            return nil, err
+        }
+        if len(values) == 0 {
+            return nil, nil
+        }
+        var root yaml.Node
+        if err := root.Encode(values); err != nil {
+            return nil, err
+        }
+        return &root, nil
+    }
+
+    var doc yaml.Node
+    if err := yaml.Unmarshal(data, &doc); err != nil {
+        return nil, err
+    }
+    if len(doc.Content) == 0 {
+        return nil, nil
+    }
+    return doc.Content[0], nil
+}
+
+// location prefixes a message with its line number when known
+func location(line int, message string) string {
+    if line <= 0 {
+        return message
+    }
+    return fmt.Sprintf("line %d: %s", line, message)
+}
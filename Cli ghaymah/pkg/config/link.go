@@ -0,0 +1,72 @@
+package config
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+const (
+    // LinkDir is the directory holding the link file
+    LinkDir = ".ghaymah"
+    // LinkFile is the file inside LinkDir naming the linked application
+    LinkFile = "app"
+)
+
+// ErrNoLink is returned when no link file is found
+var ErrNoLink = errors.New("no linked application found")
+
+// FindLinkedApp searches dir and its parents for a link file and returns the
+// linked application name and the path of the link file
+func FindLinkedApp(dir string) (string, string, error) {
+    dir, err := filepath.Abs(dir)
+    if err != nil {
+        return "", "", err
+    }
+
+    for {
+        path := filepath.Join(dir, LinkDir, LinkFile)
+        data, err := os.ReadFile(path)
+        if err == nil {
+            name := strings.TrimSpace(string(data))
+            if name == "" {
+                return "", "", fmt.Errorf("link file %s is empty", path)
+            }
+            return name, path, nil
+        }
+        if !errors.Is(err, os.ErrNotExist) {
+            return "", "", err
+        }
+
+        parent := filepath.Dir(dir)
+        if parent == dir {
+            return "", "", ErrNoLink
+        }
+        dir = parent
+    }
+}
+
+// WriteLink links dir to an application, returning the path of the link file
+func WriteLink(dir, appName string) (string, error) {
+    linkDir := filepath.Join(dir, LinkDir)
+    if err := os.MkdirAll(linkDir, 0o755); err != nil {
+        return "", err
+    }
+
+    path := filepath.Join(linkDir, LinkFile)
+    if err := os.WriteFile(path, []byte(appName+"\n"), 0o644); err != nil {
+        return "", err
+    }
+    return path, nil
+}
+
+// RemoveLink removes the link file of dir
+func RemoveLink(dir string) error {
+    err := os.Remove(filepath.Join(dir, LinkDir, LinkFile))
+    if errors.Is(err, os.ErrNotExist) {
+        return ErrNoLink
+    }
+    return err
+}
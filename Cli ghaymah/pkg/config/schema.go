@@ -0,0 +1,179 @@
+package config
+
+import (
+    "reflect"
+    "strings"
+)
+
+// SchemaID identifies the JSON Schema of the configuration file
+const SchemaID = "https://ghaymah.cloud/schemas/config.json"
+
+// schemaDescriptions documents configuration fields by their YAML path
+var schemaDescriptions = map[string]string{
+    "":                               "Ghaymah CLI deploy configuration",
+    "version":                        "Configuration format version",
+    "appName":                        "Application name",
+    "image":                          "Docker image to deploy (e.g., username/app:tag)",
+    "dockerfilePath":                 "Path to the Dockerfile when building from source",
+    "envVars":                        "Environment variables for the application",
+    "region":                         "Deployment region (see 'ghaymah regions'); the account's default region when omitted",
+    "regions":                        "Regions the application is deployed to in parallel, instead of region",
+    "regions[]":                      "Region name (see 'ghaymah regions')",
+    "resources":                      "Resource requirements",
+    "resources.cpu":                  "Number of CPU cores",
+    "resources.memory":               "Memory limit (e.g., 512M)",
+    "resources.storage":              "Storage limit (e.g., 1G), lost on redeploy; see volumes for persistent data",
+    "domains":                        "Custom domains attached on deploy",
+    "labels":                         "Labels attached to the application, used to select it in commands",
+    "healthCheck":                    "How the platform checks that an instance is healthy",
+    "healthCheck.type":               "Check type, inferred from path, command or port when omitted",
+    "healthCheck.path":               "HTTP path to request",
+    "healthCheck.port":               "Port to check",
+    "healthCheck.command":            "Command run inside the instance; exit code 0 means healthy",
+    "healthCheck.interval":           "Time between checks (e.g., 10s)",
+    "healthCheck.timeout":            "Time before a check fails (e.g., 2s)",
+    "healthCheck.healthyThreshold":   "Successful checks before an instance is healthy (0 uses the default)",
+    "healthCheck.unhealthyThreshold": "Failed checks before an instance is unhealthy (0 uses the default)",
+    "healthCheck.gracePeriod":        "Time after start before checks count (e.g., 30s)",
+    "strategy":                       "How a new release replaces the running one",
+    "strategy.type":                  "Strategy type, canary when steps are given and rolling otherwise",
+    "strategy.maxSurge":              "Rolling: extra instances started during the update (e.g., 1 or 25%)",
+    "strategy.maxUnavailable":        "Rolling: instances that may be down during the update (e.g., 0 or 25%)",
+    "strategy.autoPromote":           "Blue-green: switch traffic as soon as the new release is healthy",
+    "strategy.steps":                 "Canary: traffic weights applied in order",
+    "strategy.steps[].weight":        "Percentage of traffic sent to the new release",
+    "strategy.steps[].pause":         "Time before the next step (e.g., 5m); wait for 'ghaymah rollout promote' when omitted",
+    "jobs":                           "Commands run on a schedule, by job name",
+    "jobs.*.schedule":                "Cron schedule in UTC (e.g., 0 2 * * * or @daily)",
+    "jobs.*.command":                 "Command to run, as a list of arguments",
+    "jobs.*.image":                   "Docker image to run the command in, instead of the application's",
+    "jobs.*.resources":               "Resource requirements of the job, instead of the application's",
+    "jobs.*.resources.cpu":           "Number of CPU cores",
+    "jobs.*.resources.memory":        "Memory limit (e.g., 512M)",
+    "jobs.*.resources.storage":       "Storage limit (e.g., 1G)",
+    "jobs.*.timeout":                 "Time after which a run is stopped and failed (e.g., 30m)",
+    "volumes":                        "Persistent volumes mounted into the instances; their data survives redeploys",
+    "volumes[].name":                 "Name of a volume created with 'ghaymah volumes create'",
+    "volumes[].mountPath":            "Absolute path the volume is mounted at (e.g., /data)",
+    "volumes[].readOnly":             "Mount the volume read-only",
+}
+
+// schemaOverrides replaces or extends the generated schema of a field
+var schemaOverrides = map[string]map[string]interface{}{
+    "version":                        {"minimum": 1, "maximum": CurrentVersion},
+    "appName":                        {"minLength": 1},
+    "resources.cpu":                  {"type": []string{"string", "number"}},
+    "healthCheck.type":               {"enum": []string{"http", "tcp", "command"}},
+    "healthCheck.port":               {"minimum": 1, "maximum": 65535},
+    "healthCheck.interval":           {"pattern": durationPattern},
+    "healthCheck.timeout":            {"pattern": durationPattern},
+    "healthCheck.gracePeriod":        {"pattern": durationPattern},
+    "healthCheck.healthyThreshold":   {"minimum": 0},
+    "healthCheck.unhealthyThreshold": {"minimum": 0},
+    "strategy.type":                  {"enum": []string{"rolling", "blue-green", "canary"}},
+    "strategy.maxSurge":              {"type": []string{"string", "integer"}, "pattern": "^[0-9]+%?$"},
+    "strategy.maxUnavailable":        {"type": []string{"string", "integer"}, "pattern": "^[0-9]+%?$"},
+    "strategy.steps[].weight":        {"minimum": 1, "maximum": 100},
+    "strategy.steps[].pause":         {"pattern": durationPattern},
+    "jobs":                           {"propertyNames": map[string]interface{}{"pattern": resourceNameRe.String()}},
+    "jobs.*.resources.cpu":           {"type": []string{"string", "number"}},
+    "jobs.*.timeout":                 {"pattern": durationPattern},
+    "regions":                        {"minItems": 1, "uniqueItems": true},
+    "regions[]":                      {"minLength": 1},
+    "volumes[].name":                 {"pattern": resourceNameRe.String()},
+    "volumes[].mountPath":            {"pattern": "^/"},
+}
+
+// durationPattern matches Go durations such as 10s or 1m30s
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// Schema returns the JSON Schema of the configuration file, generated from Config
+func Schema() map[string]interface{} {
+    schema := schemaFor(reflect.TypeOf(Config{}), "")
+    schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+    schema["$id"] = SchemaID
+    schema["title"] = "Ghaymah configuration"
+    schema["properties"].(map[string]interface{})[EnvironmentsKey] = map[string]interface{}{
+        "type":                 "object",
+        "description":          "Settings per environment (e.g., staging, production), merged onto the base configuration when selected with --env-name",
+        "propertyNames":        map[string]interface{}{"pattern": resourceNameRe.String()},
+        "additionalProperties": overlaySchema(),
+    }
+    schema["required"] = []string{"appName"}
+    schema["anyOf"] = []interface{}{
+        map[string]interface{}{"required": []string{"image"}},
+        map[string]interface{}{"required": []string{"dockerfilePath"}},
+    }
+    return schema
+}
+
+// schemaFor builds the schema of a Go type found at path
+func schemaFor(t reflect.Type, path string) map[string]interface{} {
+    for t.Kind() == reflect.Ptr {
+        t = t.Elem()
+    }
+
+    schema := map[string]interface{}{}
+    switch t.Kind() {
+    case reflect.Struct:
+        schema["type"] = "object"
+        schema["additionalProperties"] = false
+        properties := map[string]interface{}{}
+        for i := 0; i < t.NumField(); i++ {
+            field := t.Field(i)
+            name := yamlName(field)
+            if name == "" {
+                continue
+            }
+            properties[name] = schemaFor(field.Type, joinPath(path, name))
+        }
+        schema["properties"] = properties
+    case reflect.Map:
+        schema["type"] = "object"
+        schema["additionalProperties"] = schemaFor(t.Elem(), joinPath(path, "*"))
+    case reflect.Slice, reflect.Array:
+        schema["type"] = "array"
+        schema["items"] = schemaFor(t.Elem(), path+"[]")
+    case reflect.String:
+        schema["type"] = "string"
+    case reflect.Bool:
+        schema["type"] = "boolean"
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        schema["type"] = "integer"
+    case reflect.Float32, reflect.Float64:
+        schema["type"] = "number"
+    }
+
+    if desc, ok := schemaDescriptions[path]; ok {
+        schema["description"] = desc
+    }
+    for key, value := range schemaOverrides[path] {
+        schema[key] = value
+    }
+    return schema
+}
+
+// yamlName returns the YAML key of a struct field, or "" if it is skipped
+func yamlName(field reflect.StructField) string {
+    if !field.IsExported() {
+        return ""
+    }
+    tag := field.Tag.Get("yaml")
+    if tag == "-" {
+        return ""
+    }
+    name := strings.Split(tag, ",")[0]
+    if name == "" {
+        name = strings.ToLower(field.Name)
+    }
+    return name
+}
+
+// joinPath appends a key to a dotted schema path
+func joinPath(path, key string) string {
+    if path == "" {
+        return key
+    }
+    return path + "." + key
+}
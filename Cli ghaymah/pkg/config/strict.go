@@ -0,0 +1,255 @@
+package config
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the configuration format version written and understood by this CLI
+const CurrentVersion = 1
+
+// migrations upgrade a configuration document from the version used as key to
+// the next one. Files without a version field are treated as version 1.
+var migrations = map[int]func(root *yaml.Node) error{}
+
+// decodeStrict decodes the configuration file at path, rejecting unknown keys
+// with a suggestion for the closest valid key. Duplicate keys are reported as
+// warnings and the last value is used. When env is set, the overlays of that
+// environment are merged onto the file. Errors are prefixed with the file
+// they were found in.
+func decodeStrict(data []byte, path, env string, c *Config) ([]string, error) {
+    format := FormatOf(path)
+    fail := func(err error) error {
+        return fmt.Errorf("%s: %w", path, err)
+    }
+
+    root, err := parseDocument(data, format)
+    if err != nil {
+        return nil, fail(err)
+    }
+    if root == nil {
+        if env != "" {
+            return nil, unknownEnvironmentError(path, env)
+        }
+        return nil, nil
+    }
+
+    var warnings []string
+    for _, dup := range removeDuplicateKeys(root) {
+        warnings = append(warnings, fmt.Sprintf("%s: %s", path, location(dup.Line, dup.Message)))
+    }
+    modified := len(warnings) > 0
+
+    version, err := configVersion(root)
+    if err != nil {
+        return warnings, fail(err)
+    }
+    if version > CurrentVersion {
+        return warnings, fail(fmt.Errorf("config version %d is newer than supported version %d, please upgrade the CLI", version, CurrentVersion))
+    }
+    for v := version; v < CurrentVersion; v++ {
+        migrate, ok := migrations[v]
+        if !ok {
+            continue
+        }
+        if err := migrate(root); err != nil {
+            return warnings, fail(fmt.Errorf("failed to migrate config from version %d: %w", v, err))
+        }
+        modified = true
+    }
+
+    var unknown []string
+    checkUnknownFields(root, Schema(), "", &unknown)
+    if len(unknown) > 0 {
+        return warnings, fail(errors.New(strings.Join(unknown, "\n")))
+    }
+
+    if mappingValue(root, EnvironmentsKey) != nil {
+        modified = true
+    }
+    overlays, err := removeEnvironments(root)
+    if err != nil {
+        return warnings, fail(err)
+    }
+    if env != "" {
+        overlayWarnings, err := applyEnvironment(root, overlays, path, env)
+        warnings = append(warnings, overlayWarnings...)
+        if err != nil {
+            return warnings, err
+        }
+        modified = true
+    }
+
+    // Decode the original text when possible so line numbers in errors are right
+    if modified || format == FormatTOML {
+        if err := root.Decode(c); err != nil {
+            return warnings, fail(err)
+        }
+    } else {
+        dec := yaml.NewDecoder(bytes.NewReader(data))
+        dec.KnownFields(true)
+        if err := dec.Decode(c); err != nil {
+            return warnings, fail(err)
+        }
+    }
+    if c.Version == 0 {
+        c.Version = CurrentVersion
+    }
+    return warnings, nil
+}
+
+// configVersion reads the version field of a document, defaulting to 1
+func configVersion(root *yaml.Node) (int, error) {
+    for i := 0; i+1 < len(root.Content); i += 2 {
+        if root.Content[i].Value != "version" {
+            continue
+        }
+        value := root.Content[i+1]
+        version, err := strconv.Atoi(value.Value)
+        if err != nil || version < 1 {
+            return 0, errors.New(location(value.Line, fmt.Sprintf("invalid config version %q", value.Value)))
+        }
+        return version, nil
+    }
+    return 1, nil
+}
+
+// removeDuplicateKeys drops all but the last occurrence of repeated mapping
+// keys, returning a problem for each one removed
+func removeDuplicateKeys(node *yaml.Node) []ValidationError {
+    var dups []ValidationError
+
+    if node.Kind == yaml.MappingNode {
+        last := map[string]int{}
+        for i := 0; i+1 < len(node.Content); i += 2 {
+            last[node.Content[i].Value] = i
+        }
+
+        content := make([]*yaml.Node, 0, len(node.Content))
+        for i := 0; i+1 < len(node.Content); i += 2 {
+            key := node.Content[i]
+            if j := last[key.Value]; j != i {
+                dups = append(dups, ValidationError{
+                    Line:    key.Line,
+                    Column:  key.Column,
+                    Message: fmt.Sprintf("duplicate key %q, using the value from line %d", key.Value, node.Content[j].Line),
+                })
+                continue
+            }
+            content = append(content, key, node.Content[i+1])
+        }
+        node.Content = content
+    }
+
+    for _, child := range node.Content {
+        dups = append(dups, removeDuplicateKeys(child)...)
+    }
+    return dups
+}
+
+// checkUnknownFields reports keys that are not part of the schema
+func checkUnknownFields(node *yaml.Node, schema map[string]interface{}, path string, errs *[]string) {
+    if node.Kind != yaml.MappingNode {
+        if node.Kind == yaml.SequenceNode {
+            if items, ok := schema["items"].(map[string]interface{}); ok {
+                for _, item := range node.Content {
+                    checkUnknownFields(item, items, path, errs)
+                }
+            }
+        }
+        return
+    }
+
+    properties, _ := schema["properties"].(map[string]interface{})
+    for i := 0; i+1 < len(node.Content); i += 2 {
+        key, value := node.Content[i], node.Content[i+1]
+        if prop, ok := properties[key.Value].(map[string]interface{}); ok {
+            checkUnknownFields(value, prop, joinPath(path, key.Value), errs)
+            continue
+        }
+        switch additional := schema["additionalProperties"].(type) {
+        case bool:
+            if !additional {
+                *errs = append(*errs, location(key.Line, unknownFieldMessage(joinPath(path, key.Value), properties)))
+            }
+        case map[string]interface{}:
+            checkUnknownFields(value, additional, joinPath(path, key.Value), errs)
+        }
+    }
+}
+
+// unknownFieldMessage describes an unknown key, suggesting the closest valid one
+func unknownFieldMessage(path string, properties map[string]interface{}) string {
+    name := path[strings.LastIndex(path, ".")+1:]
+    if suggestion := closestKey(name, properties); suggestion != "" {
+        return fmt.Sprintf("unknown field %s (did you mean %s?)", path, suggestion)
+    }
+    return fmt.Sprintf("unknown field %s", path)
+}
+
+// closestKey returns the valid key most similar to name, if any is close enough
+func closestKey(name string, properties map[string]interface{}) string {
+    keys := make([]string, 0, len(properties))
+    for key := range properties {
+        keys = append(keys, key)
+    }
+    return ClosestMatch(name, keys)
+}
+
+// ClosestMatch returns the candidate most similar to name, if any is close
+// enough to be a typo of it
+func ClosestMatch(name string, candidates []string) string {
+    best, bestDistance := "", -1
+    for _, key := range candidates {
+        d := levenshtein(strings.ToLower(name), strings.ToLower(key))
+        if bestDistance == -1 || d < bestDistance || (d == bestDistance && key < best) {
+            best, bestDistance = key, d
+        }
+    }
+
+    limit := len(name) / 3
+    if limit < 2 {
+        limit = 2
+    }
+    if bestDistance == -1 || bestDistance > limit {
+        return ""
+    }
+    return best
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+    prev := make([]int, len(b)+1)
+    curr := make([]int, len(b)+1)
+    for j := range prev {
+        prev[j] = j
+    }
+
+    for i := 1; i <= len(a); i++ {
+        curr[0] = i
+        for j := 1; j <= len(b); j++ {
+            cost := 1
+            if a[i-1] == b[j-1] {
+                cost = 0
+            }
+            curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+        }
+        prev, curr = curr, prev
+    }
+    return prev[len(b)]
+}
+
+// min3 returns the smallest of three ints
+func min3(a, b, c int) int {
+    if b < a {
+        a = b
+    }
+    if c < a {
+        a = c
+    }
+    return a
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// writeFile writes a file under dir and returns its path
+func writeFile(t *testing.T, dir, name, content string) string {
+    t.Helper()
+    path := filepath.Join(dir, name)
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
+
+func TestLoadUnknownFieldSuggestions(t *testing.T) {
+    tests := []struct {
+        content string
+        want    string
+    }{
+        {content: "appName: shop\nimage: shop:1\nhealtCheck:\n  path: /\n", want: "line 3: unknown field healtCheck (did you mean healthCheck?)"},
+        {content: "appName: shop\nimage: shop:1\nresources:\n  cpuu: 1\n", want: "line 4: unknown field resources.cpuu (did you mean cpu?)"},
+        {content: "appName: shop\nimage: shop:1\nreplicas: 3\n", want: "line 3: unknown field replicas"},
+        {content: "appName: shop\nimage: shop:1\nenvironments:\n  production:\n    imag: shop:2\n", want: "unknown field environments.production.imag (did you mean image?)"},
+    }
+    for _, tt := range tests {
+        path := writeFile(t, t.TempDir(), "ghaymah.yaml", tt.content)
+        err := (&Config{}).LoadFromFile(path)
+        if err == nil || !strings.Contains(err.Error(), tt.want) {
+            t.Errorf("loading %q: got error %v, want %q", tt.content, err, tt.want)
+        }
+    }
+}
+
+func TestLoadDuplicateKeysAreWarnings(t *testing.T) {
+    content := "appName: shop\nimage: shop:1\nenvVars:\n  FOO: a\n  FOO: b\nimage: shop:2\n"
+    path := writeFile(t, t.TempDir(), "ghaymah.yaml", content)
+
+    cfg := &Config{}
+    if err := cfg.LoadFromFile(path); err != nil {
+        t.Fatalf("duplicate keys must not fail loading: %v", err)
+    }
+    if cfg.Image != "shop:2" || cfg.EnvVars["FOO"] != "b" {
+        t.Errorf("image %q and FOO %q, want the last values shop:2 and b", cfg.Image, cfg.EnvVars["FOO"])
+    }
+    want := []string{
+        path + `: line 2: duplicate key "image", using the value from line 6`,
+        path + `: line 4: duplicate key "FOO", using the value from line 5`,
+    }
+    if got := cfg.Warnings(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+        t.Errorf("warnings = %q, want %q", got, want)
+    }
+
+    // config validate agrees: the same problems, as warnings only
+    problems := ValidateData([]byte(content), FormatYAML)
+    if len(problems) != 2 || len(Invalid(problems)) != 0 {
+        t.Fatalf("got problems %v, want 2 warnings", problems)
+    }
+    if got := problems[0].Error(); got != `2:1: warning: duplicate key "image", using the value from line 6` {
+        t.Errorf("first problem = %q", got)
+    }
+}
+
+func TestClosestMatch(t *testing.T) {
+    candidates := []string{"appName", "image", "healthCheck", "domains", "regions"}
+    tests := map[string]string{
+        "appname":    "appName",
+        "imgae":      "image",
+        "helthCheck": "healthCheck",
+        "region":     "regions",
+        "replicas":   "",
+        "x":          "",
+    }
+    for name, want := range tests {
+        if got := ClosestMatch(name, candidates); got != want {
+            t.Errorf("ClosestMatch(%q) = %q, want %q", name, got, want)
+        }
+    }
+}
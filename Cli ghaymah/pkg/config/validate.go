@@ -0,0 +1,385 @@
+package config
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "os"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "github.com/BurntSushi/toml"
+    "gopkg.in/yaml.v3"
+)
+
+// ValidationError is a problem found in a configuration file. Warnings, such
+// as duplicate keys, are reported but don't make the file invalid.
+type ValidationError struct {
+    Line    int
+    Column  int
+    Path    string
+    Message string
+    Warning bool
+}
+
+// Error formats the problem as line:column: message, leaving out the
+// position when it isn't known
+func (e ValidationError) Error() string {
+    message := e.Message
+    if e.Warning {
+        message = "warning: " + message
+    }
+    if e.Path != "" {
+        message = e.Path + ": " + message
+    }
+    if e.Line <= 0 {
+        return message
+    }
+    return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, message)
+}
+
+// Invalid returns the problems that aren't warnings
+func Invalid(problems []ValidationError) []ValidationError {
+    var errs []ValidationError
+    for _, p := range problems {
+        if !p.Warning {
+            errs = append(errs, p)
+        }
+    }
+    return errs
+}
+
+// yamlLineRe extracts the line number of yaml syntax errors
+var yamlLineRe = regexp.MustCompile(`line (\d+): `)
+
+// tomlPrefixRe matches the position prefix of toml syntax errors
+var tomlPrefixRe = regexp.MustCompile(`^toml: line \d+( \(last key "[^"]*"\))?: `)
+
+// ValidateFile checks a configuration file against the schema and the rules
+// applied on deploy, without contacting the API. The returned error is only
+// set when the file cannot be read.
+func ValidateFile(path string) ([]ValidationError, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return ValidateData(data, FormatOf(path)), nil
+}
+
+// ValidateData checks configuration file contents in the given format.
+// TOML documents carry no positions once parsed, so problems found in them
+// after parsing are reported without a line and column.
+func ValidateData(data []byte, format string) []ValidationError {
+    root, err := parseDocument(data, format)
+    if err != nil {
+        line := 1
+        var tomlErr toml.ParseError
+        if errors.As(err, &tomlErr) {
+            start := tomlErr.Position.Start
+            if start > len(data) {
+                start = len(data)
+            }
+            column := start - bytes.LastIndexByte(data[:start], '\n')
+            message := tomlPrefixRe.ReplaceAllString(tomlErr.Error(), "")
+            return []ValidationError{{Line: tomlErr.Position.Line, Column: column, Message: message}}
+        }
+        if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+            line, _ = strconv.Atoi(m[1])
+        }
+        message := yamlLineRe.ReplaceAllString(strings.TrimPrefix(err.Error(), "yaml: "), "")
+        return []ValidationError{{Line: line, Column: 1, Message: message}}
+    }
+
+    if root == nil {
+        return []ValidationError{{Line: 1, Column: 1, Message: "file is empty"}}
+    }
+
+    // Duplicate keys are only warned about, as on deploy
+    warnings := removeDuplicateKeys(root)
+    for i := range warnings {
+        warnings[i].Warning = true
+    }
+
+    var errs []ValidationError
+    validateNode(root, Schema(), "", &errs)
+    if len(errs) > 0 {
+        return sortProblems(append(warnings, errs...))
+    }
+
+    // The schema can't express everything checked on deploy, for the base
+    // configuration and each environment merged onto it
+    base := cloneNode(root)
+    overlays, err := removeEnvironments(base)
+    if err != nil {
+        return append(warnings, ValidationError{Line: root.Line, Column: root.Column, Message: err.Error()})
+    }
+    errs = checkConfig(base, root, "")
+
+    names := make([]string, 0, len(overlays))
+    for name := range overlays {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        if len(errs) > 0 {
+            break
+        }
+        merged := mergeNodes(cloneNode(base), cloneNode(overlays[name]))
+        errs = checkConfig(merged, overlays[name], joinPath(EnvironmentsKey, name))
+    }
+    return sortProblems(append(warnings, errs...))
+}
+
+// sortProblems orders problems by their position in the file
+func sortProblems(problems []ValidationError) []ValidationError {
+    sort.SliceStable(problems, func(i, j int) bool {
+        return problems[i].Line < problems[j].Line || (problems[i].Line == problems[j].Line && problems[i].Column < problems[j].Column)
+    })
+    return problems
+}
+
+// checkConfig runs the checks applied on deploy to a configuration document.
+// Problems are reported at the position of node under path.
+func checkConfig(doc, node *yaml.Node, path string) []ValidationError {
+    var errs []ValidationError
+    cfg := &Config{}
+    if err := doc.Decode(cfg); err != nil {
+        return []ValidationError{{Line: node.Line, Column: node.Column, Path: path, Message: err.Error()}}
+    }
+    if cfg.HealthCheck != nil {
+        if err := cfg.HealthCheck.Validate(); err != nil {
+            key := mappingKey(node, "healthCheck")
+            errs = append(errs, ValidationError{Line: key.Line, Column: key.Column, Path: joinPath(path, "healthCheck"), Message: err.Error()})
+        }
+    }
+    if cfg.Strategy != nil {
+        if err := cfg.Strategy.Validate(); err != nil {
+            key := mappingKey(node, "strategy")
+            errs = append(errs, ValidationError{Line: key.Line, Column: key.Column, Path: joinPath(path, "strategy"), Message: err.Error()})
+        }
+    }
+    for _, name := range cfg.JobNames() {
+        job := cfg.Jobs[name]
+        if err := job.Validate(); err != nil {
+            key := mappingKey(node, "jobs")
+            if jobs := mappingValue(node, "jobs"); jobs != nil {
+                key = mappingKey(jobs, name)
+            }
+            errs = append(errs, ValidationError{Line: key.Line, Column: key.Column, Path: joinPath(path, "jobs."+name), Message: err.Error()})
+        }
+    }
+    if err := cfg.ValidateVolumes(); err != nil {
+        key := mappingKey(node, "volumes")
+        errs = append(errs, ValidationError{Line: key.Line, Column: key.Column, Path: path, Message: err.Error()})
+    }
+    if err := cfg.ValidateRegions(); err != nil {
+        key := mappingKey(node, "regions")
+        errs = append(errs, ValidationError{Line: key.Line, Column: key.Column, Path: path, Message: err.Error()})
+    }
+    if len(errs) > 0 {
+        return errs
+    }
+    if err := cfg.Validate(); err != nil {
+        errs = append(errs, ValidationError{Line: node.Line, Column: node.Column, Path: path, Message: err.Error()})
+    }
+    return errs
+}
+
+// validateNode checks a YAML node against a schema, appending problems to errs
+func validateNode(node *yaml.Node, schema map[string]interface{}, path string, errs *[]ValidationError) {
+    if node.Kind == yaml.AliasNode {
+        node = node.Alias
+    }
+
+    fail := func(n *yaml.Node, format string, args ...interface{}) {
+        *errs = append(*errs, ValidationError{Line: n.Line, Column: n.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+    }
+
+    // An empty value is the same as leaving the key out
+    if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+        return
+    }
+
+    types := schemaTypes(schema["type"])
+    if len(types) > 0 && !matchesType(node, types) {
+        fail(node, "expected %s, got %s", strings.Join(types, " or "), nodeType(node))
+        return
+    }
+
+    switch node.Kind {
+    case yaml.MappingNode:
+        validateMapping(node, schema, path, errs)
+    case yaml.SequenceNode:
+        if minItems, ok := schema["minItems"].(int); ok && len(node.Content) < minItems {
+            fail(node, "must have at least %d item(s)", minItems)
+        }
+        if unique, _ := schema["uniqueItems"].(bool); unique {
+            seen := map[string]bool{}
+            for i, item := range node.Content {
+                if item.Kind != yaml.ScalarNode {
+                    continue
+                }
+                if seen[item.Value] {
+                    *errs = append(*errs, ValidationError{Line: item.Line, Column: item.Column, Path: fmt.Sprintf("%s[%d]", path, i), Message: fmt.Sprintf("%s is listed twice", item.Value)})
+                }
+                seen[item.Value] = true
+            }
+        }
+        if items, ok := schema["items"].(map[string]interface{}); ok {
+            for i, item := range node.Content {
+                validateNode(item, items, fmt.Sprintf("%s[%d]", path, i), errs)
+            }
+        }
+    case yaml.ScalarNode:
+        if enum, ok := schema["enum"].([]string); ok && !contains(enum, node.Value) {
+            fail(node, "must be one of %s, got %q", strings.Join(enum, ", "), node.Value)
+        }
+        if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(node.Value) {
+            fail(node, "invalid value %q", node.Value)
+        }
+        if minLength, ok := schema["minLength"].(int); ok && len(node.Value) < minLength {
+            fail(node, "must not be empty")
+        }
+        if n, err := strconv.ParseFloat(node.Value, 64); err == nil && node.Tag != "!!str" {
+            if min, ok := schema["minimum"].(int); ok && n < float64(min) {
+                fail(node, "must be at least %d", min)
+            }
+            if max, ok := schema["maximum"].(int); ok && n > float64(max) {
+                fail(node, "must be at most %d", max)
+            }
+        }
+    }
+}
+
+// validateMapping checks the keys of an object
+func validateMapping(node *yaml.Node, schema map[string]interface{}, path string, errs *[]ValidationError) {
+    properties, _ := schema["properties"].(map[string]interface{})
+    seen := map[string]bool{}
+
+    for i := 0; i+1 < len(node.Content); i += 2 {
+        key, value := node.Content[i], node.Content[i+1]
+        seen[key.Value] = true
+        childPath := joinPath(path, key.Value)
+
+        if names, ok := schema["propertyNames"].(map[string]interface{}); ok {
+            if pattern, ok := names["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(key.Value) {
+                *errs = append(*errs, ValidationError{Line: key.Line, Column: key.Column, Path: childPath, Message: "invalid name (use lowercase letters, digits and dashes)"})
+                continue
+            }
+        }
+
+        if prop, ok := properties[key.Value].(map[string]interface{}); ok {
+            validateNode(value, prop, childPath, errs)
+            continue
+        }
+
+        switch additional := schema["additionalProperties"].(type) {
+        case bool:
+            if !additional {
+                message := "unknown field"
+                if suggestion := closestKey(key.Value, properties); suggestion != "" {
+                    message = fmt.Sprintf("unknown field (did you mean %s?)", suggestion)
+                }
+                *errs = append(*errs, ValidationError{Line: key.Line, Column: key.Column, Path: childPath, Message: message})
+            }
+        case map[string]interface{}:
+            validateNode(value, additional, childPath, errs)
+        }
+    }
+
+    if required, ok := schema["required"].([]string); ok {
+        for _, name := range required {
+            if !seen[name] {
+                *errs = append(*errs, ValidationError{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf("missing required field %s", name)})
+            }
+        }
+    }
+
+    if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+        var alternatives []string
+        satisfied := false
+        for _, alt := range anyOf {
+            required, _ := alt.(map[string]interface{})["required"].([]string)
+            alternatives = append(alternatives, strings.Join(required, " and "))
+            all := true
+            for _, name := range required {
+                all = all && seen[name]
+            }
+            satisfied = satisfied || all
+        }
+        if !satisfied {
+            *errs = append(*errs, ValidationError{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf("one of %s is required", strings.Join(alternatives, " or "))})
+        }
+    }
+}
+
+// schemaTypes normalizes the type keyword to a list
+func schemaTypes(v interface{}) []string {
+    switch t := v.(type) {
+    case string:
+        return []string{t}
+    case []string:
+        return t
+    }
+    return nil
+}
+
+// matchesType reports whether a node has one of the JSON Schema types. Any
+// scalar is accepted for strings, because the CLI reads numbers and booleans
+// into string fields as written.
+func matchesType(node *yaml.Node, types []string) bool {
+    actual := nodeType(node)
+    for _, t := range types {
+        switch {
+        case t == actual:
+            return true
+        case t == "number" && actual == "integer":
+            return true
+        case t == "string" && node.Kind == yaml.ScalarNode:
+            return true
+        }
+    }
+    return false
+}
+
+// nodeType returns the JSON Schema type of a node
+func nodeType(node *yaml.Node) string {
+    switch node.Kind {
+    case yaml.MappingNode:
+        return "object"
+    case yaml.SequenceNode:
+        return "array"
+    }
+    switch node.Tag {
+    case "!!int":
+        return "integer"
+    case "!!float":
+        return "number"
+    case "!!bool":
+        return "boolean"
+    case "!!null":
+        return "null"
+    }
+    return "string"
+}
+
+// mappingKey returns the key node of a mapping entry, or the mapping itself if absent
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+    for i := 0; i+1 < len(node.Content); i += 2 {
+        if node.Content[i].Value == key {
+            return node.Content[i]
+        }
+    }
+    return node
+}
+
+// contains reports whether list has s
+func contains(list []string, s string) bool {
+    for _, item := range list {
+        if item == s {
+            return true
+        }
+    }
+    return false
+}
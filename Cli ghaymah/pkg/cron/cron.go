@@ -0,0 +1,258 @@
+package cron
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// macros are the shorthands accepted instead of the five fields
+var macros = map[string]string{
+    "@yearly":   "0 0 1 1 *",
+    "@annually": "0 0 1 1 *",
+    "@monthly":  "0 0 1 * *",
+    "@weekly":   "0 0 * * 0",
+    "@daily":    "0 0 * * *",
+    "@midnight": "0 0 * * *",
+    "@hourly":   "0 * * * *",
+}
+
+// field describes the values allowed in a schedule field
+type field struct {
+    name     string
+    min, max int
+    names    []string // names of the values from min, e.g. jan or sun
+}
+
+var fields = []field{
+    {name: "minute", min: 0, max: 59},
+    {name: "hour", min: 0, max: 23},
+    {name: "day of month", min: 1, max: 31},
+    {name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+    {name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// maxSearch bounds the search for the next run of a schedule that matches
+// rarely or never, such as February 30th
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed cron schedule
+type Schedule struct {
+    minute, hour, dom, month, dow uint64
+
+    // domAny and dowAny record a * day field: when both days are restricted,
+    // either one matching is enough, as in cron
+    domAny, dowAny bool
+
+    // fixed records a schedule without wildcard minute or hour, which runs
+    // once when clocks change, as in cron
+    fixed bool
+}
+
+// Parse parses a standard five field cron schedule (minute, hour, day of
+// month, month, day of week) or one of @yearly, @monthly, @weekly, @daily,
+// @midnight and @hourly. Fields accept *, values, ranges, steps and lists,
+// and month and day names.
+func Parse(spec string) (*Schedule, error) {
+    spec = strings.TrimSpace(spec)
+    if expanded, ok := macros[strings.ToLower(spec)]; ok {
+        spec = expanded
+    } else if strings.HasPrefix(spec, "@") {
+        return nil, fmt.Errorf("unknown schedule %s", spec)
+    }
+
+    parts := strings.Fields(spec)
+    if len(parts) != len(fields) {
+        return nil, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(parts))
+    }
+
+    bits := make([]uint64, len(fields))
+    for i, part := range parts {
+        var err error
+        if bits[i], err = parseField(part, fields[i]); err != nil {
+            return nil, err
+        }
+    }
+
+    // 7 is another name for Sunday
+    if bits[4]&(1<<7) != 0 {
+        bits[4] |= 1
+    }
+
+    return &Schedule{
+        minute: bits[0],
+        hour:   bits[1],
+        dom:    bits[2],
+        month:  bits[3],
+        dow:    bits[4],
+        domAny: parts[2] == "*" || parts[2] == "?",
+        dowAny: parts[4] == "*" || parts[4] == "?",
+        fixed:  !strings.HasPrefix(parts[0], "*") && !strings.HasPrefix(parts[1], "*"),
+    }, nil
+}
+
+// parseField parses a comma separated list of values, ranges and steps
+func parseField(s string, f field) (uint64, error) {
+    var bits uint64
+    for _, item := range strings.Split(s, ",") {
+        rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+        step := 1
+        if hasStep {
+            n, err := strconv.Atoi(stepPart)
+            if err != nil || n <= 0 {
+                return 0, fmt.Errorf("%s: invalid step %q", f.name, stepPart)
+            }
+            step = n
+        }
+
+        var low, high int
+        switch {
+        case rangePart == "*" || rangePart == "?":
+            low, high = f.min, f.max
+        case strings.Contains(rangePart, "-"):
+            lowPart, highPart, _ := strings.Cut(rangePart, "-")
+            var err error
+            if low, err = parseValue(lowPart, f); err != nil {
+                return 0, err
+            }
+            if high, err = parseValue(highPart, f); err != nil {
+                return 0, err
+            }
+            if low > high {
+                return 0, fmt.Errorf("%s: invalid range %s", f.name, rangePart)
+            }
+        default:
+            value, err := parseValue(rangePart, f)
+            if err != nil {
+                return 0, err
+            }
+            low, high = value, value
+            if hasStep {
+                high = f.max
+            }
+        }
+
+        for v := low; v <= high; v += step {
+            bits |= 1 << uint(v)
+        }
+    }
+    return bits, nil
+}
+
+// parseValue parses a number or a name within the bounds of a field
+func parseValue(s string, f field) (int, error) {
+    for i, name := range f.names {
+        if strings.EqualFold(s, name) {
+            return f.min + i, nil
+        }
+    }
+    n, err := strconv.Atoi(s)
+    if err != nil {
+        return 0, fmt.Errorf("%s: invalid value %q", f.name, s)
+    }
+    if n < f.min || n > f.max {
+        return 0, fmt.Errorf("%s: %d is out of range %d-%d", f.name, n, f.min, f.max)
+    }
+    return n, nil
+}
+
+// Next returns the first time after t matching the schedule, in the location
+// of t, or the zero time if the schedule never matches. Schedules with a
+// wildcard minute or hour follow the clock across daylight saving changes.
+// Other schedules run once: at the change when their time is skipped, and
+// the first time only when it is repeated.
+func (s *Schedule) Next(t time.Time) time.Time {
+    t = t.Truncate(time.Minute).Add(time.Minute)
+    limit := t.Add(maxSearch)
+
+    for t.Before(limit) {
+        if s.fixed && s.skipped(t) {
+            return t
+        }
+        if s.month&(1<<uint(t.Month())) == 0 {
+            t = startOfDay(t.Year(), t.Month()+1, 1, t.Location())
+            continue
+        }
+        if !s.matchesDay(t) {
+            t = startOfDay(t.Year(), t.Month(), t.Day()+1, t.Location())
+            continue
+        }
+        // Hours and minutes are added rather than set, since setting a time
+        // skipped by a clock change may go back
+        if s.hour&(1<<uint(t.Hour())) == 0 {
+            t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+            continue
+        }
+        if s.minute&(1<<uint(t.Minute())) == 0 || (s.fixed && repeated(t)) {
+            t = t.Add(time.Minute)
+            continue
+        }
+        return t
+    }
+    return time.Time{}
+}
+
+// matches reports whether the clock time c, in UTC, matches the schedule
+func (s *Schedule) matches(c time.Time) bool {
+    return s.month&(1<<uint(c.Month())) != 0 && s.matchesDay(c) &&
+        s.hour&(1<<uint(c.Hour())) != 0 && s.minute&(1<<uint(c.Minute())) != 0
+}
+
+// skipped reports whether clocks went forward right before t, skipping a
+// time matching the schedule
+func (s *Schedule) skipped(t time.Time) bool {
+    before := t.Add(-time.Minute)
+    if zoneOffset(t) <= zoneOffset(before) {
+        return false
+    }
+    for c := clock(before).Add(time.Minute); c.Before(clock(t)); c = c.Add(time.Minute) {
+        if s.matches(c) {
+            return true
+        }
+    }
+    return false
+}
+
+// repeated reports whether the clock already showed the time of t, clocks
+// having gone back less than 3 hours before
+func repeated(t time.Time) bool {
+    back := zoneOffset(t.Add(-3*time.Hour)) - zoneOffset(t)
+    if back <= 0 {
+        return false
+    }
+    return clock(t.Add(-time.Duration(back) * time.Second)).Equal(clock(t))
+}
+
+// startOfDay returns the first instant of a day, which is not midnight where
+// clocks go forward at midnight
+func startOfDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+    t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+    if t.Hour() > 12 {
+        // The skipped midnight was taken in the new offset, back on the day before
+        t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+    }
+    return t
+}
+
+// clock returns the time shown by the clock at t, as a UTC time
+func clock(t time.Time) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// zoneOffset returns the offset of the zone at t, in seconds east of UTC
+func zoneOffset(t time.Time) int {
+    _, offset := t.Zone()
+    return offset
+}
+
+// matchesDay reports whether the day of t matches the day fields
+func (s *Schedule) matchesDay(t time.Time) bool {
+    dom := s.dom&(1<<uint(t.Day())) != 0
+    dow := s.dow&(1<<uint(t.Weekday())) != 0
+    if s.domAny || s.dowAny {
+        return dom && dow
+    }
+    return dom || dow
+}
@@ -0,0 +1,198 @@
+package cron
+
+import (
+    "strings"
+    "testing"
+    "time"
+    _ "time/tzdata"
+)
+
+func TestParseErrors(t *testing.T) {
+    tests := map[string]string{
+        "* * * *":      "expected 5 fields",
+        "@every 5m":    "unknown schedule @every 5m",
+        "60 * * * *":   "minute: 60 is out of range 0-59",
+        "* 24 * * *":   "hour: 24 is out of range 0-23",
+        "* * 0 * *":    "day of month: 0 is out of range 1-31",
+        "* * * 13 *":   "month: 13 is out of range 1-12",
+        "* * * * 8":    "day of week: 8 is out of range 0-7",
+        "*/0 * * * *":  `minute: invalid step "0"`,
+        "5-1 * * * *":  "minute: invalid range 5-1",
+        "* * * foo *":  `month: invalid value "foo"`,
+        "* * * * mon-": `day of week: invalid value ""`,
+    }
+    for spec, want := range tests {
+        if _, err := Parse(spec); err == nil || !strings.Contains(err.Error(), want) {
+            t.Errorf("Parse(%q): got %v, want %q", spec, err, want)
+        }
+    }
+}
+
+func TestNext(t *testing.T) {
+    // A Monday
+    from := time.Date(2026, 10, 19, 10, 7, 0, 0, time.UTC)
+    tests := []struct {
+        spec string
+        from time.Time
+        want time.Time
+    }{
+        {spec: "@hourly", want: time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)},
+        {spec: "@daily", want: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+        {spec: "@midnight", want: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+        {spec: "@weekly", want: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+        {spec: "@monthly", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+        {spec: "@YEARLY", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+        // Strictly after the given time
+        {spec: "7 10 * * *", want: time.Date(2026, 10, 20, 10, 7, 0, 0, time.UTC)},
+        {spec: "7 10 * * *", from: from.Add(-30 * time.Second), want: from},
+        // Ranges, steps and lists
+        {spec: "*/15 * * * *", want: time.Date(2026, 10, 19, 10, 15, 0, 0, time.UTC)},
+        {spec: "5/15 * * * *", want: time.Date(2026, 10, 19, 10, 20, 0, 0, time.UTC)},
+        {spec: "5/15 * * * *", from: time.Date(2026, 10, 19, 10, 50, 0, 0, time.UTC), want: time.Date(2026, 10, 19, 11, 5, 0, 0, time.UTC)},
+        {spec: "1-5/2 * * * *", want: time.Date(2026, 10, 19, 11, 1, 0, 0, time.UTC)},
+        {spec: "1-5/2 * * * *", from: time.Date(2026, 10, 19, 11, 3, 0, 0, time.UTC), want: time.Date(2026, 10, 19, 11, 5, 0, 0, time.UTC)},
+        {spec: "0 8,12-14 * * *", want: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)},
+        // Month and day names, in any case
+        {spec: "0 9 * * mon-fri", want: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)},
+        {spec: "0 0 1 JAN *", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+        {spec: "30 8 * dec Sat", want: time.Date(2026, 12, 5, 8, 30, 0, 0, time.UTC)},
+        // 7 is Sunday, as 0 is
+        {spec: "0 12 * * 7", want: time.Date(2026, 10, 25, 12, 0, 0, 0, time.UTC)},
+        {spec: "0 12 * * 0", want: time.Date(2026, 10, 25, 12, 0, 0, 0, time.UTC)},
+        {spec: "0 12 * * fri-7", want: time.Date(2026, 10, 23, 12, 0, 0, 0, time.UTC)},
+        // With both days restricted, either one matches
+        {spec: "0 0 13 * mon", want: time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC)},
+        {spec: "0 0 13 * mon", from: time.Date(2026, 12, 8, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 12, 13, 0, 0, 0, 0, time.UTC)},
+        // With one of them *, the other one must match
+        {spec: "0 0 13 * *", want: time.Date(2026, 11, 13, 0, 0, 0, 0, time.UTC)},
+        {spec: "0 0 ? * mon", want: time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC)},
+        // Rare and impossible dates
+        {spec: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+        {spec: "0 0 31 4 *", want: time.Time{}},
+        {spec: "0 0 30 2 *", want: time.Time{}},
+    }
+    for _, tt := range tests {
+        schedule, err := Parse(tt.spec)
+        if err != nil {
+            t.Errorf("Parse(%q): %v", tt.spec, err)
+            continue
+        }
+        start := tt.from
+        if start.IsZero() {
+            start = from
+        }
+        if got := schedule.Next(start); !got.Equal(tt.want) {
+            t.Errorf("%q after %s: got %s, want %s", tt.spec, start, got, tt.want)
+        }
+    }
+}
+
+func TestNextDaylightSaving(t *testing.T) {
+    location := func(name string) *time.Location {
+        loc, err := time.LoadLocation(name)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return loc
+    }
+    newYork, berlin, santiago := location("America/New_York"), location("Europe/Berlin"), location("America/Santiago")
+    // New York clocks go from 02:00 to 03:00 on March 8 2026, and from 02:00 back to 01:00 on November 1
+    edt, est := time.FixedZone("EDT", -4*60*60), time.FixedZone("EST", -5*60*60)
+
+    tests := []struct {
+        name string
+        spec string
+        from time.Time
+        want []time.Time
+    }{
+        {
+            name: "skipped time runs at the change",
+            spec: "30 2 * * *",
+            from: time.Date(2026, 3, 7, 12, 0, 0, 0, newYork),
+            want: []time.Time{
+                time.Date(2026, 3, 8, 3, 0, 0, 0, edt),
+                time.Date(2026, 3, 9, 2, 30, 0, 0, edt),
+            },
+        },
+        {
+            name: "time after the skipped hour",
+            spec: "0 3 * * *",
+            from: time.Date(2026, 3, 7, 12, 0, 0, 0, newYork),
+            want: []time.Time{
+                time.Date(2026, 3, 8, 3, 0, 0, 0, edt),
+                time.Date(2026, 3, 9, 3, 0, 0, 0, edt),
+            },
+        },
+        {
+            name: "hourly follows the clock when it goes forward",
+            spec: "0 * * * *",
+            from: time.Date(2026, 3, 8, 0, 30, 0, 0, est),
+            want: []time.Time{
+                time.Date(2026, 3, 8, 1, 0, 0, 0, est),
+                time.Date(2026, 3, 8, 3, 0, 0, 0, edt),
+                time.Date(2026, 3, 8, 4, 0, 0, 0, edt),
+            },
+        },
+        {
+            name: "repeated time runs once",
+            spec: "30 1 * * *",
+            from: time.Date(2026, 10, 31, 12, 0, 0, 0, newYork),
+            want: []time.Time{
+                time.Date(2026, 11, 1, 1, 30, 0, 0, edt),
+                time.Date(2026, 11, 2, 1, 30, 0, 0, est),
+            },
+        },
+        {
+            name: "steps follow the clock when it goes back",
+            spec: "*/30 * * * *",
+            from: time.Date(2026, 11, 1, 1, 15, 0, 0, edt),
+            want: []time.Time{
+                time.Date(2026, 11, 1, 1, 30, 0, 0, edt),
+                time.Date(2026, 11, 1, 1, 0, 0, 0, est),
+                time.Date(2026, 11, 1, 1, 30, 0, 0, est),
+                time.Date(2026, 11, 1, 2, 0, 0, 0, est),
+            },
+        },
+        {
+            name: "repeated time in Europe",
+            spec: "30 2 * * *",
+            from: time.Date(2026, 10, 24, 12, 0, 0, 0, berlin),
+            want: []time.Time{
+                time.Date(2026, 10, 25, 2, 30, 0, 0, time.FixedZone("CEST", 2*60*60)),
+                time.Date(2026, 10, 26, 2, 30, 0, 0, time.FixedZone("CET", 60*60)),
+            },
+        },
+        {
+            // Santiago clocks go from 00:00 to 01:00 on September 6 2026
+            name: "skipped midnight",
+            spec: "0 0 * * *",
+            from: time.Date(2026, 9, 5, 12, 0, 0, 0, santiago),
+            want: []time.Time{
+                time.Date(2026, 9, 6, 1, 0, 0, 0, santiago),
+                time.Date(2026, 9, 7, 0, 0, 0, 0, santiago),
+            },
+        },
+        {
+            name: "day after a skipped midnight",
+            spec: "0 12 * * *",
+            from: time.Date(2026, 9, 5, 13, 0, 0, 0, santiago),
+            want: []time.Time{
+                time.Date(2026, 9, 6, 12, 0, 0, 0, santiago),
+            },
+        },
+    }
+    for _, tt := range tests {
+        schedule, err := Parse(tt.spec)
+        if err != nil {
+            t.Fatal(err)
+        }
+        got := tt.from
+        for i, want := range tt.want {
+            got = schedule.Next(got)
+            if !got.Equal(want) {
+                t.Errorf("%s: run %d of %q is %s, want %s", tt.name, i+1, tt.spec, got, want.In(tt.from.Location()))
+                break
+            }
+        }
+    }
+}
@@ -0,0 +1,105 @@
+package exporter
+
+import (
+    "context"
+    "net/http"
+    "sort"
+    "sync"
+    "time"
+    "ghaymah-cli/pkg/types"
+)
+
+// StatusFunc fetches the detailed status of an application
+type StatusFunc func(appName string) (*types.StatusResponse, error)
+
+// AppsFunc returns the applications to export, called before each poll so
+// that applications matching a selector are picked up as they are created
+type AppsFunc func() ([]string, error)
+
+// sample is the last status fetched for an application
+type sample struct {
+    status   *types.StatusResponse
+    err      error
+    duration time.Duration
+}
+
+// Exporter periodically fetches the status of applications and serves it
+// in the Prometheus text format
+type Exporter struct {
+    Status   StatusFunc
+    Apps     AppsFunc
+    Interval time.Duration
+    Logf     func(format string, args ...interface{})
+
+    mu       sync.RWMutex
+    samples  map[string]sample
+    lastPoll time.Time
+    pollErr  error
+}
+
+// Run polls the applications every Interval until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context) {
+    ticker := time.NewTicker(e.Interval)
+    defer ticker.Stop()
+    for {
+        e.Poll()
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// Poll fetches the status of each application once. Applications that
+// can't be listed keep the samples of the previous poll.
+func (e *Exporter) Poll() {
+    apps, err := e.Apps()
+    if err != nil {
+        e.logf("Failed to list applications: %v\n", err)
+        e.mu.Lock()
+        e.pollErr = err
+        e.lastPoll = time.Now()
+        e.mu.Unlock()
+        return
+    }
+
+    samples := make(map[string]sample, len(apps))
+    for _, app := range apps {
+        start := time.Now()
+        status, err := e.Status(app)
+        if err != nil {
+            e.logf("Failed to get status of %s: %v\n", app, err)
+        }
+        samples[app] = sample{status: status, err: err, duration: time.Since(start)}
+    }
+
+    e.mu.Lock()
+    defer e.mu.Unlock()
+    e.samples = samples
+    e.pollErr = nil
+    e.lastPoll = time.Now()
+}
+
+// logf logs a polling problem when Logf is set
+func (e *Exporter) logf(format string, args ...interface{}) {
+    if e.Logf != nil {
+        e.Logf(format, args...)
+    }
+}
+
+// ServeHTTP serves the metrics of the last poll
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+    e.WriteMetrics(w)
+}
+
+// appNames returns the polled applications in a stable order
+func (e *Exporter) appNames() []string {
+    names := make([]string, 0, len(e.samples))
+    for name := range e.samples {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
@@ -0,0 +1,205 @@
+package exporter
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "strings"
+    "sync"
+    "testing"
+    "ghaymah-cli/pkg/api"
+)
+
+// fakeAPI serves /apps/status with a CPU usage that grows at each request,
+// failing for the applications listed in failing
+type fakeAPI struct {
+    mu       sync.Mutex
+    requests map[string]int
+    failing  map[string]bool
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    if r.URL.Path != "/apps/status" || r.URL.Query().Get("detailed") != "true" {
+        http.NotFound(w, r)
+        return
+    }
+    name := r.URL.Query().Get("name")
+
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    if f.failing[name] {
+        http.Error(w, "upstream unavailable", http.StatusBadGateway)
+        return
+    }
+    f.requests[name]++
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "state":          "running",
+        "version":        3,
+        "lastDeployment": "2026-10-01T12:00:00Z",
+        "resources":      map[string]float64{"cpuUsage": float64(10 * f.requests[name]), "memoryUsage": 40, "storageUsage": 5},
+        "health":         map[string]interface{}{"status": "healthy", "instances": []interface{}{}},
+        "instances":      []map[string]interface{}{{"id": "shop-1", "state": "running", "restarts": 2}},
+    })
+}
+
+func (f *fakeAPI) setFailing(name string, failing bool) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.failing[name] = failing
+}
+
+// newTestExporter returns an exporter polling the applications through the API client
+func newTestExporter(t *testing.T, apps *[]string, appsErr *error) (*Exporter, *fakeAPI) {
+    fake := &fakeAPI{requests: map[string]int{}, failing: map[string]bool{}}
+    server := httptest.NewServer(fake)
+    t.Cleanup(server.Close)
+
+    client := api.NewGhaymahAPI(server.URL, "test-token")
+    return &Exporter{
+        Status: client.GetDetailedStatus,
+        Apps: func() ([]string, error) {
+            return *apps, *appsErr
+        },
+    }, fake
+}
+
+// scrape returns the metrics as served to Prometheus
+func scrape(t *testing.T, e *Exporter) string {
+    var buf bytes.Buffer
+    if err := e.WriteMetrics(&buf); err != nil {
+        t.Fatal(err)
+    }
+    return buf.String()
+}
+
+func assertContains(t *testing.T, metrics string, lines ...string) {
+    t.Helper()
+    for _, line := range lines {
+        if !strings.Contains(metrics, line+"\n") {
+            t.Errorf("metrics are missing %q:\n%s", line, metrics)
+        }
+    }
+}
+
+func assertMissing(t *testing.T, metrics string, prefixes ...string) {
+    t.Helper()
+    for _, prefix := range prefixes {
+        if strings.Contains(metrics, prefix) {
+            t.Errorf("metrics unexpectedly contain %q:\n%s", prefix, metrics)
+        }
+    }
+}
+
+func TestPollWritesMetrics(t *testing.T) {
+    apps := []string{"shop", "blog"}
+    var appsErr error
+    e, _ := newTestExporter(t, &apps, &appsErr)
+
+    e.Poll()
+    metrics := scrape(t, e)
+    assertContains(t, metrics,
+        "# HELP ghaymah_app_up Whether the last status request of the application succeeded.",
+        "# TYPE ghaymah_app_up gauge",
+        `ghaymah_app_up{app="blog"} 1`,
+        `ghaymah_app_up{app="shop"} 1`,
+        `ghaymah_app_state{app="shop",state="running"} 1`,
+        `ghaymah_app_release_version{app="shop"} 3`,
+        `ghaymah_app_cpu_usage_percent{app="shop"} 10`,
+        `ghaymah_app_memory_usage_percent{app="shop"} 40`,
+        `ghaymah_app_last_deployment_timestamp_seconds{app="shop"} 1790856000`,
+        `ghaymah_app_healthy{app="shop"} 1`,
+        `ghaymah_app_deployments_locked{app="shop"} 0`,
+        `ghaymah_app_instance_restarts{app="shop",instance="shop-1"} 2`,
+        "ghaymah_exporter_poll_success 1",
+    )
+
+    // Each family is written once, with all its samples together
+    if n := strings.Count(metrics, "# TYPE ghaymah_app_cpu_usage_percent gauge"); n != 1 {
+        t.Errorf("TYPE line of ghaymah_app_cpu_usage_percent written %d times", n)
+    }
+    if strings.Index(metrics, `ghaymah_app_up{app="blog"}`) > strings.Index(metrics, `ghaymah_app_up{app="shop"}`) {
+        t.Errorf("applications are not sorted:\n%s", metrics)
+    }
+
+    // The next poll replaces the values
+    e.Poll()
+    metrics = scrape(t, e)
+    assertContains(t, metrics, `ghaymah_app_cpu_usage_percent{app="shop"} 20`)
+    assertMissing(t, metrics, `ghaymah_app_cpu_usage_percent{app="shop"} 10`)
+}
+
+func TestPollStatusError(t *testing.T) {
+    apps := []string{"shop", "blog"}
+    var appsErr error
+    e, fake := newTestExporter(t, &apps, &appsErr)
+
+    e.Poll()
+    fake.setFailing("shop", true)
+    e.Poll()
+
+    // The failing application is reported down, without its previous values
+    metrics := scrape(t, e)
+    assertContains(t, metrics,
+        `ghaymah_app_up{app="shop"} 0`,
+        `ghaymah_app_up{app="blog"} 1`,
+        `ghaymah_app_cpu_usage_percent{app="blog"} 20`,
+        "ghaymah_exporter_poll_success 1",
+    )
+    assertMissing(t, metrics,
+        `ghaymah_app_state{app="shop"`,
+        `ghaymah_app_cpu_usage_percent{app="shop"}`,
+        `ghaymah_app_instance_restarts{app="shop"`,
+    )
+
+    fake.setFailing("shop", false)
+    e.Poll()
+    assertContains(t, scrape(t, e), `ghaymah_app_up{app="shop"} 1`, `ghaymah_app_cpu_usage_percent{app="shop"} 20`)
+}
+
+func TestPollListError(t *testing.T) {
+    apps := []string{"shop"}
+    var appsErr error
+    e, _ := newTestExporter(t, &apps, &appsErr)
+
+    e.Poll()
+    appsErr = errors.New("connection refused")
+    e.Poll()
+
+    // The previous samples are kept, flagged as stale by the poll status
+    metrics := scrape(t, e)
+    assertContains(t, metrics,
+        `ghaymah_app_up{app="shop"} 1`,
+        `ghaymah_app_cpu_usage_percent{app="shop"} 10`,
+        "ghaymah_exporter_poll_success 0",
+    )
+
+    appsErr = nil
+    e.Poll()
+    assertContains(t, scrape(t, e), `ghaymah_app_cpu_usage_percent{app="shop"} 20`, "ghaymah_exporter_poll_success 1")
+}
+
+func TestEscapeLabel(t *testing.T) {
+    tests := map[string]string{
+        `shop`:        `shop`,
+        `say "hi"`:    `say \"hi\"`,
+        `C:\apps`:     `C:\\apps`,
+        "line\nbreak": `line\nbreak`,
+    }
+    for value, want := range tests {
+        if got := escapeLabel(value); got != want {
+            t.Errorf("escapeLabel(%q) = %s, want %s", value, got, want)
+        }
+    }
+}
+
+func ExampleExporter_WriteMetrics() {
+    e := &Exporter{}
+    e.WriteMetrics(os.Stdout)
+    // Output:
+    // # HELP ghaymah_exporter_poll_success Whether the applications could be listed at the last poll.
+    // # TYPE ghaymah_exporter_poll_success gauge
+    // ghaymah_exporter_poll_success 1
+}
@@ -0,0 +1,142 @@
+package exporter
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+)
+
+// metric is a Prometheus metric family being written
+type metric struct {
+    w       *bufio.Writer
+    name    string
+    started bool
+    help    string
+    kind    string
+}
+
+// sample writes a sample of the metric, preceded by its HELP and TYPE lines
+// for the first sample
+func (m *metric) sample(value float64, labels ...string) {
+    if !m.started {
+        fmt.Fprintf(m.w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+        m.started = true
+    }
+    m.w.WriteString(m.name)
+    if len(labels) > 0 {
+        m.w.WriteByte('{')
+        for i := 0; i+1 < len(labels); i += 2 {
+            if i > 0 {
+                m.w.WriteByte(',')
+            }
+            fmt.Fprintf(m.w, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+        }
+        m.w.WriteByte('}')
+    }
+    fmt.Fprintf(m.w, " %s\n", strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// labelEscaper escapes label values as required by the text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value
+func escapeLabel(value string) string {
+    return labelEscaper.Replace(value)
+}
+
+// WriteMetrics writes the metrics of the last poll in the Prometheus text format
+func (e *Exporter) WriteMetrics(out io.Writer) error {
+    e.mu.RLock()
+    defer e.mu.RUnlock()
+
+    w := bufio.NewWriter(out)
+    gauge := func(name, help string) *metric {
+        return &metric{w: w, name: name, help: help, kind: "gauge"}
+    }
+
+    up := gauge("ghaymah_app_up", "Whether the last status request of the application succeeded.")
+    state := gauge("ghaymah_app_state", "State of the application, 1 for the current state.")
+    version := gauge("ghaymah_app_release_version", "Version of the running release.")
+    cpu := gauge("ghaymah_app_cpu_usage_percent", "CPU usage in percent of the limit.")
+    memory := gauge("ghaymah_app_memory_usage_percent", "Memory usage in percent of the limit.")
+    storage := gauge("ghaymah_app_storage_usage_percent", "Storage usage in percent of the limit.")
+    lastDeployment := gauge("ghaymah_app_last_deployment_timestamp_seconds", "Unix time of the last deployment.")
+    healthy := gauge("ghaymah_app_healthy", "Whether the health checks of the application pass.")
+    locked := gauge("ghaymah_app_deployments_locked", "Whether deployments of the application are locked.")
+    restarts := gauge("ghaymah_app_instance_restarts", "Restarts of each instance of the application.")
+    duration := gauge("ghaymah_exporter_status_duration_seconds", "Duration of the last status request of the application.")
+
+    // Each family is written in full before the next one, as the format requires
+    names := e.appNames()
+    for _, name := range names {
+        s := e.samples[name]
+        up.sample(boolValue(s.err == nil), "app", name)
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil {
+            state.sample(1, "app", name, "state", s.status.State)
+        }
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil && s.status.Version > 0 {
+            version.sample(float64(s.status.Version), "app", name)
+        }
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil {
+            cpu.sample(s.status.Resources.CPUUsage, "app", name)
+        }
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil {
+            memory.sample(s.status.Resources.MemoryUsage, "app", name)
+        }
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil {
+            storage.sample(s.status.Resources.StorageUsage, "app", name)
+        }
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil && !s.status.LastDeployment.IsZero() {
+            lastDeployment.sample(float64(s.status.LastDeployment.Unix()), "app", name)
+        }
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil && s.status.Health != nil {
+            healthy.sample(boolValue(s.status.Health.Status == "healthy"), "app", name)
+        }
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil {
+            locked.sample(boolValue(s.status.Lock != nil), "app", name)
+        }
+    }
+    for _, name := range names {
+        if s := e.samples[name]; s.status != nil {
+            for _, inst := range s.status.Instances {
+                restarts.sample(float64(inst.Restarts), "app", name, "instance", inst.ID)
+            }
+        }
+    }
+    for _, name := range names {
+        duration.sample(e.samples[name].duration.Seconds(), "app", name)
+    }
+
+    if !e.lastPoll.IsZero() {
+        gauge("ghaymah_exporter_last_poll_timestamp_seconds", "Unix time of the last poll.").sample(float64(e.lastPoll.Unix()))
+    }
+    gauge("ghaymah_exporter_poll_success", "Whether the applications could be listed at the last poll.").sample(boolValue(e.pollErr == nil))
+
+    return w.Flush()
+}
+
+// boolValue converts a condition to 1 or 0
+func boolValue(b bool) float64 {
+    if b {
+        return 1
+    }
+    return 0
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+    "fmt"
+    "strings"
+    "time"
+    "ghaymah-cli/pkg/types"
+)
+
+// Chart renders a series as an ASCII line chart of at most width columns and
+// height rows, with the value axis on the left and the time axis below.
+// When there are more points than columns, each column shows the highest
+// value of the points it covers so that spikes remain visible.
+func Chart(points []types.MetricPoint, unit string, width, height int) string {
+    if len(points) == 0 {
+        return "  (no data)\n"
+    }
+
+    columns := resample(points, width)
+    lo, hi := columns[0], columns[0]
+    for _, v := range columns {
+        lo = min(lo, v)
+        hi = max(hi, v)
+    }
+    if hi == lo {
+        hi = lo + 1
+    }
+
+    grid := make([][]byte, height)
+    for r := range grid {
+        grid[r] = []byte(strings.Repeat(" ", len(columns)))
+    }
+    previous := -1
+    for c, v := range columns {
+        level := int((v-lo)/(hi-lo)*float64(height-1) + 0.5)
+        if previous >= 0 {
+            // Join vertical jumps to the previous column
+            for l := min(level, previous) + 1; l < max(level, previous); l++ {
+                grid[height-1-l][c] = '|'
+            }
+        }
+        grid[height-1-level][c] = '*'
+        previous = level
+    }
+
+    labels := make([]string, height)
+    labels[0] = FormatValue(hi, unit)
+    labels[height-1] = FormatValue(lo, unit)
+    if height >= 5 {
+        labels[height/2] = FormatValue(hi-(hi-lo)*float64(height/2)/float64(height-1), unit)
+    }
+    labelWidth := 0
+    for _, label := range labels {
+        labelWidth = max(labelWidth, len(label))
+    }
+
+    var b strings.Builder
+    for r, row := range grid {
+        fmt.Fprintf(&b, "%*s |%s\n", labelWidth, labels[r], row)
+    }
+    fmt.Fprintf(&b, "%*s +%s\n", labelWidth, "", strings.Repeat("-", len(columns)))
+
+    start, end := timeLabels(points[0].Timestamp, points[len(points)-1].Timestamp)
+    gap := len(columns) - len(start) - len(end)
+    if gap >= 1 {
+        fmt.Fprintf(&b, "%*s  %s%s%s\n", labelWidth, "", start, strings.Repeat(" ", gap), end)
+    } else {
+        fmt.Fprintf(&b, "%*s  %s\n", labelWidth, "", start)
+    }
+    return b.String()
+}
+
+// resample reduces points to at most width values, keeping the highest value of each bucket
+func resample(points []types.MetricPoint, width int) []float64 {
+    n := min(len(points), width)
+    columns := make([]float64, n)
+    for c := range columns {
+        from := c * len(points) / n
+        to := (c + 1) * len(points) / n
+        columns[c] = points[from].Value
+        for _, p := range points[from+1 : to] {
+            columns[c] = max(columns[c], p.Value)
+        }
+    }
+    return columns
+}
+
+// timeLabels formats the bounds of the time axis, with the date when the range spans several days
+func timeLabels(start, end time.Time) (string, string) {
+    layout := "15:04"
+    if end.Sub(start) >= 24*time.Hour || start.YearDay() != end.YearDay() {
+        layout = "01-02 15:04"
+    }
+    return start.Local().Format(layout), end.Local().Format(layout)
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+    "ghaymah-cli/pkg/types"
+)
+
+// series returns points one minute apart with the given values
+func series(values ...float64) []types.MetricPoint {
+    start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+    points := make([]types.MetricPoint, len(values))
+    for i, v := range values {
+        points[i] = types.MetricPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: v}
+    }
+    return points
+}
+
+// chartRows returns the plot area of a chart, without the value labels and axes
+func chartRows(t *testing.T, chart string, height int) []string {
+    t.Helper()
+    lines := strings.Split(strings.TrimSuffix(chart, "\n"), "\n")
+    if len(lines) != height+2 {
+        t.Fatalf("got %d lines, want %d rows, the axis and the time labels:\n%s", len(lines), height, chart)
+    }
+    rows := make([]string, height)
+    for i := range rows {
+        _, row, ok := strings.Cut(lines[i], "|")
+        if !ok {
+            t.Fatalf("row %d has no axis: %q", i, lines[i])
+        }
+        rows[i] = row
+    }
+    return rows
+}
+
+func TestResample(t *testing.T) {
+    points := series(1, 5, 2, 0, 0, 9, 3, 1, 4, 2)
+
+    // Each column keeps the highest value of the points it covers
+    if got, want := resample(points, 4), []float64{5, 2, 9, 4}; !reflect.DeepEqual(got, want) {
+        t.Errorf("resample(10 points, 4) = %v, want %v", got, want)
+    }
+    // With enough columns, every point has its own
+    if got, want := resample(points, 20), []float64{1, 5, 2, 0, 0, 9, 3, 1, 4, 2}; !reflect.DeepEqual(got, want) {
+        t.Errorf("resample(10 points, 20) = %v, want %v", got, want)
+    }
+}
+
+func TestChartKeepsSpikes(t *testing.T) {
+    values := make([]float64, 100)
+    values[37] = 100
+    rows := chartRows(t, Chart(series(values...), "%", 10, 5), 5)
+
+    if len(rows[0]) != 10 || strings.Count(rows[0], "*") != 1 {
+        t.Errorf("top row = %q, want the spike in one of 10 columns", rows[0])
+    }
+    if strings.Count(rows[4], "*") != 9 {
+        t.Errorf("bottom row = %q, want the 9 other columns", rows[4])
+    }
+}
+
+func TestChartFlatSeries(t *testing.T) {
+    chart := Chart(series(50, 50, 50), "%", 20, 4)
+    rows := chartRows(t, chart, 4)
+
+    for i, row := range rows[:3] {
+        if strings.TrimSpace(row) != "" {
+            t.Errorf("row %d = %q, want it empty", i, row)
+        }
+    }
+    if rows[3] != "***" {
+        t.Errorf("bottom row = %q, want the series on it", rows[3])
+    }
+    if !strings.HasPrefix(strings.Split(chart, "\n")[3], "50.0% |") {
+        t.Errorf("the bottom of the value axis isn't the value of the series:\n%s", chart)
+    }
+}
+
+func TestChartSinglePoint(t *testing.T) {
+    chart := Chart(series(42), "ms", 20, 5)
+    rows := chartRows(t, chart, 5)
+
+    if rows[4] != "*" || strings.Count(chart, "*") != 1 {
+        t.Errorf("got\n%s\nwant a single point in the bottom row", chart)
+    }
+    if Chart(nil, "ms", 20, 5) != "  (no data)\n" {
+        t.Error("an empty series isn't reported as having no data")
+    }
+}
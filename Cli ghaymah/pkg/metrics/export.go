@@ -0,0 +1,70 @@
+package metrics
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "io"
+    "sort"
+    "strconv"
+    "time"
+    "ghaymah-cli/pkg/types"
+)
+
+// seriesExport is a series with its statistics, as exported in JSON
+type seriesExport struct {
+    types.MetricSeries
+    Summary Stats `json:"summary"`
+}
+
+// WriteJSON writes the series and their statistics as indented JSON
+func WriteJSON(w io.Writer, resp *types.MetricsResponse) error {
+    export := struct {
+        Start       time.Time      `json:"start"`
+        End         time.Time      `json:"end"`
+        StepSeconds int            `json:"stepSeconds"`
+        Series      []seriesExport `json:"series"`
+    }{
+        Start:       resp.Start,
+        End:         resp.End,
+        StepSeconds: resp.StepSeconds,
+        Series:      []seriesExport{},
+    }
+    for _, series := range resp.Series {
+        export.Series = append(export.Series, seriesExport{MetricSeries: series, Summary: Summarize(series.Points)})
+    }
+
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(export)
+}
+
+// WriteCSV writes the series as CSV with one row per timestamp and one column
+// per metric. Cells of metrics without a value at a timestamp are left empty.
+func WriteCSV(w io.Writer, resp *types.MetricsResponse) error {
+    header := []string{"timestamp"}
+    rows := map[time.Time][]string{}
+    for i, series := range resp.Series {
+        header = append(header, series.Name)
+        for _, p := range series.Points {
+            t := p.Timestamp.UTC()
+            if rows[t] == nil {
+                rows[t] = make([]string, len(resp.Series))
+            }
+            rows[t][i] = strconv.FormatFloat(p.Value, 'f', -1, 64)
+        }
+    }
+
+    timestamps := make([]time.Time, 0, len(rows))
+    for t := range rows {
+        timestamps = append(timestamps, t)
+    }
+    sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+
+    cw := csv.NewWriter(w)
+    cw.Write(header)
+    for _, t := range timestamps {
+        cw.Write(append([]string{t.Format(time.RFC3339)}, rows[t]...))
+    }
+    cw.Flush()
+    return cw.Error()
+}
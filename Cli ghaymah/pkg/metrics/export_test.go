@@ -0,0 +1,40 @@
+package metrics
+
+import (
+    "bytes"
+    "testing"
+    "time"
+    "ghaymah-cli/pkg/types"
+)
+
+func TestWriteCSVMergesTimestamps(t *testing.T) {
+    t0 := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+    cairo := time.FixedZone("EET", 3*60*60)
+    resp := &types.MetricsResponse{
+        Series: []types.MetricSeries{
+            {Name: types.MetricCPU, Unit: "%", Points: []types.MetricPoint{
+                {Timestamp: t0, Value: 1.5},
+                {Timestamp: t0.Add(time.Minute), Value: 2},
+                {Timestamp: t0.Add(2 * time.Minute), Value: 3},
+            }},
+            // Listed out of order and in another zone, with a timestamp missing from cpu
+            {Name: types.MetricMemory, Unit: "MB", Points: []types.MetricPoint{
+                {Timestamp: t0.Add(3 * time.Minute), Value: 256},
+                {Timestamp: t0.Add(time.Minute).In(cairo), Value: 512},
+            }},
+        },
+    }
+
+    var b bytes.Buffer
+    if err := WriteCSV(&b, resp); err != nil {
+        t.Fatal(err)
+    }
+    want := "timestamp," + types.MetricCPU + "," + types.MetricMemory + "\n" +
+        "2026-10-19T12:00:00Z,1.5,\n" +
+        "2026-10-19T12:01:00Z,2,512\n" +
+        "2026-10-19T12:02:00Z,3,\n" +
+        "2026-10-19T12:03:00Z,,256\n"
+    if b.String() != want {
+        t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+    }
+}
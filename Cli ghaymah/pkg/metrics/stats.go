@@ -0,0 +1,83 @@
+package metrics
+
+import (
+    "fmt"
+    "math"
+    "sort"
+    "ghaymah-cli/pkg/types"
+)
+
+// titles are the display names of the metrics
+var titles = map[string]string{
+    types.MetricCPU:      "CPU",
+    types.MetricMemory:   "Memory",
+    types.MetricStorage:  "Storage",
+    types.MetricRequests: "Request rate",
+    types.MetricLatency:  "Latency (p95)",
+    types.MetricErrors:   "Error rate",
+}
+
+// Stats summarizes the values of a series
+type Stats struct {
+    Count int     `json:"count"`
+    Min   float64 `json:"min"`
+    P50   float64 `json:"p50"`
+    P95   float64 `json:"p95"`
+    Max   float64 `json:"max"`
+    Last  float64 `json:"last"`
+}
+
+// Summarize computes the statistics of a series
+func Summarize(points []types.MetricPoint) Stats {
+    if len(points) == 0 {
+        return Stats{}
+    }
+
+    values := make([]float64, len(points))
+    for i, p := range points {
+        values[i] = p.Value
+    }
+    sort.Float64s(values)
+
+    return Stats{
+        Count: len(values),
+        Min:   values[0],
+        P50:   Percentile(values, 50),
+        P95:   Percentile(values, 95),
+        Max:   values[len(values)-1],
+        Last:  points[len(points)-1].Value,
+    }
+}
+
+// Percentile returns the p-th percentile of sorted values, interpolating
+// linearly between the closest ranks
+func Percentile(sorted []float64, p float64) float64 {
+    if len(sorted) == 0 {
+        return 0
+    }
+    rank := p / 100 * float64(len(sorted)-1)
+    lower := int(math.Floor(rank))
+    upper := int(math.Ceil(rank))
+    return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// Title returns the display name of a metric
+func Title(name string) string {
+    if title, ok := titles[name]; ok {
+        return title
+    }
+    return name
+}
+
+// FormatValue formats a value with its unit, e.g. 41.2% or 85 ms
+func FormatValue(value float64, unit string) string {
+    switch unit {
+    case "%":
+        return fmt.Sprintf("%.1f%%", value)
+    case "ms":
+        return fmt.Sprintf("%.0f ms", value)
+    case "":
+        return fmt.Sprintf("%.1f", value)
+    }
+    return fmt.Sprintf("%.1f %s", value, unit)
+}
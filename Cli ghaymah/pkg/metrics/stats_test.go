@@ -0,0 +1,45 @@
+package metrics
+
+import (
+    "testing"
+    "time"
+    "ghaymah-cli/pkg/types"
+)
+
+func TestPercentile(t *testing.T) {
+    tests := []struct {
+        values []float64
+        p      float64
+        want   float64
+    }{
+        {values: nil, p: 50, want: 0},
+        {values: []float64{7}, p: 95, want: 7},
+        {values: []float64{10, 20, 30, 40}, p: 0, want: 10},
+        {values: []float64{10, 20, 30, 40}, p: 100, want: 40},
+        // Between the closest ranks: rank 1.5, then rank 2.85
+        {values: []float64{10, 20, 30, 40}, p: 50, want: 25},
+        {values: []float64{10, 20, 30, 40}, p: 95, want: 38.5},
+        {values: []float64{10, 20, 30}, p: 50, want: 20},
+    }
+    for _, tt := range tests {
+        if got := Percentile(tt.values, tt.p); got != tt.want {
+            t.Errorf("Percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+        }
+    }
+}
+
+func TestSummarize(t *testing.T) {
+    start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+    var points []types.MetricPoint
+    for i, v := range []float64{30, 10, 40, 20} {
+        points = append(points, types.MetricPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: v})
+    }
+
+    want := Stats{Count: 4, Min: 10, P50: 25, P95: 38.5, Max: 40, Last: 20}
+    if got := Summarize(points); got != want {
+        t.Errorf("Summarize() = %+v, want %+v", got, want)
+    }
+    if got := Summarize(nil); got != (Stats{}) {
+        t.Errorf("Summarize(nil) = %+v, want zero stats", got)
+    }
+}
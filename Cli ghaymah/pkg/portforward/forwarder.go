@@ -0,0 +1,241 @@
+package portforward
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "net"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/gorilla/websocket"
+)
+
+// Mapping pairs a local port with a port on the application
+type Mapping struct {
+    LocalPort  int
+    RemotePort int
+}
+
+// ParseMapping parses "LOCAL:REMOTE" or a single "PORT" used for both sides
+func ParseMapping(spec string) (Mapping, error) {
+    parts := strings.Split(spec, ":")
+    if len(parts) > 2 {
+        return Mapping{}, fmt.Errorf("invalid port mapping %q, expected LOCAL:REMOTE", spec)
+    }
+
+    ports := make([]int, len(parts))
+    for i, part := range parts {
+        port, err := strconv.Atoi(part)
+        if err != nil || port < 1 || port > 65535 {
+            return Mapping{}, fmt.Errorf("invalid port %q in mapping %q", part, spec)
+        }
+        ports[i] = port
+    }
+
+    if len(ports) == 1 {
+        return Mapping{LocalPort: ports[0], RemotePort: ports[0]}, nil
+    }
+    return Mapping{LocalPort: ports[0], RemotePort: ports[1]}, nil
+}
+
+// DialFunc opens a new WebSocket connection to the port-forward endpoint
+type DialFunc func() (*websocket.Conn, error)
+
+// Forwarder listens on local ports and tunnels connections to the application
+type Forwarder struct {
+    Dial     DialFunc
+    Address  string
+    Mappings []Mapping
+    Logf     func(format string, args ...interface{})
+
+    // MaxBackoff caps the delay between reconnection attempts
+    MaxBackoff time.Duration
+
+    mu      sync.Mutex
+    current *session
+    ready   chan struct{}
+}
+
+// Run starts the listeners and keeps the tunnel connected until ctx is cancelled
+func (f *Forwarder) Run(ctx context.Context) error {
+    if f.Logf == nil {
+        f.Logf = func(string, ...interface{}) {}
+    }
+    if f.MaxBackoff == 0 {
+        f.MaxBackoff = 30 * time.Second
+    }
+    f.ready = make(chan struct{})
+
+    listeners := make([]net.Listener, 0, len(f.Mappings))
+    defer func() {
+        for _, l := range listeners {
+            l.Close()
+        }
+    }()
+
+    for _, m := range f.Mappings {
+        addr := net.JoinHostPort(f.Address, strconv.Itoa(m.LocalPort))
+        l, err := net.Listen("tcp", addr)
+        if err != nil {
+            return fmt.Errorf("failed to listen on %s: %w", addr, err)
+        }
+        listeners = append(listeners, l)
+        f.Logf("Forwarding from %s -> %d\n", addr, m.RemotePort)
+    }
+
+    for i, l := range listeners {
+        go f.acceptLoop(ctx, l, f.Mappings[i].RemotePort)
+    }
+
+    go func() {
+        <-ctx.Done()
+        for _, l := range listeners {
+            l.Close()
+        }
+    }()
+
+    f.connectLoop(ctx)
+    return nil
+}
+
+// connectLoop maintains the WebSocket session, reconnecting with backoff
+func (f *Forwarder) connectLoop(ctx context.Context) {
+    backoff := time.Second
+    for {
+        conn, err := f.Dial()
+        if err != nil {
+            f.Logf("Tunnel connection failed: %v (retrying in %s)\n", err, backoff)
+            if !sleepContext(ctx, backoff) {
+                return
+            }
+            backoff *= 2
+            if backoff > f.MaxBackoff {
+                backoff = f.MaxBackoff
+            }
+            continue
+        }
+
+        backoff = time.Second
+        s := newSession(conn)
+        f.setSession(s)
+        f.Logf("Tunnel connected\n")
+
+        select {
+        case <-ctx.Done():
+            s.close()
+            return
+        case <-s.done:
+            f.setSession(nil)
+            f.Logf("Tunnel disconnected, reconnecting...\n")
+        }
+    }
+}
+
+// setSession publishes the active session and wakes waiting connections
+func (f *Forwarder) setSession(s *session) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.current = s
+    if s != nil {
+        close(f.ready)
+    } else {
+        f.ready = make(chan struct{})
+    }
+}
+
+// waitSession returns the active session, waiting for a reconnect if needed
+func (f *Forwarder) waitSession(ctx context.Context) (*session, error) {
+    for {
+        f.mu.Lock()
+        s, ready := f.current, f.ready
+        f.mu.Unlock()
+        if s != nil {
+            return s, nil
+        }
+
+        select {
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        case <-ready:
+        case <-time.After(f.MaxBackoff):
+            return nil, errSessionClosed
+        }
+    }
+}
+
+// acceptLoop handles incoming local connections for one mapping
+func (f *Forwarder) acceptLoop(ctx context.Context, l net.Listener, remotePort int) {
+    for {
+        conn, err := l.Accept()
+        if err != nil {
+            return
+        }
+        go f.handle(ctx, conn, remotePort)
+    }
+}
+
+// handle tunnels a single local connection to the remote port
+func (f *Forwarder) handle(ctx context.Context, conn net.Conn, remotePort int) {
+    defer conn.Close()
+
+    s, err := f.waitSession(ctx)
+    if err != nil {
+        f.Logf("Dropping connection from %s: %v\n", conn.RemoteAddr(), err)
+        return
+    }
+
+    st, err := s.openStream(remotePort)
+    if err != nil {
+        f.Logf("Failed to open stream to port %d: %v\n", remotePort, err)
+        return
+    }
+    defer st.Close()
+
+    f.Logf("Handling connection for %d\n", remotePort)
+
+    // Each direction is half-closed on its own, so a client that shuts down
+    // its side after a request still receives the whole response
+    var wg sync.WaitGroup
+    wg.Add(2)
+    go func() {
+        defer wg.Done()
+        if _, err := io.Copy(st, conn); err != nil {
+            st.Close()
+            return
+        }
+        st.CloseWrite()
+    }()
+    go func() {
+        defer wg.Done()
+        if _, err := io.Copy(conn, st); err != nil {
+            conn.Close()
+            return
+        }
+        closeWrite(conn)
+    }()
+    wg.Wait()
+}
+
+// closeWrite half-closes a local connection, or closes it when it can't be half-closed
+func closeWrite(conn net.Conn) {
+    if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+        cw.CloseWrite()
+        return
+    }
+    conn.Close()
+}
+
+// sleepContext waits for d and reports false if ctx was cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+    t := time.NewTimer(d)
+    defer t.Stop()
+    select {
+    case <-ctx.Done():
+        return false
+    case <-t.C:
+        return true
+    }
+}
@@ -0,0 +1,359 @@
+package portforward
+
+import (
+    "bytes"
+    "context"
+    "encoding/binary"
+    "io"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/gorilla/websocket"
+)
+
+// Remote ports of the fake tunnel endpoint
+const (
+    echoPort    = 7  // writes data back, and half-closes when the client does
+    replyPort   = 80 // answers once the client half-closed its side
+    floodPort   = 9  // sends floodSize bytes as fast as the window allows, then half-closes
+    overrunPort = 10 // ignores the window and sends more than it at once
+)
+
+// floodSize is several windows, as a bulk transfer would send
+const floodSize = 3 * streamWindow
+
+// fakeRemote is a port-forward endpoint serving the ports above. Close frames
+// it receives are reported on closes, and the bytes sent on floodPort are
+// counted in flooded.
+type fakeRemote struct {
+    server  *httptest.Server
+    closes  chan *frame
+    flooded atomic.Int64
+}
+
+func newFakeRemote(t *testing.T) *fakeRemote {
+    r := &fakeRemote{closes: make(chan *frame, 16)}
+    upgrader := websocket.Upgrader{}
+    r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+        ws, err := upgrader.Upgrade(w, req, nil)
+        if err != nil {
+            return
+        }
+        defer ws.Close()
+
+        var writeMu sync.Mutex
+        send := func(f *frame) {
+            writeMu.Lock()
+            defer writeMu.Unlock()
+            ws.WriteMessage(websocket.BinaryMessage, f.encode())
+        }
+        ports := map[uint32]int{}
+        received := map[uint32][]byte{}
+        credits := map[uint32]chan int{}
+        for {
+            _, msg, err := ws.ReadMessage()
+            if err != nil {
+                return
+            }
+            f, err := decodeFrame(msg)
+            if err != nil {
+                continue
+            }
+            switch f.kind {
+            case frameOpen:
+                port := int(binary.BigEndian.Uint16(f.payload))
+                ports[f.streamID] = port
+                switch port {
+                case floodPort:
+                    credits[f.streamID] = make(chan int, 64)
+                    go r.flood(f.streamID, credits[f.streamID], send)
+                case overrunPort:
+                    send(&frame{kind: frameData, streamID: f.streamID, payload: make([]byte, streamWindow+1)})
+                }
+            case frameData:
+                switch ports[f.streamID] {
+                case echoPort:
+                    // Acked once the echo is read, so the echo stays within the window
+                    send(&frame{kind: frameData, streamID: f.streamID, payload: f.payload})
+                case replyPort:
+                    send(ackFrame(f.streamID, len(f.payload)))
+                    received[f.streamID] = append(received[f.streamID], f.payload...)
+                }
+            case frameAck:
+                n, err := f.ackSize()
+                switch {
+                case err != nil:
+                case ports[f.streamID] == echoPort:
+                    send(ackFrame(f.streamID, n))
+                case credits[f.streamID] != nil:
+                    credits[f.streamID] <- n
+                }
+            case frameFin:
+                if ports[f.streamID] == replyPort {
+                    reply := "received " + string(received[f.streamID])
+                    // Send the reply in small pieces, as a slow response would arrive
+                    for _, part := range strings.SplitAfter(reply, " ") {
+                        send(&frame{kind: frameData, streamID: f.streamID, payload: []byte(part)})
+                    }
+                }
+                send(&frame{kind: frameFin, streamID: f.streamID})
+            case frameClose:
+                r.closes <- f
+            }
+        }
+    }))
+    t.Cleanup(r.server.Close)
+    return r
+}
+
+// flood sends floodSize bytes on a stream, never more than the window ahead
+// of the acks received on credits
+func (r *fakeRemote) flood(id uint32, credits chan int, send func(*frame)) {
+    window := streamWindow
+    for sent := 0; sent < floodSize; {
+        for window == 0 {
+            window += <-credits
+        }
+        n := maxDataSize
+        if n > window {
+            n = window
+        }
+        send(&frame{kind: frameData, streamID: id, payload: bytes.Repeat([]byte("x"), n)})
+        window -= n
+        sent += n
+        r.flooded.Store(int64(sent))
+    }
+    send(&frame{kind: frameFin, streamID: id})
+}
+
+func (r *fakeRemote) dial() (*websocket.Conn, error) {
+    conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(r.server.URL, "http"), nil)
+    return conn, err
+}
+
+// freePort returns a local port nothing listens on
+func freePort(t *testing.T) int {
+    l, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer l.Close()
+    return l.Addr().(*net.TCPAddr).Port
+}
+
+// startForwarder runs a forwarder to the remote port and returns the local address
+func startForwarder(t *testing.T, remote *fakeRemote, remotePort int) string {
+    local := freePort(t)
+    ctx, cancel := context.WithCancel(context.Background())
+    t.Cleanup(cancel)
+
+    f := &Forwarder{
+        Dial:     remote.dial,
+        Address:  "127.0.0.1",
+        Mappings: []Mapping{{LocalPort: local, RemotePort: remotePort}},
+    }
+    go f.Run(ctx)
+
+    addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(local))
+    for i := 0; i < 100; i++ {
+        if conn, err := net.Dial("tcp", addr); err == nil {
+            conn.Close()
+            return addr
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    t.Fatalf("forwarder didn't listen on %s", addr)
+    return ""
+}
+
+func TestParseMapping(t *testing.T) {
+    tests := []struct {
+        spec    string
+        want    Mapping
+        wantErr bool
+    }{
+        {spec: "8080", want: Mapping{LocalPort: 8080, RemotePort: 8080}},
+        {spec: "5433:5432", want: Mapping{LocalPort: 5433, RemotePort: 5432}},
+        {spec: "65535:1", want: Mapping{LocalPort: 65535, RemotePort: 1}},
+        {spec: "0", wantErr: true},
+        {spec: "65536", wantErr: true},
+        {spec: "http", wantErr: true},
+        {spec: "8080:", wantErr: true},
+        {spec: "1:2:3", wantErr: true},
+    }
+    for _, tt := range tests {
+        got, err := ParseMapping(tt.spec)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("ParseMapping(%q) = %+v, want an error", tt.spec, got)
+            }
+            continue
+        }
+        if err != nil || got != tt.want {
+            t.Errorf("ParseMapping(%q) = %+v, %v, want %+v", tt.spec, got, err, tt.want)
+        }
+    }
+}
+
+func TestForwarderEcho(t *testing.T) {
+    addr := startForwarder(t, newFakeRemote(t), echoPort)
+
+    conn, err := net.Dial("tcp", addr)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer conn.Close()
+    conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+    if _, err := conn.Write([]byte("ping")); err != nil {
+        t.Fatal(err)
+    }
+    buf := make([]byte, 4)
+    if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+        t.Fatalf("read %q, %v, want ping", buf, err)
+    }
+}
+
+func TestForwarderHalfClose(t *testing.T) {
+    addr := startForwarder(t, newFakeRemote(t), replyPort)
+
+    conn, err := net.Dial("tcp", addr)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer conn.Close()
+    conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+    if _, err := conn.Write([]byte("GET / HTTP/1.0")); err != nil {
+        t.Fatal(err)
+    }
+    // The request is complete: the response must still arrive in full
+    if err := conn.(*net.TCPConn).CloseWrite(); err != nil {
+        t.Fatal(err)
+    }
+    response, err := io.ReadAll(conn)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := "received GET / HTTP/1.0"; string(response) != want {
+        t.Errorf("response = %q, want %q", response, want)
+    }
+}
+
+func TestSlowStreamDoesNotBlockTunnel(t *testing.T) {
+    remote := newFakeRemote(t)
+    conn, err := remote.dial()
+    if err != nil {
+        t.Fatal(err)
+    }
+    s := newSession(conn)
+    defer s.close()
+
+    // Nobody reads the flooded stream yet, which must not stall the echo stream
+    flooded, err := s.openStream(floodPort)
+    if err != nil {
+        t.Fatal(err)
+    }
+    echo, err := s.openStream(echoPort)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    result := make(chan string, 1)
+    go func() {
+        echo.Write([]byte("ping"))
+        buf := make([]byte, 4)
+        io.ReadFull(echo, buf)
+        result <- string(buf)
+    }()
+    select {
+    case got := <-result:
+        if got != "ping" {
+            t.Fatalf("echo stream read %q, want ping", got)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("echo stream blocked by the flooded stream")
+    }
+
+    // The remote side waits for acks once the window is used
+    time.Sleep(50 * time.Millisecond)
+    if sent := remote.flooded.Load(); sent != streamWindow {
+        t.Errorf("remote sent %d bytes to an unread stream, want the window of %d", sent, streamWindow)
+    }
+
+    // Reading resumes the transfer, without losing data
+    data, err := io.ReadAll(flooded)
+    if err != nil {
+        t.Fatalf("reading the flooded stream: %v", err)
+    }
+    if len(data) != floodSize {
+        t.Errorf("read %d bytes from the flooded stream, want %d", len(data), floodSize)
+    }
+}
+
+func TestStreamOverrunIsReset(t *testing.T) {
+    remote := newFakeRemote(t)
+    conn, err := remote.dial()
+    if err != nil {
+        t.Fatal(err)
+    }
+    s := newSession(conn)
+    defer s.close()
+
+    st, err := s.openStream(overrunPort)
+    if err != nil {
+        t.Fatal(err)
+    }
+    select {
+    case f := <-remote.closes:
+        if f.streamID != st.id || string(f.payload) != errStreamOverrun.Error() {
+            t.Errorf("remote got close frame %+v, want a reset of stream %d", f, st.id)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("overrun stream wasn't reset")
+    }
+    if _, err := io.ReadAll(st); err != errStreamOverrun {
+        t.Errorf("reading the overrun stream returned %v, want %v", err, errStreamOverrun)
+    }
+}
+
+func TestWriteWaitsForWindow(t *testing.T) {
+    remote := newFakeRemote(t)
+    conn, err := remote.dial()
+    if err != nil {
+        t.Fatal(err)
+    }
+    s := newSession(conn)
+    defer s.close()
+
+    // The echo port acks data as its echo is read, so a write larger than the
+    // window completes while reading, and is echoed back in full
+    st, err := s.openStream(echoPort)
+    if err != nil {
+        t.Fatal(err)
+    }
+    sent := bytes.Repeat([]byte("0123456789"), floodSize/10)
+    errs := make(chan error, 1)
+    go func() {
+        _, err := st.Write(sent)
+        st.CloseWrite()
+        errs <- err
+    }()
+    received, err := io.ReadAll(st)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := <-errs; err != nil {
+        t.Fatal(err)
+    }
+    if !bytes.Equal(received, sent) {
+        t.Errorf("echoed %d bytes, want the %d sent", len(received), len(sent))
+    }
+}
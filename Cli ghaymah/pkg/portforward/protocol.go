@@ -0,0 +1,77 @@
+package portforward
+
+import (
+    "encoding/binary"
+    "fmt"
+)
+
+// Frame types multiplexed over a single WebSocket connection. A fin frame
+// half-closes a stream: the sender has no more data, but still reads. An ack
+// frame returns window to the sender once data has been read.
+const (
+    frameOpen  byte = 1
+    frameData  byte = 2
+    frameClose byte = 3
+    frameFin   byte = 4
+    frameAck   byte = 5
+)
+
+// Flow control: each side of a stream may have at most streamWindow bytes
+// sent but not yet acknowledged, in data frames of at most maxDataSize bytes
+const (
+    streamWindow = 256 * 1024
+    maxDataSize  = 32 * 1024
+)
+
+// frameHeaderSize is the frame type byte followed by a 4 byte stream ID
+const frameHeaderSize = 5
+
+// frame is a single message exchanged with the port-forward endpoint
+type frame struct {
+    kind     byte
+    streamID uint32
+    payload  []byte
+}
+
+// encode serializes the frame into a binary WebSocket message
+func (f *frame) encode() []byte {
+    buf := make([]byte, frameHeaderSize+len(f.payload))
+    buf[0] = f.kind
+    binary.BigEndian.PutUint32(buf[1:frameHeaderSize], f.streamID)
+    copy(buf[frameHeaderSize:], f.payload)
+    return buf
+}
+
+// decodeFrame parses a binary WebSocket message into a frame
+func decodeFrame(msg []byte) (*frame, error) {
+    if len(msg) < frameHeaderSize {
+        return nil, fmt.Errorf("frame too short: %d bytes", len(msg))
+    }
+    return &frame{
+        kind:     msg[0],
+        streamID: binary.BigEndian.Uint32(msg[1:frameHeaderSize]),
+        payload:  msg[frameHeaderSize:],
+    }, nil
+}
+
+// openFrame requests a new stream to the given remote port
+func openFrame(streamID uint32, port int) *frame {
+    payload := make([]byte, 2)
+    binary.BigEndian.PutUint16(payload, uint16(port))
+    return &frame{kind: frameOpen, streamID: streamID, payload: payload}
+}
+
+// ackFrame acknowledges n bytes of a stream read by the local side
+func ackFrame(streamID uint32, n int) *frame {
+    payload := make([]byte, 4)
+    binary.BigEndian.PutUint32(payload, uint32(n))
+    return &frame{kind: frameAck, streamID: streamID, payload: payload}
+}
+
+// ackSize returns the number of bytes acknowledged by an ack frame
+func (f *frame) ackSize() (int, error) {
+    if len(f.payload) != 4 {
+        return 0, fmt.Errorf("invalid ack payload: %d bytes", len(f.payload))
+    }
+    return int(binary.BigEndian.Uint32(f.payload)), nil
+}
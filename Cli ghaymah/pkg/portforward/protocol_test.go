@@ -0,0 +1,50 @@
+package portforward
+
+import (
+    "bytes"
+    "encoding/binary"
+    "testing"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+    frames := []*frame{
+        {kind: frameData, streamID: 7, payload: []byte("hello")},
+        {kind: frameFin, streamID: 1 << 31},
+        {kind: frameClose, streamID: 3, payload: []byte("connection refused")},
+    }
+    for _, want := range frames {
+        got, err := decodeFrame(want.encode())
+        if err != nil {
+            t.Fatalf("decodeFrame(%v): %v", want, err)
+        }
+        if got.kind != want.kind || got.streamID != want.streamID || !bytes.Equal(got.payload, want.payload) {
+            t.Errorf("round trip of %+v gave %+v", want, got)
+        }
+    }
+}
+
+func TestDecodeFrameTooShort(t *testing.T) {
+    if _, err := decodeFrame([]byte{frameData, 0, 0, 1}); err == nil {
+        t.Error("expected an error for a frame shorter than its header")
+    }
+}
+
+func TestOpenFrame(t *testing.T) {
+    f := openFrame(5, 5432)
+    if f.kind != frameOpen || f.streamID != 5 {
+        t.Fatalf("unexpected open frame %+v", f)
+    }
+    if port := binary.BigEndian.Uint16(f.payload); port != 5432 {
+        t.Errorf("open frame port = %d, want 5432", port)
+    }
+}
+
+func TestAckFrame(t *testing.T) {
+    n, err := ackFrame(5, streamWindow).ackSize()
+    if err != nil || n != streamWindow {
+        t.Errorf("ackSize() = %d, %v, want %d", n, err, streamWindow)
+    }
+    if _, err := (&frame{kind: frameAck, payload: []byte{1}}).ackSize(); err == nil {
+        t.Error("expected an error for a truncated ack")
+    }
+}
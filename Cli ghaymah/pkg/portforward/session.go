@@ -0,0 +1,336 @@
+package portforward
+
+import (
+    "errors"
+    "io"
+    "sync"
+
+    "github.com/gorilla/websocket"
+)
+
+// errSessionClosed is returned when using a session whose connection has dropped
+var errSessionClosed = errors.New("port-forward session closed")
+
+// errStreamOverrun resets a stream whose remote side sent more than its window
+var errStreamOverrun = errors.New("receive window exceeded")
+
+// session multiplexes many TCP streams over one WebSocket connection
+type session struct {
+    conn    *websocket.Conn
+    writeMu sync.Mutex
+
+    mu      sync.Mutex
+    streams map[uint32]*stream
+    nextID  uint32
+    closed  bool
+
+    done chan struct{}
+}
+
+// newSession starts reading frames from the connection
+func newSession(conn *websocket.Conn) *session {
+    s := &session{
+        conn:    conn,
+        streams: make(map[uint32]*stream),
+        done:    make(chan struct{}),
+    }
+    go s.readLoop()
+    return s
+}
+
+// openStream asks the remote side to connect to the given port
+func (s *session) openStream(port int) (*stream, error) {
+    s.mu.Lock()
+    if s.closed {
+        s.mu.Unlock()
+        return nil, errSessionClosed
+    }
+    s.nextID++
+    st := newStream(s, s.nextID)
+    s.streams[st.id] = st
+    s.mu.Unlock()
+
+    if err := s.writeFrame(openFrame(st.id, port)); err != nil {
+        s.removeStream(st.id)
+        return nil, err
+    }
+    return st, nil
+}
+
+// writeFrame sends a single frame, serializing concurrent writers
+func (s *session) writeFrame(f *frame) error {
+    s.writeMu.Lock()
+    defer s.writeMu.Unlock()
+    if err := s.conn.WriteMessage(websocket.BinaryMessage, f.encode()); err != nil {
+        return errSessionClosed
+    }
+    return nil
+}
+
+// readLoop dispatches incoming frames to their streams until the connection drops.
+// It never blocks on a stream: the remote side stops sending to a slow stream
+// once its window is used, and a stream it overruns anyway is reset.
+func (s *session) readLoop() {
+    defer s.close()
+    for {
+        _, msg, err := s.conn.ReadMessage()
+        if err != nil {
+            return
+        }
+        f, err := decodeFrame(msg)
+        if err != nil {
+            continue
+        }
+
+        s.mu.Lock()
+        st := s.streams[f.streamID]
+        s.mu.Unlock()
+        if st == nil {
+            continue
+        }
+
+        switch f.kind {
+        case frameData:
+            if !st.deliver(f.payload) {
+                s.removeStream(st.id)
+                st.reset(errStreamOverrun)
+                s.writeFrame(&frame{kind: frameClose, streamID: st.id, payload: []byte(errStreamOverrun.Error())})
+            }
+        case frameAck:
+            if n, err := f.ackSize(); err == nil {
+                st.grant(n)
+            }
+        case frameFin:
+            st.finish(io.EOF)
+        case frameClose:
+            s.removeStream(st.id)
+            if len(f.payload) > 0 {
+                st.reset(errors.New(string(f.payload)))
+            } else {
+                st.reset(io.EOF)
+            }
+        }
+    }
+}
+
+// removeStream forgets a stream so further frames for it are dropped
+func (s *session) removeStream(id uint32) {
+    s.mu.Lock()
+    delete(s.streams, id)
+    s.mu.Unlock()
+}
+
+// close tears down the connection and every open stream
+func (s *session) close() {
+    s.mu.Lock()
+    if s.closed {
+        s.mu.Unlock()
+        return
+    }
+    s.closed = true
+    streams := s.streams
+    s.streams = make(map[uint32]*stream)
+    s.mu.Unlock()
+
+    for _, st := range streams {
+        st.reset(errSessionClosed)
+    }
+    s.conn.Close()
+    close(s.done)
+}
+
+// stream is one forwarded TCP connection inside a session. Incoming data is
+// queued until read; eof is closed once the remote side sends no more data,
+// and gone once it stopped reading too.
+type stream struct {
+    session *session
+    id      uint32
+
+    mu       sync.Mutex
+    queue    [][]byte
+    buffered int
+    unacked  int
+    window   int
+
+    readable chan struct{}
+    writable chan struct{}
+    eof      chan struct{}
+    err      error
+    gone     chan struct{}
+    closed   chan struct{}
+
+    finOnce   sync.Once
+    goneOnce  sync.Once
+    writeOnce sync.Once
+    once      sync.Once
+}
+
+// newStream creates a stream with an empty receive queue and a full send window
+func newStream(s *session, id uint32) *stream {
+    return &stream{
+        session:  s,
+        id:       id,
+        window:   streamWindow,
+        readable: make(chan struct{}, 1),
+        writable: make(chan struct{}, 1),
+        eof:      make(chan struct{}),
+        gone:     make(chan struct{}),
+        closed:   make(chan struct{}),
+    }
+}
+
+// notify wakes up a goroutine waiting on ch, if any
+func notify(ch chan struct{}) {
+    select {
+    case ch <- struct{}{}:
+    default:
+    }
+}
+
+// deliver queues data received from the remote port, reporting false when
+// the remote side sent more than the window
+func (st *stream) deliver(p []byte) bool {
+    st.mu.Lock()
+    if st.buffered+len(p) > streamWindow {
+        st.mu.Unlock()
+        return false
+    }
+    st.queue = append(st.queue, p)
+    st.buffered += len(p)
+    st.mu.Unlock()
+    notify(st.readable)
+    return true
+}
+
+// grant returns window acknowledged by the remote side
+func (st *stream) grant(n int) {
+    st.mu.Lock()
+    st.window += n
+    st.mu.Unlock()
+    notify(st.writable)
+}
+
+// finish marks the end of the incoming data; queued data is still read first
+func (st *stream) finish(err error) {
+    st.finOnce.Do(func() {
+        st.err = err
+        close(st.eof)
+    })
+}
+
+// reset ends the stream in both directions
+func (st *stream) reset(err error) {
+    st.finish(err)
+    st.goneOnce.Do(func() {
+        close(st.gone)
+    })
+}
+
+// Read returns data received from the remote port. Read data is acknowledged
+// in batches of a quarter of the window, so the remote side keeps sending.
+func (st *stream) Read(p []byte) (int, error) {
+    for {
+        st.mu.Lock()
+        if len(st.queue) > 0 {
+            n := copy(p, st.queue[0])
+            st.queue[0] = st.queue[0][n:]
+            if len(st.queue[0]) == 0 {
+                st.queue = st.queue[1:]
+            }
+            st.buffered -= n
+            st.unacked += n
+            ack := 0
+            if st.unacked >= streamWindow/4 {
+                ack, st.unacked = st.unacked, 0
+            }
+            st.mu.Unlock()
+            if ack > 0 {
+                st.session.writeFrame(ackFrame(st.id, ack))
+            }
+            return n, nil
+        }
+        st.mu.Unlock()
+
+        select {
+        case <-st.readable:
+        case <-st.closed:
+            return 0, io.ErrClosedPipe
+        case <-st.eof:
+            st.mu.Lock()
+            empty := len(st.queue) == 0
+            st.mu.Unlock()
+            if empty {
+                return 0, st.err
+            }
+        }
+    }
+}
+
+// Write sends data to the remote port, waiting while the remote side has
+// as much unread data as its window
+func (st *stream) Write(p []byte) (int, error) {
+    written := 0
+    for len(p) > 0 {
+        n, err := st.reserve(len(p))
+        if err != nil {
+            return written, err
+        }
+        payload := make([]byte, n)
+        copy(payload, p)
+        if err := st.session.writeFrame(&frame{kind: frameData, streamID: st.id, payload: payload}); err != nil {
+            return written, err
+        }
+        written += n
+        p = p[n:]
+    }
+    return written, nil
+}
+
+// reserve takes up to size bytes of the send window, waiting for acks while
+// it is used up
+func (st *stream) reserve(size int) (int, error) {
+    for {
+        st.mu.Lock()
+        if st.window > 0 {
+            n := size
+            if n > st.window {
+                n = st.window
+            }
+            if n > maxDataSize {
+                n = maxDataSize
+            }
+            st.window -= n
+            st.mu.Unlock()
+            return n, nil
+        }
+        st.mu.Unlock()
+
+        select {
+        case <-st.writable:
+        case <-st.closed:
+            return 0, io.ErrClosedPipe
+        case <-st.gone:
+            return 0, st.err
+        }
+    }
+}
+
+// CloseWrite tells the remote side no more data will be written, while the
+// stream keeps receiving
+func (st *stream) CloseWrite() error {
+    var err error
+    st.writeOnce.Do(func() {
+        err = st.session.writeFrame(&frame{kind: frameFin, streamID: st.id})
+    })
+    return err
+}
+
+// Close tells the remote side the stream is finished
+func (st *stream) Close() error {
+    st.once.Do(func() {
+        st.session.removeStream(st.id)
+        st.session.writeFrame(&frame{kind: frameClose, streamID: st.id})
+        close(st.closed)
+    })
+    return nil
+}
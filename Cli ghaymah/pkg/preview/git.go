@@ -0,0 +1,80 @@
+package preview
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "os"
+    "os/exec"
+    "strings"
+)
+
+// ciBranchVars are environment variables CI systems set to the branch being
+// built, used when the checkout is a detached HEAD
+var ciBranchVars = []string{
+    "GITHUB_HEAD_REF",     // GitHub Actions, pull requests
+    "GITHUB_REF_NAME",     // GitHub Actions, pushes
+    "CI_COMMIT_REF_NAME",  // GitLab CI
+    "BITBUCKET_BRANCH",    // Bitbucket Pipelines
+    "CIRCLE_BRANCH",       // CircleCI
+    "BRANCH_NAME",         // Jenkins
+}
+
+// CurrentBranch returns the branch checked out in the git repository
+// containing dir, falling back to the branch set by CI when HEAD is detached
+func CurrentBranch(dir string) (string, error) {
+    branch, err := git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+    if err == nil && branch != "HEAD" {
+        return branch, nil
+    }
+
+    for _, name := range ciBranchVars {
+        if value := os.Getenv(name); value != "" {
+            return value, nil
+        }
+    }
+
+    if err != nil {
+        return "", err
+    }
+    return "", errors.New("HEAD is detached. Use --branch")
+}
+
+// CurrentCommit returns the abbreviated commit checked out in dir
+func CurrentCommit(dir string) (string, error) {
+    return git(dir, "rev-parse", "--short=12", "HEAD")
+}
+
+// RemoteBranches returns the branches that exist on a remote
+func RemoteBranches(dir, remote string) (map[string]bool, error) {
+    out, err := git(dir, "ls-remote", "--heads", remote)
+    if err != nil {
+        return nil, err
+    }
+
+    branches := map[string]bool{}
+    for _, line := range strings.Split(out, "\n") {
+        fields := strings.Fields(line)
+        if len(fields) == 2 {
+            branches[strings.TrimPrefix(fields[1], "refs/heads/")] = true
+        }
+    }
+    return branches, nil
+}
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+    cmd := exec.Command("git", args...)
+    cmd.Dir = dir
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
+
+    out, err := cmd.Output()
+    if err != nil {
+        if msg := strings.TrimSpace(stderr.String()); msg != "" {
+            return "", fmt.Errorf("git %s: %s", args[0], msg)
+        }
+        return "", fmt.Errorf("git %s: %w", args[0], err)
+    }
+    return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,82 @@
+package preview
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "strings"
+    "time"
+    "ghaymah-cli/pkg/scaffold"
+    "ghaymah-cli/pkg/types"
+)
+
+// Labels attached to preview applications
+const (
+    LabelPreview = "ghaymah.cloud/preview"
+    LabelApp     = "ghaymah.cloud/preview-of"
+    LabelBranch  = "ghaymah.cloud/branch"
+    LabelCommit  = "ghaymah.cloud/commit"
+)
+
+// maxNameLength is the longest application name accepted by the platform
+const maxNameLength = 63
+
+// AppName derives the name of the preview application of a branch from the
+// name of the application, e.g. shop-feature-login for branch feature/login.
+// Names that would be too long are shortened and made unique with a hash of
+// the branch.
+func AppName(app, branch string) string {
+    slug := scaffold.SanitizeName(branch)
+    name := app + "-" + slug
+    if slug != "" && len(name) <= maxNameLength {
+        return name
+    }
+
+    sum := sha256.Sum256([]byte(branch))
+    hash := hex.EncodeToString(sum[:3])
+    name = strings.TrimRight(name[:min(len(name), maxNameLength-len(hash)-1)], "-")
+    return name + "-" + hash
+}
+
+// Labels returns the labels identifying the preview of app for a branch
+func Labels(app, branch, commit string) map[string]string {
+    labels := map[string]string{
+        LabelPreview: "true",
+        LabelApp:     app,
+        LabelBranch:  branch,
+    }
+    if commit != "" {
+        labels[LabelCommit] = commit
+    }
+    return labels
+}
+
+// IsPreviewOf reports whether app is labeled as the preview of base for
+// branch. Branches such as feature/login and feature-login share a preview
+// name, so the name alone doesn't identify a preview.
+func IsPreviewOf(app types.AppSummary, base, branch string) bool {
+    return app.Labels[LabelPreview] == "true" && app.Labels[LabelApp] == base && app.Labels[LabelBranch] == branch
+}
+
+// Selector returns the labels selecting the previews of app, or of all
+// applications when app is empty
+func Selector(app string) map[string]string {
+    selector := map[string]string{LabelPreview: "true"}
+    if app != "" {
+        selector[LabelApp] = app
+    }
+    return selector
+}
+
+// Stale returns why a preview should be garbage-collected, or "" if it should
+// be kept. Previews deployed more than ttl ago are stale, as are previews of
+// branches missing from branches. A nil branches skips the branch check.
+func Stale(app types.AppSummary, ttl time.Duration, branches map[string]bool, now time.Time) string {
+    if ttl > 0 && app.DeployedAt != nil && now.Sub(*app.DeployedAt) > ttl {
+        return fmt.Sprintf("older than %s", ttl)
+    }
+    if branch := app.Labels[LabelBranch]; branches != nil && branch != "" && !branches[branch] {
+        return fmt.Sprintf("branch %s no longer exists", branch)
+    }
+    return ""
+}
@@ -0,0 +1,92 @@
+package preview
+
+import (
+    "strings"
+    "testing"
+    "time"
+    "ghaymah-cli/pkg/types"
+)
+
+func TestAppName(t *testing.T) {
+    long := "feature/" + strings.Repeat("x", 80)
+    tests := []struct {
+        app, branch string
+        want        string
+    }{
+        {app: "shop", branch: "main", want: "shop-main"},
+        {app: "shop", branch: "feature/login", want: "shop-feature-login"},
+        {app: "shop", branch: "Fix_Bug#12", want: "shop-fix-bug-12"},
+        // Too long: truncated, then made unique with a hash of the branch
+        {app: "shop", branch: long, want: "shop-feature-" + strings.Repeat("x", 43) + "-213bb9"},
+        // Nothing left of the branch once sanitized
+        {app: "shop", branch: "///", want: "shop-732c4e"},
+    }
+    for _, tt := range tests {
+        got := AppName(tt.app, tt.branch)
+        if got != tt.want {
+            t.Errorf("AppName(%q, %q) = %q, want %q", tt.app, tt.branch, got, tt.want)
+        }
+        if len(got) > maxNameLength {
+            t.Errorf("AppName(%q, %q) is %d characters long, over %d", tt.app, tt.branch, len(got), maxNameLength)
+        }
+    }
+}
+
+func TestAppNameTruncatedBranchesDiffer(t *testing.T) {
+    prefix := "feature/" + strings.Repeat("x", 80)
+    a, b := AppName("shop", prefix+"-a"), AppName("shop", prefix+"-b")
+    if a == b {
+        t.Errorf("branches differing after the truncation share the name %s", a)
+    }
+}
+
+func TestAppNameCollision(t *testing.T) {
+    // Branches sanitized alike share a name: only the labels tell them apart
+    name := AppName("shop", "feature/login")
+    if other := AppName("shop", "feature-login"); other != name {
+        t.Fatalf("expected feature/login and feature-login to share a name, got %s and %s", name, other)
+    }
+
+    app := types.AppSummary{Name: name, Labels: Labels("shop", "feature/login", "abc123")}
+    if !IsPreviewOf(app, "shop", "feature/login") {
+        t.Error("the preview isn't recognized as the one of its branch")
+    }
+    if IsPreviewOf(app, "shop", "feature-login") {
+        t.Error("the preview of feature/login is taken for the one of feature-login")
+    }
+    if IsPreviewOf(app, "blog", "feature/login") {
+        t.Error("the preview of shop is taken for one of blog")
+    }
+    if IsPreviewOf(types.AppSummary{Name: name}, "shop", "feature/login") {
+        t.Error("an application without labels is taken for a preview")
+    }
+}
+
+func TestStale(t *testing.T) {
+    now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+    deployed := func(ago time.Duration) *time.Time {
+        at := now.Add(-ago)
+        return &at
+    }
+    branches := map[string]bool{"main": true, "feature/login": true}
+    tests := []struct {
+        name     string
+        app      types.AppSummary
+        ttl      time.Duration
+        branches map[string]bool
+        want     string
+    }{
+        {name: "recent", app: types.AppSummary{DeployedAt: deployed(time.Hour), Labels: Labels("shop", "main", "")}, ttl: 24 * time.Hour, branches: branches},
+        {name: "expired", app: types.AppSummary{DeployedAt: deployed(48 * time.Hour), Labels: Labels("shop", "main", "")}, ttl: 24 * time.Hour, branches: branches, want: "older than 24h0m0s"},
+        {name: "ttl disabled", app: types.AppSummary{DeployedAt: deployed(48 * time.Hour), Labels: Labels("shop", "main", "")}, branches: branches},
+        {name: "deploy time unknown", app: types.AppSummary{Labels: Labels("shop", "main", "")}, ttl: time.Hour, branches: branches},
+        {name: "branch deleted", app: types.AppSummary{DeployedAt: deployed(time.Hour), Labels: Labels("shop", "feature/old", "")}, ttl: 24 * time.Hour, branches: branches, want: "branch feature/old no longer exists"},
+        {name: "branches unknown", app: types.AppSummary{DeployedAt: deployed(time.Hour), Labels: Labels("shop", "feature/old", "")}, ttl: 24 * time.Hour},
+        {name: "no branch label", app: types.AppSummary{DeployedAt: deployed(time.Hour)}, ttl: 24 * time.Hour, branches: branches},
+    }
+    for _, tt := range tests {
+        if got := Stale(tt.app, tt.ttl, tt.branches, now); got != tt.want {
+            t.Errorf("%s: Stale() = %q, want %q", tt.name, got, tt.want)
+        }
+    }
+}
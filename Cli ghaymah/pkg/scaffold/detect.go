@@ -0,0 +1,143 @@
+package scaffold
+
+import (
+    "bufio"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// Project types recognized by Detect
+const (
+    ProjectNode   = "node"
+    ProjectGo     = "go"
+    ProjectDocker = "docker"
+    ProjectOther  = "unknown"
+)
+
+// DefaultRegion is proposed when nothing else is known
+const DefaultRegion = "us-east-1"
+
+// Project is what Detect learned about a directory
+type Project struct {
+    Type           string
+    Name           string
+    DockerfilePath string
+    Port           int
+    PortSource     string
+}
+
+var (
+    exposeRe    = regexp.MustCompile(`(?im)^\s*EXPOSE\s+(\d+)`)
+    portUsageRe = regexp.MustCompile(`process\.env\.PORT|os\.Getenv\("PORT"\)|os\.LookupEnv\("PORT"\)`)
+    portValueRe = regexp.MustCompile(`(?:PORT\D{0,40}?|listen\(\s*|":)(\d{2,5})\b`)
+    invalidName = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// sourceExtensions are scanned for PORT usage
+var sourceExtensions = map[string]bool{".js": true, ".mjs": true, ".ts": true, ".go": true}
+
+// Detect inspects dir for a Dockerfile, package.json, go.mod and port usage
+func Detect(dir string) (*Project, error) {
+    abs, err := filepath.Abs(dir)
+    if err != nil {
+        return nil, err
+    }
+
+    p := &Project{Type: ProjectOther, Name: SanitizeName(filepath.Base(abs))}
+
+    if data, err := os.ReadFile(filepath.Join(dir, "Dockerfile")); err == nil {
+        p.Type = ProjectDocker
+        p.DockerfilePath = "./Dockerfile"
+        if m := exposeRe.FindSubmatch(data); m != nil {
+            p.Port, _ = strconv.Atoi(string(m[1]))
+            p.PortSource = "Dockerfile EXPOSE"
+        }
+    }
+
+    if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+        p.Type = ProjectNode
+        var pkg struct {
+            Name string `json:"name"`
+        }
+        if json.Unmarshal(data, &pkg) == nil && pkg.Name != "" {
+            // Drop the npm scope of names like @org/app
+            name := pkg.Name[strings.LastIndex(pkg.Name, "/")+1:]
+            p.Name = SanitizeName(name)
+        }
+    } else if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+        p.Type = ProjectGo
+        if module := goModule(data); module != "" {
+            p.Name = SanitizeName(module[strings.LastIndex(module, "/")+1:])
+        }
+    }
+
+    if p.Port == 0 {
+        p.Port, p.PortSource = findPortUsage(dir)
+    }
+
+    return p, nil
+}
+
+// goModule returns the module path of a go.mod file
+func goModule(data []byte) string {
+    scanner := bufio.NewScanner(strings.NewReader(string(data)))
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if strings.HasPrefix(line, "module ") {
+            return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+        }
+    }
+    return ""
+}
+
+// findPortUsage looks for PORT environment usage in top-level source files and
+// returns the default port found next to it, if any
+func findPortUsage(dir string) (int, string) {
+    entries, err := os.ReadDir(dir)
+    if err != nil {
+        return 0, ""
+    }
+
+    for _, entry := range entries {
+        if entry.IsDir() || !sourceExtensions[filepath.Ext(entry.Name())] {
+            continue
+        }
+        data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+        if err != nil || !portUsageRe.Match(data) {
+            continue
+        }
+        if m := portValueRe.FindSubmatch(data); m != nil {
+            if port, err := strconv.Atoi(string(m[1])); err == nil && port > 0 && port < 65536 {
+                return port, entry.Name()
+            }
+        }
+    }
+    return 0, ""
+}
+
+// SanitizeName turns a string into a valid application name
+// (lowercase letters, digits and dashes, at most 63 characters)
+func SanitizeName(name string) string {
+    name = invalidName.ReplaceAllString(strings.ToLower(name), "-")
+    name = strings.Trim(name, "-")
+    if len(name) > 63 {
+        name = strings.TrimRight(name[:63], "-")
+    }
+    return name
+}
+
+// DefaultResources proposes CPU and memory for a project type
+func DefaultResources(projectType string) (cpu, memory, storage string) {
+    switch projectType {
+    case ProjectGo:
+        return "0.25", "128M", "1G"
+    case ProjectNode:
+        return "0.5", "256M", "1G"
+    default:
+        return "1", "512M", "1G"
+    }
+}
@@ -0,0 +1,71 @@
+package scaffold
+
+import (
+    "bytes"
+    "strconv"
+    "text/template"
+)
+
+// Options are the values written into a new configuration file
+type Options struct {
+    Version        int
+    AppName        string
+    DockerfilePath string
+    Image          string
+    Region         string
+    Port           int
+    CPU            string
+    Memory         string
+    Storage        string
+}
+
+var configTemplate = template.Must(template.New("config").Funcs(template.FuncMap{
+    "quote": strconv.Quote,
+}).Parse(`# Ghaymah CLI Configuration
+# Deploy with: ghaymah deploy
+
+# Configuration format version
+version: {{ .Version }}
+
+# Application name (lowercase letters, digits and dashes)
+appName: {{ quote .AppName }}
+{{ if .Image }}
+# Docker image to deploy
+image: {{ quote .Image }}
+{{ else }}
+# Path to Dockerfile
+dockerfilePath: {{ quote .DockerfilePath }}
+{{ end }}
+# Deployment region
+region: {{ quote .Region }}
+{{ if .Port }}
+# Environment variables
+envVars:
+  PORT: "{{ .Port }}"
+
+# Health check on the application port
+healthCheck:
+  type: "tcp"
+  port: {{ .Port }}
+  interval: "10s"
+  timeout: "2s"
+{{ else }}
+# Environment variables
+# envVars:
+#   PORT: "8080"
+{{ end }}
+# Resource requirements
+resources:
+  cpu: {{ quote .CPU }}  # Number of CPU cores
+  memory: {{ quote .Memory }}  # Memory limit
+  storage: {{ quote .Storage }}  # Storage limit
+`))
+
+// Render produces a commented YAML configuration file
+func Render(opts Options) ([]byte, error) {
+    var buf bytes.Buffer
+    if err := configTemplate.Execute(&buf, opts); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
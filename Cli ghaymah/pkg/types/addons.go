@@ -0,0 +1,95 @@
+package types
+
+import (
+    "time"
+)
+
+// Add-on types
+const (
+    AddonPostgres = "postgres"
+    AddonRedis    = "redis"
+    AddonMongo    = "mongo"
+)
+
+// AddonTypes lists the managed databases that can be created as add-ons
+var AddonTypes = []string{AddonPostgres, AddonRedis, AddonMongo}
+
+// Add-on states
+const (
+    AddonProvisioning = "provisioning"
+    AddonAvailable    = "available"
+    AddonRestoring    = "restoring"
+)
+
+// AddonPlan is a size of managed database an add-on can be created with
+type AddonPlan struct {
+    Name             string  `json:"name"`
+    Type             string  `json:"type"`
+    Memory           string  `json:"memory"`
+    Storage          string  `json:"storage"`
+    MaxConnections   int     `json:"maxConnections"`
+    BackupDays       int     `json:"backupDays"`
+    HighAvailability bool    `json:"highAvailability,omitempty"`
+    MonthlyPrice     float64 `json:"monthlyPrice"`
+}
+
+// AddonPlansResponse represents the response from an add-on plans request
+type AddonPlansResponse struct {
+    Plans []AddonPlan `json:"plans"`
+}
+
+// Addon is a managed database attached to an application, whose connection
+// URL is injected into the application's environment on deploy. URL holds
+// the credentials and is only returned by info requests.
+type Addon struct {
+    Name               string     `json:"name"`
+    Type               string     `json:"type"`
+    Plan               string     `json:"plan"`
+    Version            string     `json:"version"`
+    Region             string     `json:"region,omitempty"`
+    State              string     `json:"state"`
+    App                string     `json:"app"`
+    EnvVar             string     `json:"envVar"`
+    URL                string     `json:"url,omitempty"`
+    CreatedAt          time.Time  `json:"createdAt"`
+    CredentialsRotated *time.Time `json:"credentialsRotatedAt,omitempty"`
+}
+
+// AddonsResponse represents the response from an add-ons list request
+type AddonsResponse struct {
+    Addons []Addon `json:"addons"`
+}
+
+// AddonOptions are the settings of a new add-on. Name defaults to
+// <app>-<type> and EnvVar to the usual variable of the type, e.g. DATABASE_URL.
+type AddonOptions struct {
+    Type   string `json:"type"`
+    Plan   string `json:"plan"`
+    App    string `json:"app"`
+    Name   string `json:"name,omitempty"`
+    EnvVar string `json:"envVar,omitempty"`
+}
+
+// AddonBackup is a backup of the data of an add-on
+type AddonBackup struct {
+    ID        string    `json:"id"`
+    Addon     string    `json:"addon"`
+    Trigger   string    `json:"trigger"`
+    SizeBytes float64   `json:"sizeBytes"`
+    CreatedAt time.Time `json:"createdAt"`
+}
+
+// AddonBackupsResponse represents the response from an add-on backups list request
+type AddonBackupsResponse struct {
+    Backups []AddonBackup `json:"backups"`
+}
+
+// AddonBinding is an environment variable injected from an add-on on deploy.
+// Overridden is set when envVars of the config file sets the same variable,
+// whose value then wins.
+type AddonBinding struct {
+    Addon      string `json:"addon"`
+    Type       string `json:"type"`
+    EnvVar     string `json:"envVar"`
+    Overridden bool   `json:"overridden,omitempty"`
+}
@@ -0,0 +1,206 @@
+package types
+
+import (
+    "fmt"
+    "net/mail"
+    "net/url"
+    "slices"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Values alert rules can watch, besides the resource usage metrics
+const (
+    AlertOnState  = "state"
+    AlertOnHealth = "health"
+)
+
+// Notification channel types
+const (
+    ChannelWebhook = "webhook"
+    ChannelSlack   = "slack"
+    ChannelEmail   = "email"
+)
+
+// Alert states
+const (
+    AlertOK     = "ok"
+    AlertFiring = "firing"
+)
+
+// alertOperators are the comparison operators of alert conditions, longest first
+var alertOperators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// AlertRule fires a notification when a condition on an application holds
+// for the given duration
+type AlertRule struct {
+    ID        string     `json:"id,omitempty"`
+    App       string     `json:"app"`
+    Metric    string     `json:"metric"`
+    Operator  string     `json:"operator"`
+    Threshold float64    `json:"threshold,omitempty"`
+    Value     string     `json:"value,omitempty"`
+    For       string     `json:"for,omitempty"`
+    Channels  []string   `json:"channels"`
+    State     string     `json:"state,omitempty"`
+    CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// ParseCondition fills the metric, operator and threshold of the rule from
+// a condition such as "cpu > 80" or "health == unhealthy". Resource usage
+// (cpu, memory, storage) is compared in percent of the limit; state and
+// health are compared with == or != to one of AppStates or HealthStatuses.
+func (r *AlertRule) ParseCondition(condition string) error {
+    for _, op := range alertOperators {
+        metric, value, ok := strings.Cut(condition, op)
+        if !ok {
+            continue
+        }
+        r.Metric = strings.TrimSpace(metric)
+        r.Operator = op
+        value = strings.TrimSpace(value)
+
+        switch r.Metric {
+        case MetricCPU, MetricMemory, MetricStorage:
+            threshold, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+            if err != nil {
+                return fmt.Errorf("invalid condition %q: %s is compared to a percentage, got %q", condition, r.Metric, value)
+            }
+            if threshold < 0 || threshold > 100 {
+                return fmt.Errorf("invalid condition %q: %s is compared to a percentage between 0 and 100, got %s", condition, r.Metric, value)
+            }
+            r.Threshold = threshold
+        case AlertOnState, AlertOnHealth:
+            if op != "==" && op != "!=" {
+                return fmt.Errorf("invalid condition %q: %s can only be compared with == or !=", condition, r.Metric)
+            }
+            if value == "" {
+                return fmt.Errorf("invalid condition %q: missing %s to compare to", condition, r.Metric)
+            }
+            known := AppStates
+            if r.Metric == AlertOnHealth {
+                known = HealthStatuses
+            }
+            if !slices.Contains(known, value) {
+                return fmt.Errorf("invalid condition %q: unknown %s %q (expected %s)", condition, r.Metric, value, strings.Join(known, ", "))
+            }
+            r.Value = value
+        default:
+            return fmt.Errorf("invalid condition %q: unknown metric %q (expected cpu, memory, storage, state or health)", condition, r.Metric)
+        }
+        return nil
+    }
+    return fmt.Errorf("invalid condition %q, expected e.g. \"cpu > 80\" or \"health == unhealthy\"", condition)
+}
+
+// Condition formats the condition of the rule, e.g. cpu > 80%
+func (r *AlertRule) Condition() string {
+    if r.Value != "" {
+        return fmt.Sprintf("%s %s %s", r.Metric, r.Operator, r.Value)
+    }
+    return fmt.Sprintf("%s %s %s%%", r.Metric, r.Operator, strconv.FormatFloat(r.Threshold, 'f', -1, 64))
+}
+
+// Validate ensures the rule is complete
+func (r *AlertRule) Validate() error {
+    if r.App == "" {
+        return fmt.Errorf("application name is required")
+    }
+    if r.Metric == "" {
+        return fmt.Errorf("condition is required")
+    }
+    if r.For != "" {
+        if d, err := time.ParseDuration(r.For); err != nil || d < 0 {
+            return fmt.Errorf("invalid duration %q for --for", r.For)
+        }
+    }
+    if len(r.Channels) == 0 {
+        return fmt.Errorf("at least one notification channel is required. Create one with 'ghaymah notifications create'")
+    }
+    return nil
+}
+
+// AlertsResponse represents the response from an alerts list request
+type AlertsResponse struct {
+    Alerts []AlertRule `json:"alerts"`
+}
+
+// ChannelDelivery is the result of sending a notification to a channel
+type ChannelDelivery struct {
+    Channel   string `json:"channel"`
+    Delivered bool   `json:"delivered"`
+    Error     string `json:"error,omitempty"`
+}
+
+// AlertTestResponse represents the response from an alert test request
+type AlertTestResponse struct {
+    Deliveries []ChannelDelivery `json:"deliveries"`
+}
+
+// NotificationChannel is where alert notifications are sent: a webhook
+// receiving JSON, a Slack-compatible incoming webhook, or email addresses
+type NotificationChannel struct {
+    Name      string     `json:"name"`
+    Type      string     `json:"type"`
+    URL       string     `json:"url,omitempty"`
+    To        []string   `json:"to,omitempty"`
+    CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// Validate ensures the channel has the settings its type needs
+func (c *NotificationChannel) Validate() error {
+    if c.Name == "" {
+        return fmt.Errorf("channel name is required")
+    }
+
+    switch c.Type {
+    case ChannelWebhook, ChannelSlack:
+        if len(c.To) > 0 {
+            return fmt.Errorf("--to is only used by email channels")
+        }
+        u, err := url.Parse(c.URL)
+        if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+            return fmt.Errorf("%s channels require an http(s) --url, got %q", c.Type, c.URL)
+        }
+        if c.Type == ChannelSlack && u.Scheme != "https" {
+            return fmt.Errorf("slack webhook URLs must use https")
+        }
+    case ChannelEmail:
+        if c.URL != "" {
+            return fmt.Errorf("--url is only used by webhook and slack channels")
+        }
+        if len(c.To) == 0 {
+            return fmt.Errorf("email channels require --to")
+        }
+        for _, to := range c.To {
+            if _, err := mail.ParseAddress(to); err != nil {
+                return fmt.Errorf("invalid email address %q", to)
+            }
+        }
+    default:
+        return fmt.Errorf("unknown channel type %q (expected webhook, slack or email)", c.Type)
+    }
+    return nil
+}
+
+// Target describes where the channel sends notifications. The path of
+// webhook URLs is masked as it usually contains a secret token.
+func (c *NotificationChannel) Target() string {
+    if c.Type == ChannelEmail {
+        return strings.Join(c.To, ", ")
+    }
+    u, err := url.Parse(c.URL)
+    if err != nil || u.Host == "" {
+        return c.URL
+    }
+    if strings.Trim(u.Path, "/") == "" && u.RawQuery == "" {
+        return u.Scheme + "://" + u.Host
+    }
+    return u.Scheme + "://" + u.Host + "/***"
+}
+
+// NotificationChannelsResponse represents the response from a notification channels list request
+type NotificationChannelsResponse struct {
+    Channels []NotificationChannel `json:"channels"`
+}
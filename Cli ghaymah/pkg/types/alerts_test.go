@@ -0,0 +1,78 @@
+package types
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestParseCondition(t *testing.T) {
+    tests := []struct {
+        condition string
+        want      AlertRule
+        wantErr   string
+    }{
+        {condition: "cpu > 80", want: AlertRule{Metric: MetricCPU, Operator: ">", Threshold: 80}},
+        {condition: "memory>=90.5%", want: AlertRule{Metric: MetricMemory, Operator: ">=", Threshold: 90.5}},
+        {condition: "storage <= 0", want: AlertRule{Metric: MetricStorage, Operator: "<=", Threshold: 0}},
+        {condition: "cpu < 100", want: AlertRule{Metric: MetricCPU, Operator: "<", Threshold: 100}},
+        {condition: "health == unhealthy", want: AlertRule{Metric: AlertOnHealth, Operator: "==", Value: HealthUnhealthy}},
+        {condition: "state != running", want: AlertRule{Metric: AlertOnState, Operator: "!=", Value: AppRunning}},
+        {condition: "cpu > 150", wantErr: "between 0 and 100, got 150"},
+        {condition: "memory > -5%", wantErr: "between 0 and 100"},
+        {condition: "cpu > lots", wantErr: "compared to a percentage"},
+        {condition: "health == helthy", wantErr: `unknown health "helthy" (expected healthy, degraded, unhealthy)`},
+        {condition: "state == runing", wantErr: `unknown state "runing"`},
+        {condition: "health == running", wantErr: `unknown health "running"`},
+        {condition: "state == degraded", wantErr: `unknown state "degraded"`},
+        {condition: "health > healthy", wantErr: "only be compared with == or !="},
+        {condition: "state ==", wantErr: "missing state"},
+        {condition: "latency > 100", wantErr: `unknown metric "latency"`},
+        {condition: "cpu is high", wantErr: "expected e.g."},
+    }
+    for _, tt := range tests {
+        var rule AlertRule
+        err := rule.ParseCondition(tt.condition)
+        switch {
+        case tt.wantErr == "" && err != nil:
+            t.Errorf("%q: unexpected error %v", tt.condition, err)
+        case tt.wantErr == "" && rule.Condition() != tt.want.Condition():
+            t.Errorf("%q: parsed as %q, want %q", tt.condition, rule.Condition(), tt.want.Condition())
+        case tt.wantErr == "" && (rule.Metric != tt.want.Metric || rule.Threshold != tt.want.Threshold || rule.Value != tt.want.Value):
+            t.Errorf("%q: parsed as %+v, want %+v", tt.condition, rule, tt.want)
+        case tt.wantErr != "" && err == nil:
+            t.Errorf("%q: expected an error containing %q", tt.condition, tt.wantErr)
+        case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+            t.Errorf("%q: error %q doesn't contain %q", tt.condition, err, tt.wantErr)
+        }
+    }
+}
+
+func TestAlertRuleValidate(t *testing.T) {
+    valid := AlertRule{App: "shop", Metric: MetricCPU, Operator: ">", Threshold: 80, For: "5m", Channels: []string{"oncall"}}
+    tests := []struct {
+        name    string
+        change  func(r *AlertRule)
+        wantErr string
+    }{
+        {name: "valid", change: func(r *AlertRule) {}},
+        {name: "no duration", change: func(r *AlertRule) { r.For = "" }},
+        {name: "no app", change: func(r *AlertRule) { r.App = "" }, wantErr: "application name is required"},
+        {name: "no condition", change: func(r *AlertRule) { r.Metric = "" }, wantErr: "condition is required"},
+        {name: "invalid duration", change: func(r *AlertRule) { r.For = "5 minutes" }, wantErr: `invalid duration "5 minutes"`},
+        {name: "negative duration", change: func(r *AlertRule) { r.For = "-1m" }, wantErr: "invalid duration"},
+        {name: "no channel", change: func(r *AlertRule) { r.Channels = nil }, wantErr: "at least one notification channel"},
+    }
+    for _, tt := range tests {
+        rule := valid
+        tt.change(&rule)
+        err := rule.Validate()
+        switch {
+        case tt.wantErr == "" && err != nil:
+            t.Errorf("%s: unexpected error %v", tt.name, err)
+        case tt.wantErr != "" && err == nil:
+            t.Errorf("%s: expected an error containing %q", tt.name, tt.wantErr)
+        case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+            t.Errorf("%s: error %q doesn't contain %q", tt.name, err, tt.wantErr)
+        }
+    }
+}
@@ -0,0 +1,68 @@
+package types
+
+import "time"
+
+// Application states. Restarting, stopping and starting are transitions
+// towards running or stopped.
+const (
+    AppDeploying  = "deploying"
+    AppRunning    = "running"
+    AppRestarting = "restarting"
+    AppStopping   = "stopping"
+    AppStopped    = "stopped"
+    AppStarting   = "starting"
+)
+
+// AppStates are the application states
+var AppStates = []string{AppDeploying, AppRunning, AppRestarting, AppStopping, AppStopped, AppStarting}
+
+// AppSummary is a short description of an application
+type AppSummary struct {
+    Name       string            `json:"name"`
+    State      string            `json:"state"`
+    Region     string            `json:"region,omitempty"`
+    Regions    []string          `json:"regions,omitempty"`
+    URL        string            `json:"url,omitempty"`
+    Labels     map[string]string `json:"labels,omitempty"`
+    DeployedAt *time.Time        `json:"deployedAt,omitempty"`
+}
+
+// AppsResponse represents the response from an apps list request
+type AppsResponse struct {
+    Apps []AppSummary `json:"apps"`
+}
+
+// Region states. Limited regions are running out of capacity; unavailable
+// ones don't accept new deployments.
+const (
+    RegionAvailable   = "available"
+    RegionLimited     = "limited"
+    RegionUnavailable = "unavailable"
+)
+
+// Region describes a region applications can be deployed to. CapacityUsed is
+// in percent; PingURL answers quickly so clients can measure their latency.
+type Region struct {
+    Name         string  `json:"name"`
+    DisplayName  string  `json:"displayName,omitempty"`
+    Status       string  `json:"status,omitempty"`
+    CapacityUsed float64 `json:"capacityUsed,omitempty"`
+    PingURL      string  `json:"pingUrl,omitempty"`
+    Default      bool    `json:"default,omitempty"`
+}
+
+// RegionStatus is the state of an application in one of the regions it is
+// deployed to
+type RegionStatus struct {
+    Region    string `json:"region"`
+    State     string `json:"state"`
+    Version   int    `json:"version,omitempty"`
+    Instances int    `json:"instances"`
+    URL       string `json:"url,omitempty"`
+    Message   string `json:"message,omitempty"`
+}
+
+// RegionsResponse represents the response from a regions list request
+type RegionsResponse struct {
+    Regions []Region `json:"regions"`
+}
@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+// DNSRecord is a DNS record the user must create for a custom domain
+type DNSRecord struct {
+    Type  string `json:"type"`
+    Name  string `json:"name"`
+    Value string `json:"value"`
+}
+
+// Certificate describes the managed TLS certificate of a custom domain
+type Certificate struct {
+    Status    string     `json:"status"`
+    Issuer    string     `json:"issuer,omitempty"`
+    ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Domain represents a custom domain attached to an application
+type Domain struct {
+    Name        string       `json:"name"`
+    Status      string       `json:"status"`
+    DNSRecords  []DNSRecord  `json:"dnsRecords"`
+    Certificate *Certificate `json:"certificate,omitempty"`
+    VerifiedAt  *time.Time   `json:"verifiedAt,omitempty"`
+    Message     string       `json:"message,omitempty"`
+}
+
+// DomainsResponse represents the response from a domains list request
+type DomainsResponse struct {
+    Domains []Domain `json:"domains"`
+}
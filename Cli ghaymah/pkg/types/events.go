@@ -0,0 +1,53 @@
+package types
+
+import "time"
+
+// Activity events, besides the events webhooks can subscribe to
+const (
+    EventAppScaled         = "app.scaled"
+    EventAppRestarted      = "app.restarted"
+    EventAppStopped        = "app.stopped"
+    EventAppStarted        = "app.started"
+    EventAppLocked         = "app.locked"
+    EventAppUnlocked       = "app.unlocked"
+    EventInstanceRestarted = "instance.restarted"
+    EventInstanceOOMKilled = "instance.oom_killed"
+    EventHealthFailed      = "health.failed"
+    EventConfigChanged     = "config.changed"
+)
+
+// EventTypes lists the types of activity events
+var EventTypes = []string{
+    EventDeployStarted, EventDeploySucceeded, EventDeployFailed,
+    EventAppCrashed, EventAppScaled, EventAppRestarted, EventAppStopped, EventAppStarted,
+    EventAppLocked, EventAppUnlocked,
+    EventInstanceRestarted, EventInstanceOOMKilled, EventHealthFailed, EventConfigChanged,
+}
+
+// AppEvent is something that happened to an application: a deploy, restart,
+// scaling, OOM kill, health check failure or configuration change. Actor is
+// the user who caused it, or "platform".
+type AppEvent struct {
+    ID        string                 `json:"id"`
+    Type      string                 `json:"type"`
+    App       string                 `json:"app"`
+    Actor     string                 `json:"actor"`
+    Message   string                 `json:"message"`
+    Instance  string                 `json:"instance,omitempty"`
+    Data      map[string]interface{} `json:"data,omitempty"`
+    Timestamp time.Time              `json:"timestamp"`
+}
+
+// EventsResponse represents the response from an events request
+type EventsResponse struct {
+    Events []AppEvent `json:"events"`
+}
+
+// EventOptions filter the events returned. Types match exactly or, for a
+// category such as "deploy", all events of the category.
+type EventOptions struct {
+    Since time.Time
+    Until time.Time
+    Types []string
+    Limit int
+}
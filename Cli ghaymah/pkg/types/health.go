@@ -0,0 +1,114 @@
+package types
+
+import (
+    "fmt"
+    "time"
+)
+
+// Health check types
+const (
+    HealthCheckHTTP    = "http"
+    HealthCheckTCP     = "tcp"
+    HealthCheckCommand = "command"
+)
+
+// Health statuses of applications and instances. An application is
+// degraded when some of its instances are unhealthy.
+const (
+    HealthHealthy   = "healthy"
+    HealthDegraded  = "degraded"
+    HealthUnhealthy = "unhealthy"
+)
+
+// HealthStatuses are the health statuses, in order of severity
+var HealthStatuses = []string{HealthHealthy, HealthDegraded, HealthUnhealthy}
+
+// HealthCheckConfig defines how the platform checks that an instance is healthy
+type HealthCheckConfig struct {
+    Type               string   `yaml:"type,omitempty" json:"type"`
+    Path               string   `yaml:"path,omitempty" json:"path,omitempty"`
+    Port               int      `yaml:"port,omitempty" json:"port,omitempty"`
+    Command            []string `yaml:"command,omitempty" json:"command,omitempty"`
+    Interval           string   `yaml:"interval,omitempty" json:"interval,omitempty"`
+    Timeout            string   `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+    HealthyThreshold   int      `yaml:"healthyThreshold,omitempty" json:"healthyThreshold,omitempty"`
+    UnhealthyThreshold int      `yaml:"unhealthyThreshold,omitempty" json:"unhealthyThreshold,omitempty"`
+    GracePeriod        string   `yaml:"gracePeriod,omitempty" json:"gracePeriod,omitempty"`
+}
+
+// CheckType returns the configured type, inferring it from the other fields when omitted
+func (h *HealthCheckConfig) CheckType() string {
+    if h.Type != "" {
+        return h.Type
+    }
+    if h.Path != "" {
+        return HealthCheckHTTP
+    }
+    if len(h.Command) > 0 {
+        return HealthCheckCommand
+    }
+    return HealthCheckTCP
+}
+
+// Validate ensures the health check is consistent
+func (h *HealthCheckConfig) Validate() error {
+    switch h.CheckType() {
+    case HealthCheckHTTP:
+        if h.Path == "" {
+            return fmt.Errorf("healthCheck.path is required for http checks")
+        }
+        if h.Port == 0 {
+            return fmt.Errorf("healthCheck.port is required for http checks")
+        }
+    case HealthCheckTCP:
+        if h.Port == 0 {
+            return fmt.Errorf("healthCheck.port is required for tcp checks")
+        }
+    case HealthCheckCommand:
+        if len(h.Command) == 0 {
+            return fmt.Errorf("healthCheck.command is required for command checks")
+        }
+    default:
+        return fmt.Errorf("unknown healthCheck.type %q (expected http, tcp or command)", h.Type)
+    }
+
+    if h.Port < 0 || h.Port > 65535 {
+        return fmt.Errorf("healthCheck.port %d is out of range", h.Port)
+    }
+    // A threshold of 0 can't be told apart from one left out, so both use the default
+    if h.HealthyThreshold < 0 || h.UnhealthyThreshold < 0 {
+        return fmt.Errorf("healthCheck thresholds must not be negative")
+    }
+
+    durations := map[string]string{
+        "interval":    h.Interval,
+        "timeout":     h.Timeout,
+        "gracePeriod": h.GracePeriod,
+    }
+    for field, value := range durations {
+        if value == "" {
+            continue
+        }
+        if _, err := time.ParseDuration(value); err != nil {
+            return fmt.Errorf("healthCheck.%s: invalid duration %q", field, value)
+        }
+    }
+
+    return nil
+}
+
+// InstanceHealth is the latest health check result of a single instance
+type InstanceHealth struct {
+    Instance            string     `json:"instance"`
+    Status              string     `json:"status"`
+    LastCheck           time.Time  `json:"lastCheck"`
+    ConsecutiveFailures int        `json:"consecutiveFailures"`
+    LastFailure         string     `json:"lastFailure,omitempty"`
+    LastFailureAt       *time.Time `json:"lastFailureAt,omitempty"`
+}
+
+// HealthStatus aggregates health check results of an application
+type HealthStatus struct {
+    Status    string           `json:"status"`
+    Instances []InstanceHealth `json:"instances"`
+}
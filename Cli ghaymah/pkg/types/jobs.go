@@ -0,0 +1,116 @@
+package types
+
+import (
+    "fmt"
+    "time"
+    "ghaymah-cli/pkg/cron"
+)
+
+// Run states of jobs and tasks
+const (
+    RunPending   = "pending"
+    RunRunning   = "running"
+    RunSucceeded = "succeeded"
+    RunFailed    = "failed"
+)
+
+// Run triggers
+const (
+    TriggerSchedule = "schedule"
+    TriggerManual   = "manual"
+    TriggerTask     = "task"
+)
+
+// JobConfig defines a command run on a cron schedule in a container of the
+// application's image, or of Image when set
+type JobConfig struct {
+    Schedule  string          `yaml:"schedule" json:"schedule"`
+    Command   []string        `yaml:"command" json:"command"`
+    Image     string          `yaml:"image,omitempty" json:"image,omitempty"`
+    Resources *ResourceConfig `yaml:"resources,omitempty" json:"resources,omitempty"`
+    Timeout   string          `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// Validate ensures the job can be scheduled
+func (j *JobConfig) Validate() error {
+    if j.Schedule == "" {
+        return fmt.Errorf("schedule is required")
+    }
+    schedule, err := cron.Parse(j.Schedule)
+    if err != nil {
+        return fmt.Errorf("invalid schedule %q: %v", j.Schedule, err)
+    }
+    if schedule.Next(time.Now()).IsZero() {
+        return fmt.Errorf("schedule %q never runs", j.Schedule)
+    }
+    if len(j.Command) == 0 {
+        return fmt.Errorf("command is required")
+    }
+    if j.Timeout != "" {
+        if d, err := time.ParseDuration(j.Timeout); err != nil || d <= 0 {
+            return fmt.Errorf("invalid timeout %q", j.Timeout)
+        }
+    }
+    return nil
+}
+
+// Job is a scheduled job of a deployed application
+type Job struct {
+    Name string `json:"name"`
+    JobConfig
+    LastRun *JobRun `json:"lastRun,omitempty"`
+}
+
+// JobsResponse represents the response from a jobs list request
+type JobsResponse struct {
+    Jobs []Job `json:"jobs"`
+}
+
+// JobRun is a run of a scheduled job, or a one-off task when Job is empty
+type JobRun struct {
+    ID         string     `json:"id"`
+    App        string     `json:"app"`
+    Job        string     `json:"job,omitempty"`
+    Command    []string   `json:"command"`
+    Image      string     `json:"image"`
+    Trigger    string     `json:"trigger"`
+    State      string     `json:"state"`
+    ExitCode   *int       `json:"exitCode,omitempty"`
+    Message    string     `json:"message,omitempty"`
+    StartedAt  time.Time  `json:"startedAt"`
+    FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Done reports whether the run finished
+func (r *JobRun) Done() bool {
+    return r.State == RunSucceeded || r.State == RunFailed
+}
+
+// Duration returns how long the run took, or has been running until now
+func (r *JobRun) Duration(now time.Time) time.Duration {
+    if r.FinishedAt != nil {
+        return r.FinishedAt.Sub(r.StartedAt)
+    }
+    return now.Sub(r.StartedAt)
+}
+
+// JobRunsResponse represents the response from a job history request
+type JobRunsResponse struct {
+    Runs []JobRun `json:"runs"`
+}
+
+// RunLogsResponse is the output of a run from a cursor. Next is the cursor to
+// read the following output from.
+type RunLogsResponse struct {
+    Run     JobRun     `json:"run"`
+    Entries []LogEntry `json:"entries"`
+    Next    int        `json:"next"`
+}
+
+// TaskOptions define a one-off task run in a container of an application
+type TaskOptions struct {
+    Command   []string          `json:"command"`
+    Image     string            `json:"image,omitempty"`
+    Resources *ResourceConfig   `json:"resources,omitempty"`
+    EnvVars   map[string]string `json:"env,omitempty"`
+}
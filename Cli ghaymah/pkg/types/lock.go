@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+// AppLock freezes deployments of an application
+type AppLock struct {
+    Reason   string    `json:"reason"`
+    LockedBy string    `json:"lockedBy,omitempty"`
+    LockedAt time.Time `json:"lockedAt"`
+}
+
+// DeployOptions control how a deployment is applied
+type DeployOptions struct {
+    // ExpectedVersion is the release the deployment replaces. The API rejects
+    // the deployment if another release was deployed since. 0 means the
+    // application must not exist yet; nil skips the check.
+    ExpectedVersion *int
+}
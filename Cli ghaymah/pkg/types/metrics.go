@@ -0,0 +1,46 @@
+package types
+
+import "time"
+
+// Metrics returned by the metrics endpoint
+const (
+    MetricCPU      = "cpu"
+    MetricMemory   = "memory"
+    MetricStorage  = "storage"
+    MetricRequests = "requests"
+    MetricLatency  = "latency"
+    MetricErrors   = "errors"
+)
+
+// Metrics lists the available metrics in display order. Request metrics are
+// only reported for applications serving HTTP.
+var Metrics = []string{MetricCPU, MetricMemory, MetricStorage, MetricRequests, MetricLatency, MetricErrors}
+
+// MetricsOptions select the time range and resolution of a metrics query
+type MetricsOptions struct {
+    Start   time.Time
+    End     time.Time
+    Step    time.Duration
+    Metrics []string
+}
+
+// MetricPoint is the value of a metric over the step starting at Timestamp
+type MetricPoint struct {
+    Timestamp time.Time `json:"timestamp"`
+    Value     float64   `json:"value"`
+}
+
+// MetricSeries is the history of one metric
+type MetricSeries struct {
+    Name   string        `json:"name"`
+    Unit   string        `json:"unit"`
+    Points []MetricPoint `json:"points"`
+}
+
+// MetricsResponse represents the response from a metrics request
+type MetricsResponse struct {
+    Start       time.Time      `json:"start"`
+    End         time.Time      `json:"end"`
+    StepSeconds int            `json:"stepSeconds"`
+    Series      []MetricSeries `json:"series"`
+}
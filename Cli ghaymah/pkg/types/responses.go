@@ -4,27 +4,40 @@ import "time"
 
 // ResourceConfig defines the resource requirements for deployment
 type ResourceConfig struct {
-    CPU     string `yaml:"cpu"`
-    Memory  string `yaml:"memory"`
-    Storage string `yaml:"storage"`
+    CPU     string `yaml:"cpu,omitempty"`
+    Memory  string `yaml:"memory,omitempty"`
+    Storage string `yaml:"storage,omitempty"`
 }
 
 // DeployResponse represents the response from a deployment request
 type DeployResponse struct {
-    AppID  string `json:"appId"`
-    Status string `json:"status"`
-    URL    string `json:"url,omitempty"`
+    AppID   string         `json:"appId"`
+    Status  string         `json:"status"`
+    URL     string         `json:"url,omitempty"`
+    Addons  []AddonBinding `json:"addons,omitempty"`
+    Regions []RegionStatus `json:"regions,omitempty"`
 }
 
 // StatusResponse represents the response from a status request
 type StatusResponse struct {
     State         string    `json:"state"`
+    Version       int       `json:"version,omitempty"`
     LastDeployment time.Time `json:"lastDeployment"`
     Resources     struct {
         CPUUsage     float64 `json:"cpuUsage"`
         MemoryUsage  float64 `json:"memoryUsage"`
         StorageUsage float64 `json:"storageUsage"`
     } `json:"resources"`
+    Health        *HealthStatus `json:"health,omitempty"`
+    Lock          *AppLock      `json:"lock,omitempty"`
+    Volumes       []VolumeUsage `json:"volumes,omitempty"`
+    Regions       []RegionStatus `json:"regions,omitempty"`
+
+    // Only returned for detailed status requests
+    Release   *ReleaseInfo         `json:"release,omitempty"`
+    Instances []InstanceStatus     `json:"instances,omitempty"`
+    Events    []PlatformEvent      `json:"events,omitempty"`
+    Usage     *ResourceUsageDetail `json:"usage,omitempty"`
 }
 
 // LogEntry represents a single log entry
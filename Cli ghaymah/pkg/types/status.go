@@ -0,0 +1,44 @@
+package types
+
+import "time"
+
+// ReleaseInfo describes the release currently running
+type ReleaseInfo struct {
+    Version     int       `json:"version"`
+    Image       string    `json:"image"`
+    ImageDigest string    `json:"imageDigest"`
+    DeployedAt  time.Time `json:"deployedAt"`
+}
+
+// InstanceStatus is the state of a single application instance
+type InstanceStatus struct {
+    ID             string    `json:"id"`
+    State          string    `json:"state"`
+    Restarts       int       `json:"restarts"`
+    StartedAt      time.Time `json:"startedAt"`
+    LastExitReason string    `json:"lastExitReason,omitempty"`
+}
+
+// PlatformEvent is something the platform did to the application,
+// such as an OOM kill, a crash loop back-off or a scheduling failure
+type PlatformEvent struct {
+    Type      string    `json:"type"`
+    Reason    string    `json:"reason"`
+    Message   string    `json:"message"`
+    Instance  string    `json:"instance,omitempty"`
+    Timestamp time.Time `json:"timestamp"`
+}
+
+// ResourceQuantity is a used amount against its limit in absolute units
+// (CPU in cores, memory and storage in bytes)
+type ResourceQuantity struct {
+    Used  float64 `json:"used"`
+    Limit float64 `json:"limit"`
+}
+
+// ResourceUsageDetail reports resource usage against limits
+type ResourceUsageDetail struct {
+    CPU     ResourceQuantity `json:"cpu"`
+    Memory  ResourceQuantity `json:"memory"`
+    Storage ResourceQuantity `json:"storage"`
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Deployment strategies
+const (
+    StrategyRolling   = "rolling"
+    StrategyBlueGreen = "blue-green"
+    StrategyCanary    = "canary"
+)
+
+// Rollout states
+const (
+    RolloutProgressing = "progressing"
+    RolloutPaused      = "paused"
+    RolloutCompleted   = "completed"
+    RolloutAborted     = "aborted"
+)
+
+// surgeRe matches an instance count or a percentage of instances
+var surgeRe = regexp.MustCompile(`^[0-9]+%?$`)
+
+// StrategyConfig defines how a new release replaces the running one
+type StrategyConfig struct {
+    Type           string       `yaml:"type,omitempty" json:"type"`
+    MaxSurge       string       `yaml:"maxSurge,omitempty" json:"maxSurge,omitempty"`
+    MaxUnavailable string       `yaml:"maxUnavailable,omitempty" json:"maxUnavailable,omitempty"`
+    AutoPromote    bool         `yaml:"autoPromote,omitempty" json:"autoPromote,omitempty"`
+    Steps          []CanaryStep `yaml:"steps,omitempty" json:"steps,omitempty"`
+}
+
+// CanaryStep sends a share of the traffic to the new release, then waits
+// for Pause, or for 'ghaymah rollout promote' when Pause is empty
+type CanaryStep struct {
+    Weight int    `yaml:"weight" json:"weight"`
+    Pause  string `yaml:"pause,omitempty" json:"pause,omitempty"`
+}
+
+// StrategyType returns the configured type, inferring it from the other fields when omitted
+func (s *StrategyConfig) StrategyType() string {
+    if s.Type != "" {
+        return s.Type
+    }
+    if len(s.Steps) > 0 {
+        return StrategyCanary
+    }
+    return StrategyRolling
+}
+
+// Validate ensures the strategy is consistent
+func (s *StrategyConfig) Validate() error {
+    switch s.StrategyType() {
+    case StrategyRolling:
+        if len(s.Steps) > 0 {
+            return fmt.Errorf("strategy.steps is only used by canary deployments")
+        }
+        for field, value := range map[string]string{"maxSurge": s.MaxSurge, "maxUnavailable": s.MaxUnavailable} {
+            if value != "" && !surgeRe.MatchString(value) {
+                return fmt.Errorf("strategy.%s: expected a number of instances or a percentage, got %q", field, value)
+            }
+        }
+        if isZero(s.MaxSurge) && isZero(s.MaxUnavailable) {
+            return fmt.Errorf("strategy.maxSurge and strategy.maxUnavailable can't both be 0")
+        }
+    case StrategyBlueGreen:
+        if len(s.Steps) > 0 || s.MaxSurge != "" || s.MaxUnavailable != "" {
+            return fmt.Errorf("blue-green deployments don't use strategy.steps, maxSurge or maxUnavailable")
+        }
+    case StrategyCanary:
+        if s.MaxSurge != "" || s.MaxUnavailable != "" {
+            return fmt.Errorf("canary deployments don't use strategy.maxSurge or maxUnavailable")
+        }
+        if len(s.Steps) == 0 {
+            return fmt.Errorf("strategy.steps is required for canary deployments")
+        }
+        previous := 0
+        for i, step := range s.Steps {
+            if step.Weight <= previous || step.Weight > 100 {
+                return fmt.Errorf("strategy.steps[%d].weight must be between %d and 100 and increase at each step", i, previous+1)
+            }
+            if step.Pause != "" {
+                if _, err := time.ParseDuration(step.Pause); err != nil {
+                    return fmt.Errorf("strategy.steps[%d].pause: invalid duration %q", i, step.Pause)
+                }
+            }
+            previous = step.Weight
+        }
+    default:
+        return fmt.Errorf("unknown strategy.type %q (expected rolling, blue-green or canary)", s.Type)
+    }
+    return nil
+}
+
+// isZero reports whether a surge value is set to 0 instances or 0%
+func isZero(value string) bool {
+    n, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+    return err == nil && n == 0
+}
+
+// Rollout is the progress of a release replacing the previous one
+type Rollout struct {
+    Strategy        string    `json:"strategy"`
+    State           string    `json:"state"`
+    Version         int       `json:"version"`
+    PreviousVersion int       `json:"previousVersion,omitempty"`
+    Step            int       `json:"step"`
+    TotalSteps      int       `json:"totalSteps"`
+    TrafficWeight   int       `json:"trafficWeight"`
+    Health          string    `json:"health,omitempty"`
+    Message         string    `json:"message,omitempty"`
+    StartedAt       time.Time `json:"startedAt"`
+    UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// Done reports whether the rollout reached a final state
+func (r *Rollout) Done() bool {
+    return r.State == RolloutCompleted || r.State == RolloutAborted
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestStrategyValidate(t *testing.T) {
+    tests := []struct {
+        name     string
+        strategy StrategyConfig
+        wantErr  string
+    }{
+        {name: "rolling default", strategy: StrategyConfig{}},
+        {name: "rolling percentages", strategy: StrategyConfig{MaxSurge: "25%", MaxUnavailable: "0"}},
+        {name: "rolling without surge or unavailable", strategy: StrategyConfig{MaxSurge: "0", MaxUnavailable: "0%"}, wantErr: "can't both be 0"},
+        {name: "rolling invalid surge", strategy: StrategyConfig{MaxSurge: "many"}, wantErr: "strategy.maxSurge"},
+        {name: "rolling with steps", strategy: StrategyConfig{Type: StrategyRolling, Steps: []CanaryStep{{Weight: 50}}}, wantErr: "only used by canary"},
+        {name: "blue-green", strategy: StrategyConfig{Type: StrategyBlueGreen, AutoPromote: true}},
+        {name: "blue-green with surge", strategy: StrategyConfig{Type: StrategyBlueGreen, MaxSurge: "1"}, wantErr: "blue-green deployments don't use"},
+        {name: "canary", strategy: StrategyConfig{Steps: []CanaryStep{{Weight: 10, Pause: "5m"}, {Weight: 50}, {Weight: 100}}}},
+        {name: "canary without steps", strategy: StrategyConfig{Type: StrategyCanary}, wantErr: "steps is required"},
+        {name: "canary weight 0", strategy: StrategyConfig{Steps: []CanaryStep{{Weight: 0}}}, wantErr: "steps[0].weight must be between 1 and 100"},
+        {name: "canary negative weight", strategy: StrategyConfig{Steps: []CanaryStep{{Weight: -10}}}, wantErr: "steps[0].weight"},
+        {name: "canary weight above 100", strategy: StrategyConfig{Steps: []CanaryStep{{Weight: 50}, {Weight: 101}}}, wantErr: "steps[1].weight must be between 51 and 100"},
+        {name: "canary repeated weight", strategy: StrategyConfig{Steps: []CanaryStep{{Weight: 20}, {Weight: 20}}}, wantErr: "steps[1].weight"},
+        {name: "canary decreasing weight", strategy: StrategyConfig{Steps: []CanaryStep{{Weight: 50}, {Weight: 20}}}, wantErr: "increase at each step"},
+        {name: "canary invalid pause", strategy: StrategyConfig{Steps: []CanaryStep{{Weight: 10, Pause: "5 minutes"}}}, wantErr: "steps[0].pause"},
+        {name: "canary with surge", strategy: StrategyConfig{Type: StrategyCanary, MaxSurge: "1", Steps: []CanaryStep{{Weight: 10}}}, wantErr: "canary deployments don't use"},
+        {name: "unknown type", strategy: StrategyConfig{Type: "big-bang"}, wantErr: "unknown strategy.type"},
+    }
+    for _, tt := range tests {
+        err := tt.strategy.Validate()
+        switch {
+        case tt.wantErr == "" && err != nil:
+            t.Errorf("%s: unexpected error %v", tt.name, err)
+        case tt.wantErr != "" && err == nil:
+            t.Errorf("%s: expected an error containing %q", tt.name, tt.wantErr)
+        case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+            t.Errorf("%s: error %q doesn't contain %q", tt.name, err, tt.wantErr)
+        }
+    }
+}
+
+func TestStrategyType(t *testing.T) {
+    if got := (&StrategyConfig{}).StrategyType(); got != StrategyRolling {
+        t.Errorf("StrategyType() without steps = %s, want %s", got, StrategyRolling)
+    }
+    if got := (&StrategyConfig{Steps: []CanaryStep{{Weight: 10}}}).StrategyType(); got != StrategyCanary {
+        t.Errorf("StrategyType() with steps = %s, want %s", got, StrategyCanary)
+    }
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+    "fmt"
+    "math"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Volume states
+const (
+    VolumeAvailable = "available"
+    VolumeAttached  = "attached"
+)
+
+// sizeRe matches sizes such as 10G, 512Mi or 1TB, in any case
+var sizeRe = regexp.MustCompile(`(?i)^([0-9]+)\s*([KMGT])i?B?$`)
+
+// sizeUnits are the multipliers of the size suffixes
+var sizeUnits = map[string]int64{"K": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40}
+
+// ParseSize parses a size in binary units, e.g. 10G, 512Mi or 1TB, into bytes
+func ParseSize(s string) (int64, error) {
+    m := sizeRe.FindStringSubmatch(strings.TrimSpace(s))
+    if m == nil {
+        return 0, fmt.Errorf("invalid size %q (expected e.g. 512M, 10G or 1T)", s)
+    }
+    unit := sizeUnits[strings.ToUpper(m[2])]
+    n, err := strconv.ParseInt(m[1], 10, 64)
+    if err != nil || n > math.MaxInt64/unit {
+        return 0, fmt.Errorf("invalid size %q: too large", s)
+    }
+    if n == 0 {
+        return 0, fmt.Errorf("invalid size %q", s)
+    }
+    return n * unit, nil
+}
+
+// VolumeMount mounts a persistent volume into the instances of an application.
+// Unlike resources.storage, the data of a volume survives redeploys.
+type VolumeMount struct {
+    Name      string `yaml:"name" json:"name"`
+    MountPath string `yaml:"mountPath" json:"mountPath"`
+    ReadOnly  bool   `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
+}
+
+// Volume is a named persistent volume of the account
+type Volume struct {
+    Name       string    `json:"name"`
+    Region     string    `json:"region,omitempty"`
+    Size       string    `json:"size"`
+    SizeBytes  float64   `json:"sizeBytes"`
+    UsedBytes  float64   `json:"usedBytes"`
+    State      string    `json:"state"`
+    AttachedTo string    `json:"attachedTo,omitempty"`
+    MountPath  string    `json:"mountPath,omitempty"`
+    CreatedAt  time.Time `json:"createdAt"`
+}
+
+// VolumesResponse represents the response from a volumes list request
+type VolumesResponse struct {
+    Volumes []Volume `json:"volumes"`
+}
+
+// VolumeSnapshot is a point in time copy of a volume
+type VolumeSnapshot struct {
+    ID          string    `json:"id"`
+    Volume      string    `json:"volume"`
+    Description string    `json:"description,omitempty"`
+    SizeBytes   float64   `json:"sizeBytes"`
+    CreatedAt   time.Time `json:"createdAt"`
+}
+
+// VolumeSnapshotsResponse represents the response from a snapshots list request
+type VolumeSnapshotsResponse struct {
+    Snapshots []VolumeSnapshot `json:"snapshots"`
+}
+
+// VolumeUsage is the usage of a volume mounted by an application
+type VolumeUsage struct {
+    Name      string  `json:"name"`
+    MountPath string  `json:"mountPath"`
+    ReadOnly  bool    `json:"readOnly,omitempty"`
+    UsedBytes float64 `json:"usedBytes"`
+    SizeBytes float64 `json:"sizeBytes"`
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestParseSize(t *testing.T) {
+    tests := []struct {
+        size    string
+        want    int64
+        wantErr string
+    }{
+        {size: "10G", want: 10 << 30},
+        {size: "512Mi", want: 512 << 20},
+        {size: "20Gi", want: 20 << 30},
+        {size: "1GiB", want: 1 << 30},
+        {size: "1TB", want: 1 << 40},
+        {size: "64k", want: 64 << 10},
+        {size: "2gib", want: 2 << 30},
+        {size: " 5 G ", want: 5 << 30},
+        {size: "8388607T", want: 8388607 << 40},
+        {size: "8388608T", wantErr: "too large"},
+        {size: "99999999T", wantErr: "too large"},
+        {size: "99999999999999999999K", wantErr: "too large"},
+        {size: "0G", wantErr: `invalid size "0G"`},
+        {size: "10", wantErr: "expected e.g. 512M"},
+        {size: "10P", wantErr: "expected e.g. 512M"},
+        {size: "1.5G", wantErr: "expected e.g. 512M"},
+        {size: "-1G", wantErr: "expected e.g. 512M"},
+        {size: "10GiBs", wantErr: "expected e.g. 512M"},
+        {size: "", wantErr: "expected e.g. 512M"},
+    }
+    for _, tt := range tests {
+        got, err := ParseSize(tt.size)
+        switch {
+        case tt.wantErr == "" && err != nil:
+            t.Errorf("ParseSize(%q): unexpected error %v", tt.size, err)
+        case tt.wantErr == "" && got != tt.want:
+            t.Errorf("ParseSize(%q) = %d, want %d", tt.size, got, tt.want)
+        case tt.wantErr != "" && err == nil:
+            t.Errorf("ParseSize(%q) = %d, expected an error containing %q", tt.size, got, tt.wantErr)
+        case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+            t.Errorf("ParseSize(%q): error %q doesn't contain %q", tt.size, err, tt.wantErr)
+        }
+    }
+}
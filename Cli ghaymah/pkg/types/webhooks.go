@@ -0,0 +1,63 @@
+package types
+
+import (
+    "fmt"
+    "net/url"
+    "time"
+)
+
+// Events webhooks can subscribe to
+const (
+    EventDeployStarted   = "deploy.started"
+    EventDeploySucceeded = "deploy.succeeded"
+    EventDeployFailed    = "deploy.failed"
+    EventAppCrashed      = "app.crashed"
+)
+
+// WebhookEvents lists the events webhooks can subscribe to
+var WebhookEvents = []string{EventDeployStarted, EventDeploySucceeded, EventDeployFailed, EventAppCrashed}
+
+// Webhook posts the events of an application to a URL. Secret is only
+// returned when the webhook is created.
+type Webhook struct {
+    ID        string     `json:"id,omitempty"`
+    App       string     `json:"app"`
+    URL       string     `json:"url"`
+    Events    []string   `json:"events"`
+    Secret    string     `json:"secret,omitempty"`
+    CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// Validate ensures the webhook posts to an http(s) URL and subscribes to known events
+func (w *Webhook) Validate() error {
+    u, err := url.Parse(w.URL)
+    if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+        return fmt.Errorf("webhooks require an http(s) --url, got %q", w.URL)
+    }
+    if len(w.Events) == 0 {
+        return fmt.Errorf("at least one event is required")
+    }
+    for _, event := range w.Events {
+        known := false
+        for _, e := range WebhookEvents {
+            known = known || e == event
+        }
+        if !known {
+            return fmt.Errorf("unknown event %q (expected one of deploy.started, deploy.succeeded, deploy.failed, app.crashed)", event)
+        }
+    }
+    return nil
+}
+
+// WebhooksResponse represents the response from a webhooks list request
+type WebhooksResponse struct {
+    Webhooks []Webhook `json:"webhooks"`
+}
+
+// WebhookDelivery is the result of posting an event to a webhook
+type WebhookDelivery struct {
+    Event      string `json:"event"`
+    StatusCode int    `json:"statusCode,omitempty"`
+    DurationMs int    `json:"durationMs"`
+    Error      string `json:"error,omitempty"`
+}
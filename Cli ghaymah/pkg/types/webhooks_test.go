@@ -0,0 +1,35 @@
+package types
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestWebhookValidate(t *testing.T) {
+    events := []string{EventDeploySucceeded}
+    tests := []struct {
+        name    string
+        webhook Webhook
+        wantErr string
+    }{
+        {name: "https", webhook: Webhook{URL: "https://bot.example.com/ghaymah", Events: events}},
+        {name: "http with port", webhook: Webhook{URL: "http://10.0.0.5:8080/hook", Events: events}},
+        {name: "no URL", webhook: Webhook{Events: events}, wantErr: `http(s) --url, got ""`},
+        {name: "relative URL", webhook: Webhook{URL: "/hook", Events: events}, wantErr: "http(s) --url"},
+        {name: "no scheme", webhook: Webhook{URL: "bot.example.com/hook", Events: events}, wantErr: "http(s) --url"},
+        {name: "other scheme", webhook: Webhook{URL: "ftp://bot.example.com/hook", Events: events}, wantErr: "http(s) --url"},
+        {name: "no events", webhook: Webhook{URL: "https://bot.example.com"}, wantErr: "at least one event"},
+        {name: "unknown event", webhook: Webhook{URL: "https://bot.example.com", Events: []string{"deploy.done"}}, wantErr: `unknown event "deploy.done"`},
+    }
+    for _, tt := range tests {
+        err := tt.webhook.Validate()
+        switch {
+        case tt.wantErr == "" && err != nil:
+            t.Errorf("%s: unexpected error %v", tt.name, err)
+        case tt.wantErr != "" && err == nil:
+            t.Errorf("%s: expected an error containing %q", tt.name, tt.wantErr)
+        case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+            t.Errorf("%s: error %q doesn't contain %q", tt.name, err, tt.wantErr)
+        }
+    }
+}
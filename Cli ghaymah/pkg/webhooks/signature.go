@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Headers sent with each webhook delivery
+const (
+    SignatureHeader = "X-Ghaymah-Signature"
+    EventHeader     = "X-Ghaymah-Event"
+    DeliveryHeader  = "X-Ghaymah-Delivery"
+)
+
+// ErrSignatureMismatch is returned when no signature of the header matches the payload
+var ErrSignatureMismatch = errors.New("signature does not match the payload")
+
+// Sign computes the signature header of a payload sent at t. The signature is
+// the hex HMAC-SHA256 of "<unix time>.<payload>" keyed with the secret, sent
+// as t=<unix time>,v1=<signature>. Signing the time lets receivers reject
+// replayed deliveries.
+func Sign(secret string, payload []byte, t time.Time) string {
+    timestamp := strconv.FormatInt(t.Unix(), 10)
+    return fmt.Sprintf("t=%s,v1=%s", timestamp, signature(secret, timestamp, payload))
+}
+
+// Verify checks a signature header against a payload. Deliveries signed more
+// than tolerance before or after now are rejected, unless tolerance is 0.
+// Several v1 signatures may be present while a secret is being rotated.
+// It returns the time the payload was signed.
+func Verify(secret string, payload []byte, header string, tolerance time.Duration, now time.Time) (time.Time, error) {
+    var (
+        timestamp  string
+        signatures []string
+    )
+    for _, part := range strings.Split(header, ",") {
+        key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+        if !ok {
+            return time.Time{}, fmt.Errorf("malformed signature header %q", header)
+        }
+        switch key {
+        case "t":
+            timestamp = value
+        case "v1":
+            signatures = append(signatures, value)
+        }
+    }
+
+    unix, err := strconv.ParseInt(timestamp, 10, 64)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("missing or invalid timestamp in signature header %q", header)
+    }
+    if len(signatures) == 0 {
+        return time.Time{}, fmt.Errorf("no v1 signature in signature header %q", header)
+    }
+    signedAt := time.Unix(unix, 0)
+
+    expected := signature(secret, timestamp, payload)
+    matched := false
+    for _, s := range signatures {
+        if hmac.Equal([]byte(s), []byte(expected)) {
+            matched = true
+        }
+    }
+    if !matched {
+        return signedAt, ErrSignatureMismatch
+    }
+
+    if tolerance > 0 {
+        if age := now.Sub(signedAt); age > tolerance || age < -tolerance {
+            return signedAt, fmt.Errorf("signature is valid but was made at %s, outside the tolerance of %s", signedAt.Format(time.RFC3339), tolerance)
+        }
+    }
+    return signedAt, nil
+}
+
+// signature computes the hex HMAC-SHA256 of a timestamped payload
+func signature(secret, timestamp string, payload []byte) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(timestamp))
+    mac.Write([]byte("."))
+    mac.Write(payload)
+    return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,118 @@
+package webhooks
+
+import (
+    "errors"
+    "strings"
+    "testing"
+    "time"
+)
+
+var (
+    payload  = []byte(`{"event":"deploy.succeeded","app":"shop"}`)
+    signedAt = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+    header := Sign("s3cret", payload, signedAt)
+    if !strings.HasPrefix(header, "t=1792411200,v1=") {
+        t.Errorf("header = %q, want it to start with the unix time", header)
+    }
+
+    got, err := Verify("s3cret", payload, header, 5*time.Minute, signedAt.Add(time.Minute))
+    if err != nil {
+        t.Fatalf("a signed payload doesn't verify: %v", err)
+    }
+    if !got.Equal(signedAt) {
+        t.Errorf("signed at %s, want %s", got, signedAt)
+    }
+}
+
+func TestVerifyMismatch(t *testing.T) {
+    header := Sign("s3cret", payload, signedAt)
+    tests := map[string]struct {
+        secret  string
+        payload []byte
+    }{
+        "wrong secret":     {secret: "other", payload: payload},
+        "modified payload": {secret: "s3cret", payload: []byte(`{"event":"deploy.failed","app":"shop"}`)},
+    }
+    for name, tt := range tests {
+        if _, err := Verify(tt.secret, tt.payload, header, 0, signedAt); !errors.Is(err, ErrSignatureMismatch) {
+            t.Errorf("%s: got %v, want ErrSignatureMismatch", name, err)
+        }
+    }
+
+    // Signing another time changes the signature
+    _, v1, _ := strings.Cut(header, ",")
+    replayed := "t=1792411260," + v1
+    if _, err := Verify("s3cret", payload, replayed, 0, signedAt); !errors.Is(err, ErrSignatureMismatch) {
+        t.Errorf("changed timestamp: got %v, want ErrSignatureMismatch", err)
+    }
+}
+
+func TestVerifySecretRotation(t *testing.T) {
+    // During a rotation, the payload is signed with the old and new secrets
+    _, oldSignature, _ := strings.Cut(Sign("old", payload, signedAt), ",")
+    _, newSignature, _ := strings.Cut(Sign("new", payload, signedAt), ",")
+    header := "t=1792411200, " + oldSignature + ", " + newSignature
+
+    for _, secret := range []string{"old", "new"} {
+        if _, err := Verify(secret, payload, header, 0, signedAt); err != nil {
+            t.Errorf("secret %s: %v", secret, err)
+        }
+    }
+    if _, err := Verify("other", payload, header, 0, signedAt); !errors.Is(err, ErrSignatureMismatch) {
+        t.Errorf("other secret: got %v, want ErrSignatureMismatch", err)
+    }
+}
+
+func TestVerifyTolerance(t *testing.T) {
+    header := Sign("s3cret", payload, signedAt)
+    tests := []struct {
+        name      string
+        now       time.Time
+        tolerance time.Duration
+        wantErr   bool
+    }{
+        {name: "within, after", now: signedAt.Add(4 * time.Minute), tolerance: 5 * time.Minute},
+        {name: "within, before", now: signedAt.Add(-4 * time.Minute), tolerance: 5 * time.Minute},
+        {name: "at the limit", now: signedAt.Add(5 * time.Minute), tolerance: 5 * time.Minute},
+        {name: "too old", now: signedAt.Add(6 * time.Minute), tolerance: 5 * time.Minute, wantErr: true},
+        {name: "in the future", now: signedAt.Add(-6 * time.Minute), tolerance: 5 * time.Minute, wantErr: true},
+        {name: "no tolerance", now: signedAt.Add(24 * time.Hour)},
+    }
+    for _, tt := range tests {
+        got, err := Verify("s3cret", payload, header, tt.tolerance, tt.now)
+        switch {
+        case tt.wantErr && (err == nil || !strings.Contains(err.Error(), "outside the tolerance")):
+            t.Errorf("%s: got %v, want the tolerance to be exceeded", tt.name, err)
+        case tt.wantErr && errors.Is(err, ErrSignatureMismatch):
+            t.Errorf("%s: an expired signature is reported as not matching", tt.name)
+        case !tt.wantErr && err != nil:
+            t.Errorf("%s: unexpected error %v", tt.name, err)
+        }
+        if !got.Equal(signedAt) {
+            t.Errorf("%s: signed at %s, want %s", tt.name, got, signedAt)
+        }
+    }
+}
+
+func TestVerifyMalformedHeaders(t *testing.T) {
+    _, v1, _ := strings.Cut(Sign("s3cret", payload, signedAt), ",")
+    tests := map[string]string{
+        "":                         "malformed signature header",
+        "garbage":                  "malformed signature header",
+        "t=1792411200;" + v1:       "missing or invalid timestamp",
+        v1:                         "missing or invalid timestamp",
+        "t=soon," + v1:             "missing or invalid timestamp",
+        "t=1792411200":             "no v1 signature",
+        "t=1792411200,v0=abcdef":   "no v1 signature",
+        "t=1792411200,," + v1:      "malformed signature header",
+    }
+    for header, want := range tests {
+        _, err := Verify("s3cret", payload, header, 0, signedAt)
+        if err == nil || !strings.Contains(err.Error(), want) {
+            t.Errorf("header %q: got %v, want %q", header, err, want)
+        }
+    }
+}
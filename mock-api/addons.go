@@ -0,0 +1,477 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type AddonPlan struct {
+	Name             string  `json:"name"`
+	Type             string  `json:"type"`
+	Memory           string  `json:"memory"`
+	Storage          string  `json:"storage"`
+	MaxConnections   int     `json:"maxConnections"`
+	BackupDays       int     `json:"backupDays"`
+	HighAvailability bool    `json:"highAvailability,omitempty"`
+	MonthlyPrice     float64 `json:"monthlyPrice"`
+}
+
+type Addon struct {
+	Name               string     `json:"name"`
+	Type               string     `json:"type"`
+	Plan               string     `json:"plan"`
+	Version            string     `json:"version"`
+	Region             string     `json:"region,omitempty"`
+	State              string     `json:"state"`
+	App                string     `json:"app"`
+	EnvVar             string     `json:"envVar"`
+	URL                string     `json:"url,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	CredentialsRotated *time.Time `json:"credentialsRotatedAt,omitempty"`
+}
+
+type AddonBackup struct {
+	ID        string    `json:"id"`
+	Addon     string    `json:"addon"`
+	Trigger   string    `json:"trigger"`
+	SizeBytes float64   `json:"sizeBytes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AddonBinding is an environment variable injected from an add-on on deploy
+type AddonBinding struct {
+	Addon      string `json:"addon"`
+	Type       string `json:"type"`
+	EnvVar     string `json:"envVar"`
+	Overridden bool   `json:"overridden,omitempty"`
+}
+
+// addonRecord is the mock server's record of an add-on
+type addonRecord struct {
+	Addon
+	user     string
+	password string
+	// busyUntil is the end of provisioning or of a restore
+	busyUntil time.Time
+	busyState string
+}
+
+// Simulated durations of add-on operations
+const (
+	provisionDuration = 3 * time.Second
+	restoreDuration   = 3 * time.Second
+)
+
+// addonDefaults are the version, environment variable, scheme and port of each type
+var addonDefaults = map[string]struct {
+	version, envVar, scheme string
+	port                    int
+}{
+	"postgres": {"16", "DATABASE_URL", "postgres", 5432},
+	"redis":    {"7.2", "REDIS_URL", "redis", 6379},
+	"mongo":    {"7.0", "MONGODB_URI", "mongodb", 27017},
+}
+
+var addonPlans = []AddonPlan{
+	{Name: "hobby", Type: "postgres", Memory: "256M", Storage: "1G", MaxConnections: 20, BackupDays: 7, MonthlyPrice: 0},
+	{Name: "standard", Type: "postgres", Memory: "1G", Storage: "20G", MaxConnections: 120, BackupDays: 14, MonthlyPrice: 25},
+	{Name: "premium", Type: "postgres", Memory: "4G", Storage: "100G", MaxConnections: 400, BackupDays: 30, HighAvailability: true, MonthlyPrice: 120},
+	{Name: "hobby", Type: "redis", Memory: "25M", Storage: "25M", MaxConnections: 20, BackupDays: 1, MonthlyPrice: 0},
+	{Name: "standard", Type: "redis", Memory: "256M", Storage: "256M", MaxConnections: 200, BackupDays: 7, MonthlyPrice: 15},
+	{Name: "premium", Type: "redis", Memory: "1G", Storage: "1G", MaxConnections: 1000, BackupDays: 14, HighAvailability: true, MonthlyPrice: 60},
+	{Name: "hobby", Type: "mongo", Memory: "256M", Storage: "1G", MaxConnections: 50, BackupDays: 7, MonthlyPrice: 0},
+	{Name: "standard", Type: "mongo", Memory: "1G", Storage: "20G", MaxConnections: 250, BackupDays: 14, MonthlyPrice: 30},
+	{Name: "premium", Type: "mongo", Memory: "4G", Storage: "100G", MaxConnections: 800, BackupDays: 30, HighAvailability: true, MonthlyPrice: 140},
+}
+
+var (
+	addonsMu     sync.Mutex
+	addons       = map[string]*addonRecord{}
+	addonBackups = map[string][]AddonBackup{}
+
+	envVarRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// findPlan returns a plan of an add-on type
+func findPlan(addonType, name string) (AddonPlan, bool) {
+	for _, plan := range addonPlans {
+		if plan.Type == addonType && plan.Name == name {
+			return plan, true
+		}
+	}
+	return AddonPlan{}, false
+}
+
+// view returns the add-on as listed, with its state at now
+func (a *addonRecord) view(now time.Time) Addon {
+	addon := a.Addon
+	addon.State = "available"
+	if now.Before(a.busyUntil) {
+		addon.State = a.busyState
+	}
+	return addon
+}
+
+// connectionURL returns the URL the attached app connects with
+func (a *addonRecord) connectionURL() string {
+	defaults := addonDefaults[a.Type]
+	host := fmt.Sprintf("%s.addons.ghaymah.internal:%d", a.Name, defaults.port)
+	if a.Type == "redis" {
+		return fmt.Sprintf("%s://%s:%s@%s", defaults.scheme, a.user, a.password, host)
+	}
+	return fmt.Sprintf("%s://%s:%s@%s/%s", defaults.scheme, a.user, a.password, host, a.App)
+}
+
+// addonBindings returns the variables injected into an app from its
+// add-ons, marking the ones env already sets
+func addonBindings(app string, env map[string]string) []AddonBinding {
+	addonsMu.Lock()
+	defer addonsMu.Unlock()
+	bindings := []AddonBinding{}
+	for _, addon := range addons {
+		if addon.App != app {
+			continue
+		}
+		_, overridden := env[addon.EnvVar]
+		bindings = append(bindings, AddonBinding{Addon: addon.Name, Type: addon.Type, EnvVar: addon.EnvVar, Overridden: overridden})
+	}
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].Addon < bindings[j].Addon })
+	return bindings
+}
+
+// lookupAddon returns the add-on named by the request, answering 404 if it doesn't exist.
+// The caller must hold addonsMu.
+func lookupAddon(w http.ResponseWriter, name string) (*addonRecord, bool) {
+	addon, ok := addons[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Add-on %s not found", name), http.StatusNotFound)
+	}
+	return addon, ok
+}
+
+// addonDataBytes simulates the size of the data of an add-on
+func addonDataBytes(a *addonRecord) float64 {
+	return usedBytes(a.Name, 1<<30)
+}
+
+func addonPlansHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	addonType := r.URL.Query().Get("type")
+	plans := []AddonPlan{}
+	for _, plan := range addonPlans {
+		if addonType == "" || plan.Type == addonType {
+			plans = append(plans, plan)
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"plans": plans})
+}
+
+func addonsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		app := r.URL.Query().Get("app")
+		now := time.Now()
+		addonsMu.Lock()
+		list := []Addon{}
+		for _, addon := range addons {
+			if app == "" || addon.App == app {
+				list = append(list, addon.view(now))
+			}
+		}
+		addonsMu.Unlock()
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		json.NewEncoder(w).Encode(map[string]interface{}{"addons": list})
+
+	case http.MethodPost:
+		var req struct {
+			Type   string `json:"type"`
+			Plan   string `json:"plan"`
+			App    string `json:"app"`
+			Name   string `json:"name"`
+			EnvVar string `json:"envVar"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defaults, ok := addonDefaults[req.Type]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown add-on type %q", req.Type), http.StatusBadRequest)
+			return
+		}
+		if _, ok := findPlan(req.Type, req.Plan); !ok {
+			http.Error(w, fmt.Sprintf("unknown %s plan %q (expected hobby, standard or premium)", req.Type, req.Plan), http.StatusBadRequest)
+			return
+		}
+		if req.App == "" {
+			http.Error(w, "App is required", http.StatusBadRequest)
+			return
+		}
+		if req.Name == "" {
+			req.Name = req.App + "-" + req.Type
+		}
+		if !volumeName.MatchString(req.Name) {
+			http.Error(w, fmt.Sprintf("invalid add-on name %q", req.Name), http.StatusBadRequest)
+			return
+		}
+		if req.EnvVar == "" {
+			req.EnvVar = defaults.envVar
+		}
+		if !envVarRe.MatchString(req.EnvVar) {
+			http.Error(w, fmt.Sprintf("invalid environment variable %q", req.EnvVar), http.StatusBadRequest)
+			return
+		}
+		region := regions[0].Name
+		if app, ok := findApp(req.App); ok && app.Region != "" {
+			region = app.Region
+		}
+
+		now := time.Now()
+		addonsMu.Lock()
+		defer addonsMu.Unlock()
+		if _, ok := addons[req.Name]; ok {
+			http.Error(w, fmt.Sprintf("add-on %s already exists", req.Name), http.StatusConflict)
+			return
+		}
+		for _, addon := range addons {
+			if addon.App == req.App && addon.EnvVar == req.EnvVar {
+				http.Error(w, fmt.Sprintf("%s of %s is already injected from add-on %s, choose another --env-var", req.EnvVar, req.App, addon.Name), http.StatusConflict)
+				return
+			}
+		}
+		addon := &addonRecord{
+			Addon: Addon{
+				Name:      req.Name,
+				Type:      req.Type,
+				Plan:      req.Plan,
+				Version:   defaults.version,
+				Region:    region,
+				App:       req.App,
+				EnvVar:    req.EnvVar,
+				CreatedAt: now,
+			},
+			user:      "u_" + randomHex(4),
+			password:  randomHex(12),
+			busyUntil: now.Add(provisionDuration),
+			busyState: "provisioning",
+		}
+		addons[req.Name] = addon
+		json.NewEncoder(w).Encode(addon.view(now))
+
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		addonsMu.Lock()
+		defer addonsMu.Unlock()
+		if _, ok := lookupAddon(w, name); !ok {
+			return
+		}
+		delete(addons, name)
+		delete(addonBackups, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func addonInfoHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	addonsMu.Lock()
+	defer addonsMu.Unlock()
+	addon, ok := lookupAddon(w, r.URL.Query().Get("name"))
+	if !ok {
+		return
+	}
+	info := addon.view(time.Now())
+	info.URL = addon.connectionURL()
+	json.NewEncoder(w).Encode(info)
+}
+
+func rotateAddonHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	addonsMu.Lock()
+	addon, ok := lookupAddon(w, req.Name)
+	if !ok {
+		addonsMu.Unlock()
+		return
+	}
+	if view := addon.view(now); view.State != "available" {
+		addonsMu.Unlock()
+		http.Error(w, fmt.Sprintf("it is %s", view.State), http.StatusConflict)
+		return
+	}
+	addon.password = randomHex(12)
+	addon.CredentialsRotated = &now
+	rotated := addon.view(now)
+	addonsMu.Unlock()
+
+	// Restart the attached app one instance at a time with the new URL
+	if _, ok := findApp(rotated.App); ok && appState(rotated.App) == "running" {
+		lifecycleMu.Lock()
+		transitions[rotated.App] = &transition{state: "restarting", target: "running", rolling: true, started: now, until: now.Add(mockInstances * restartDuration)}
+		lifecycleMu.Unlock()
+		emitEvent(rotated.App, "config.changed", mockUser, fmt.Sprintf("Credentials of add-on %s rotated: %s updated", rotated.Name, rotated.EnvVar), nil)
+		emitEvent(rotated.App, "app.restarted", platformActor, fmt.Sprintf("Rolling restart of %d instances", mockInstances), map[string]interface{}{"rolling": true})
+	}
+
+	json.NewEncoder(w).Encode(rotated)
+}
+
+func addonBackupsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		addonsMu.Lock()
+		defer addonsMu.Unlock()
+		addon, ok := lookupAddon(w, name)
+		if !ok {
+			return
+		}
+		list := []AddonBackup{}
+		for i := len(addonBackups[name]) - 1; i >= 0; i-- {
+			list = append(list, addonBackups[name][i])
+		}
+		// Daily backups run at 03:00 UTC once the add-on exists
+		for day := time.Now().UTC().Truncate(24 * time.Hour).Add(3 * time.Hour); day.After(addon.CreatedAt); day = day.Add(-24 * time.Hour) {
+			if day.After(time.Now()) {
+				continue
+			}
+			list = append(list, AddonBackup{ID: "bk-daily-" + day.Format("20060102"), Addon: name, Trigger: "scheduled", SizeBytes: addonDataBytes(addon), CreatedAt: day})
+		}
+		sort.SliceStable(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+		json.NewEncoder(w).Encode(map[string]interface{}{"backups": list})
+
+	case http.MethodPost:
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		now := time.Now()
+		addonsMu.Lock()
+		defer addonsMu.Unlock()
+		addon, ok := lookupAddon(w, req.Name)
+		if !ok {
+			return
+		}
+		if view := addon.view(now); view.State != "available" {
+			http.Error(w, fmt.Sprintf("it is %s", view.State), http.StatusConflict)
+			return
+		}
+		backup := AddonBackup{
+			ID:        "bk-" + randomHex(4),
+			Addon:     addon.Name,
+			Trigger:   "manual",
+			SizeBytes: addonDataBytes(addon),
+			CreatedAt: now,
+		}
+		addonBackups[addon.Name] = append(addonBackups[addon.Name], backup)
+		json.NewEncoder(w).Encode(backup)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func restoreAddonHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name   string `json:"name"`
+		Backup string `json:"backup"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	addonsMu.Lock()
+	defer addonsMu.Unlock()
+	addon, ok := lookupAddon(w, req.Name)
+	if !ok {
+		return
+	}
+	found := false
+	for _, backup := range addonBackups[req.Name] {
+		found = found || backup.ID == req.Backup
+	}
+	if day, ok := strings.CutPrefix(req.Backup, "bk-daily-"); ok {
+		if daily, err := time.Parse("20060102", day); err == nil {
+			at := daily.Add(3 * time.Hour)
+			found = at.After(addon.CreatedAt) && at.Before(now)
+		}
+	}
+	if !found {
+		http.Error(w, fmt.Sprintf("Backup %s of add-on %s not found", req.Backup, req.Name), http.StatusNotFound)
+		return
+	}
+	if view := addon.view(now); view.State != "available" {
+		http.Error(w, fmt.Sprintf("it is %s", view.State), http.StatusConflict)
+		return
+	}
+	addon.busyUntil, addon.busyState = now.Add(restoreDuration), "restoring"
+	json.NewEncoder(w).Encode(addon.view(now))
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type AlertRule struct {
+	ID        string    `json:"id"`
+	App       string    `json:"app"`
+	Metric    string    `json:"metric"`
+	Operator  string    `json:"operator"`
+	Threshold float64   `json:"threshold,omitempty"`
+	Value     string    `json:"value,omitempty"`
+	For       string    `json:"for,omitempty"`
+	Channels  []string  `json:"channels"`
+	State     string    `json:"state,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type NotificationChannel struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	URL       string    `json:"url,omitempty"`
+	To        []string  `json:"to,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ChannelDelivery struct {
+	Channel   string `json:"channel"`
+	Delivered bool   `json:"delivered"`
+	Error     string `json:"error,omitempty"`
+}
+
+var (
+	alertsMu    sync.Mutex
+	alertRules  = map[string]*AlertRule{}
+	channels    = map[string]*NotificationChannel{}
+	nextAlertID = 1
+)
+
+// notifyClient delivers webhook notifications
+var notifyClient = &http.Client{Timeout: 5 * time.Second}
+
+// evaluate compares the rule with the simulated status of its app
+func (rule *AlertRule) evaluate() string {
+	app := getApp(rule.App)
+
+	var value string
+	var number float64
+	switch rule.Metric {
+	case "cpu":
+		number = 25
+	case "memory":
+		number = 50
+	case "storage":
+		number = 12.5
+	case "state":
+		value = "running"
+	case "health":
+		health := healthFor(app)
+		if health == nil {
+			return "ok"
+		}
+		value = health.Status
+	}
+
+	var firing bool
+	switch rule.Operator {
+	case "==":
+		firing = value == rule.Value
+	case "!=":
+		firing = value != rule.Value
+	case ">":
+		firing = number > rule.Threshold
+	case ">=":
+		firing = number >= rule.Threshold
+	case "<":
+		firing = number < rule.Threshold
+	case "<=":
+		firing = number <= rule.Threshold
+	}
+	if firing {
+		return "firing"
+	}
+	return "ok"
+}
+
+// condition formats the condition of the rule for notifications
+func (rule *AlertRule) condition() string {
+	if rule.Value != "" {
+		return fmt.Sprintf("%s %s %s", rule.Metric, rule.Operator, rule.Value)
+	}
+	return fmt.Sprintf("%s %s %g%%", rule.Metric, rule.Operator, rule.Threshold)
+}
+
+// notify sends a sample notification of the rule to a channel
+func notify(channel *NotificationChannel, rule *AlertRule) error {
+	text := fmt.Sprintf("[TEST] Alert %s on %s: %s", rule.ID, rule.App, rule.condition())
+
+	var payload interface{}
+	switch channel.Type {
+	case "email":
+		log.Printf("Mock email to %s: %s", strings.Join(channel.To, ", "), text)
+		return nil
+	case "slack":
+		payload = map[string]string{"text": text}
+	default:
+		payload = map[string]interface{}{
+			"test":      true,
+			"status":    "firing",
+			"alert":     rule.ID,
+			"app":       rule.App,
+			"condition": rule.condition(),
+			"message":   text,
+			"firedAt":   time.Now(),
+		}
+	}
+
+	body, _ := json.Marshal(payload)
+	resp, err := notifyClient.Post(channel.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func alertsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	alertsMu.Lock()
+	defer alertsMu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		app := r.URL.Query().Get("app")
+		list := []AlertRule{}
+		for _, rule := range alertRules {
+			if app != "" && rule.App != app {
+				continue
+			}
+			listed := *rule
+			listed.State = rule.evaluate()
+			list = append(list, listed)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+		json.NewEncoder(w).Encode(map[string]interface{}{"alerts": list})
+	case http.MethodPost:
+		var rule AlertRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil || rule.App == "" || rule.Metric == "" || rule.Operator == "" {
+			http.Error(w, "App, metric and operator are required", http.StatusBadRequest)
+			return
+		}
+		if len(rule.Channels) == 0 {
+			http.Error(w, "At least one channel is required", http.StatusBadRequest)
+			return
+		}
+		for _, name := range rule.Channels {
+			if _, ok := channels[name]; !ok {
+				http.Error(w, fmt.Sprintf("Unknown notification channel %s", name), http.StatusBadRequest)
+				return
+			}
+		}
+		rule.ID = fmt.Sprintf("alert-%d", nextAlertID)
+		nextAlertID++
+		rule.CreatedAt = time.Now()
+		alertRules[rule.ID] = &rule
+		json.NewEncoder(w).Encode(rule)
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if _, ok := alertRules[id]; !ok {
+			http.Error(w, fmt.Sprintf("Alert %s not found", id), http.StatusNotFound)
+			return
+		}
+		delete(alertRules, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func testAlertHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	alertsMu.Lock()
+	rule, ok := alertRules[req.ID]
+	var targets []*NotificationChannel
+	if ok {
+		for _, name := range rule.Channels {
+			if channel, ok := channels[name]; ok {
+				targets = append(targets, channel)
+			}
+		}
+	}
+	alertsMu.Unlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("Alert %s not found", req.ID), http.StatusNotFound)
+		return
+	}
+
+	deliveries := []ChannelDelivery{}
+	for _, channel := range targets {
+		delivery := ChannelDelivery{Channel: channel.Name, Delivered: true}
+		if err := notify(channel, rule); err != nil {
+			delivery.Delivered = false
+			delivery.Error = err.Error()
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"deliveries": deliveries})
+}
+
+func notificationsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	alertsMu.Lock()
+	defer alertsMu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		list := []NotificationChannel{}
+		for _, channel := range channels {
+			list = append(list, *channel)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		json.NewEncoder(w).Encode(map[string]interface{}{"channels": list})
+	case http.MethodPost:
+		var channel NotificationChannel
+		if err := json.NewDecoder(r.Body).Decode(&channel); err != nil || channel.Name == "" {
+			http.Error(w, "Name is required", http.StatusBadRequest)
+			return
+		}
+		switch channel.Type {
+		case "webhook", "slack":
+			if channel.URL == "" {
+				http.Error(w, "URL is required", http.StatusBadRequest)
+				return
+			}
+		case "email":
+			if len(channel.To) == 0 {
+				http.Error(w, "At least one address is required", http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(w, fmt.Sprintf("Unknown channel type %q", channel.Type), http.StatusBadRequest)
+			return
+		}
+		if _, ok := channels[channel.Name]; ok {
+			http.Error(w, fmt.Sprintf("Notification channel %s already exists", channel.Name), http.StatusConflict)
+			return
+		}
+		channel.CreatedAt = time.Now()
+		channels[channel.Name] = &channel
+		json.NewEncoder(w).Encode(channel)
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if _, ok := channels[name]; !ok {
+			http.Error(w, fmt.Sprintf("Notification channel %s not found", name), http.StatusNotFound)
+			return
+		}
+		var users []string
+		for _, rule := range alertRules {
+			for _, c := range rule.Channels {
+				if c == name {
+					users = append(users, rule.ID)
+				}
+			}
+		}
+		if len(users) > 0 {
+			sort.Strings(users)
+			http.Error(w, fmt.Sprintf("Notification channel %s is used by %s", name, strings.Join(users, ", ")), http.StatusConflict)
+			return
+		}
+		delete(channels, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type HealthCheck struct {
+	Type               string   `json:"type"`
+	Path               string   `json:"path,omitempty"`
+	Port               int      `json:"port,omitempty"`
+	Command            []string `json:"command,omitempty"`
+	Interval           string   `json:"interval,omitempty"`
+	Timeout            string   `json:"timeout,omitempty"`
+	HealthyThreshold   int      `json:"healthyThreshold,omitempty"`
+	UnhealthyThreshold int      `json:"unhealthyThreshold,omitempty"`
+	GracePeriod        string   `json:"gracePeriod,omitempty"`
+}
+
+type InstanceHealth struct {
+	Instance            string     `json:"instance"`
+	Status              string     `json:"status"`
+	LastCheck           time.Time  `json:"lastCheck"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastFailure         string     `json:"lastFailure,omitempty"`
+	LastFailureAt       *time.Time `json:"lastFailureAt,omitempty"`
+}
+
+type HealthStatus struct {
+	Status    string           `json:"status"`
+	Instances []InstanceHealth `json:"instances"`
+}
+
+type ReleaseInfo struct {
+	Version     int       `json:"version"`
+	Image       string    `json:"image"`
+	ImageDigest string    `json:"imageDigest"`
+	DeployedAt  time.Time `json:"deployedAt"`
+}
+
+type InstanceStatus struct {
+	ID             string    `json:"id"`
+	State          string    `json:"state"`
+	Restarts       int       `json:"restarts"`
+	StartedAt      time.Time `json:"startedAt"`
+	LastExitReason string    `json:"lastExitReason,omitempty"`
+}
+
+type PlatformEvent struct {
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Instance  string    `json:"instance,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type ResourceQuantity struct {
+	Used  float64 `json:"used"`
+	Limit float64 `json:"limit"`
+}
+
+type ResourceUsageDetail struct {
+	CPU     ResourceQuantity `json:"cpu"`
+	Memory  ResourceQuantity `json:"memory"`
+	Storage ResourceQuantity `json:"storage"`
+}
+
+// App is the mock server's record of a deployed application
+type App struct {
+	Name        string
+	Image       string
+	Version     int
+	Region      string
+	Regions     []string
+	Labels      map[string]string
+	HealthCheck *HealthCheck
+	Jobs        map[string]Job
+	DeployedAt  time.Time
+}
+
+// appURL returns the default public URL of an app
+func appURL(name string) string {
+	return fmt.Sprintf("https://%s.ghaymah.app", name)
+}
+
+var (
+	appsMu sync.Mutex
+	apps   = map[string]*App{}
+)
+
+// saveApp records a deployment as the next release of the app and returns
+// the release it replaces, if any. When expectedVersion is set, the
+// deployment is rejected unless it is the current release (0 for none).
+func saveApp(app *App, expectedVersion *int) (*App, error) {
+	appsMu.Lock()
+	defer appsMu.Unlock()
+	current := 0
+	prev, ok := apps[app.Name]
+	if ok {
+		current = prev.Version
+	}
+	if expectedVersion != nil && *expectedVersion != current {
+		if current == 0 {
+			return nil, fmt.Errorf("expected release v%d but %s does not exist", *expectedVersion, app.Name)
+		}
+		return nil, fmt.Errorf("expected release v%d but v%d was deployed at %s", *expectedVersion, current, prev.DeployedAt.Format(time.RFC3339))
+	}
+	app.Version = current + 1
+	apps[app.Name] = app
+	return prev, nil
+}
+
+// findApp returns a deployed application
+func findApp(name string) (*App, bool) {
+	appsMu.Lock()
+	defer appsMu.Unlock()
+	app, ok := apps[name]
+	return app, ok
+}
+
+// deleteApp removes an app, reporting whether it existed
+func deleteApp(name string) bool {
+	appsMu.Lock()
+	defer appsMu.Unlock()
+	if _, ok := apps[name]; !ok {
+		return false
+	}
+	delete(apps, name)
+	return true
+}
+
+type AppSummary struct {
+	Name       string            `json:"name"`
+	State      string            `json:"state"`
+	Region     string            `json:"region,omitempty"`
+	Regions    []string          `json:"regions,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	DeployedAt time.Time         `json:"deployedAt"`
+}
+
+// listApps returns the deployed applications having all the given labels,
+// sorted by name
+func listApps(selector map[string]string) []AppSummary {
+	appsMu.Lock()
+	defer appsMu.Unlock()
+
+	list := []AppSummary{}
+	for _, app := range apps {
+		if !matchLabels(app.Labels, selector) {
+			continue
+		}
+		list = append(list, AppSummary{
+			Name:       app.Name,
+			State:      appState(app.Name),
+			Region:     app.Region,
+			Regions:    app.Regions,
+			URL:        appURL(app.Name),
+			Labels:     app.Labels,
+			DeployedAt: app.DeployedAt,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// matchLabels reports whether labels contain every key and value of selector
+func matchLabels(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSelector reads label=key=value query parameters
+func parseSelector(r *http.Request) (map[string]string, error) {
+	selector := map[string]string{}
+	for _, label := range r.URL.Query()["label"] {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label selector %q", label)
+		}
+		selector[key] = value
+	}
+	return selector, nil
+}
+
+// getApp returns a deployed application, or a default one for unknown names
+func getApp(name string) *App {
+	appsMu.Lock()
+	defer appsMu.Unlock()
+	if app, ok := apps[name]; ok {
+		return app
+	}
+	return &App{
+		Name:       name,
+		Image:      name + ":latest",
+		Version:    1,
+		DeployedAt: time.Now().Add(-2*time.Hour - 15*time.Minute),
+	}
+}
+
+// healthFor simulates health check results for the app's instances.
+// Apps checking the path /fail report a failing second instance.
+func healthFor(app *App) *HealthStatus {
+	if app.HealthCheck == nil {
+		return nil
+	}
+
+	now := time.Now()
+	health := &HealthStatus{Status: "healthy"}
+	for i := 1; i <= 2; i++ {
+		inst := InstanceHealth{
+			Instance:  fmt.Sprintf("%s-%d", app.Name, i),
+			Status:    "healthy",
+			LastCheck: now.Add(-5 * time.Second),
+		}
+		if app.HealthCheck.Path == "/fail" && i == 2 {
+			inst.Status = "unhealthy"
+			inst.ConsecutiveFailures = 3
+			inst.LastFailure = fmt.Sprintf("GET %s on port %d returned 503", app.HealthCheck.Path, app.HealthCheck.Port)
+			failedAt := inst.LastCheck
+			inst.LastFailureAt = &failedAt
+			health.Status = "degraded"
+		}
+		health.Instances = append(health.Instances, inst)
+	}
+	return health
+}
+
+// releaseFor describes the running release of the app
+func releaseFor(app *App) *ReleaseInfo {
+	return &ReleaseInfo{
+		Version:     app.Version,
+		Image:       app.Image,
+		ImageDigest: fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(app.Image))),
+		DeployedAt:  app.DeployedAt,
+	}
+}
+
+// instancesFor simulates two instances, the second one having been OOM killed once
+func instancesFor(app *App) []InstanceStatus {
+	return []InstanceStatus{
+		{ID: app.Name + "-1", State: instanceState(app.Name, 0), StartedAt: app.DeployedAt},
+		{ID: app.Name + "-2", State: instanceState(app.Name, 1), Restarts: 1, StartedAt: time.Now().Add(-10 * time.Minute), LastExitReason: "OOMKilled"},
+	}
+}
+
+// eventsFor simulates the recent platform events of the app
+func eventsFor(app *App) []PlatformEvent {
+	return []PlatformEvent{
+		{Type: "Normal", Reason: "Scheduled", Message: "Instances scheduled on 2 nodes", Timestamp: app.DeployedAt},
+		{Type: "Normal", Reason: "Started", Message: "Release v" + fmt.Sprint(app.Version) + " started", Timestamp: app.DeployedAt.Add(5 * time.Second)},
+		{Type: "Warning", Reason: "OOMKilled", Message: "Container exceeded its memory limit of 512MiB", Instance: app.Name + "-2", Timestamp: time.Now().Add(-10 * time.Minute)},
+	}
+}
+
+// usageFor simulates resource usage against limits in absolute units
+func usageFor(app *App) *ResourceUsageDetail {
+	return &ResourceUsageDetail{
+		CPU:     ResourceQuantity{Used: 0.25, Limit: 1},
+		Memory:  ResourceQuantity{Used: 256 << 20, Limit: 512 << 20},
+		Storage: ResourceQuantity{Used: 128 << 20, Limit: 1 << 30},
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type DNSRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Certificate struct {
+	Status    string     `json:"status"`
+	Issuer    string     `json:"issuer,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type Domain struct {
+	Name        string       `json:"name"`
+	Status      string       `json:"status"`
+	DNSRecords  []DNSRecord  `json:"dnsRecords"`
+	Certificate *Certificate `json:"certificate,omitempty"`
+	VerifiedAt  *time.Time   `json:"verifiedAt,omitempty"`
+	Message     string       `json:"message,omitempty"`
+}
+
+type DomainRequest struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
+}
+
+var (
+	domainsMu sync.Mutex
+	domains   = map[string]map[string]*Domain{}
+)
+
+// addDomain registers a pending domain for an app, keeping an existing entry
+func addDomain(app, name string) *Domain {
+	domainsMu.Lock()
+	defer domainsMu.Unlock()
+
+	if domains[app] == nil {
+		domains[app] = map[string]*Domain{}
+	}
+	if d, ok := domains[app][name]; ok {
+		return d
+	}
+
+	d := &Domain{
+		Name:   name,
+		Status: "pending",
+		DNSRecords: []DNSRecord{
+			{Type: "CNAME", Name: name, Value: fmt.Sprintf("%s.apps.ghaymah.cloud", app)},
+			{Type: "TXT", Name: "_ghaymah-challenge." + name, Value: fmt.Sprintf("ghaymah-verify=%s-%d", app, len(name))},
+		},
+		Certificate: &Certificate{Status: "pending"},
+	}
+	domains[app][name] = d
+	return d
+}
+
+func domainsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "Name parameter is required", http.StatusBadRequest)
+			return
+		}
+
+		domainsMu.Lock()
+		list := []*Domain{}
+		for _, d := range domains[name] {
+			list = append(list, d)
+		}
+		domainsMu.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"domains": list})
+
+	case http.MethodPost:
+		var req DomainRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Name == "" || req.Domain == "" {
+			http.Error(w, "Name and domain are required", http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(addDomain(req.Name, req.Domain))
+
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		domain := r.URL.Query().Get("domain")
+
+		domainsMu.Lock()
+		_, ok := domains[name][domain]
+		delete(domains[name], domain)
+		domainsMu.Unlock()
+
+		if !ok {
+			http.Error(w, "Domain not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func verifyDomainHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req DomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	domainsMu.Lock()
+	defer domainsMu.Unlock()
+
+	d, ok := domains[req.Name][req.Domain]
+	if !ok {
+		http.Error(w, "Domain not found", http.StatusNotFound)
+		return
+	}
+
+	// Domains under .invalid simulate missing DNS records
+	if strings.HasSuffix(d.Name, ".invalid") {
+		d.Status = "failed"
+		d.Message = "CNAME record not found"
+	} else {
+		d.Status = "verified"
+		d.Message = ""
+		now := time.Now()
+		expires := now.Add(90 * 24 * time.Hour)
+		d.VerifiedAt = &now
+		d.Certificate = &Certificate{
+			Status:    "issued",
+			Issuer:    "Let's Encrypt R3",
+			ExpiresAt: &expires,
+		}
+	}
+
+	json.NewEncoder(w).Encode(d)
+}
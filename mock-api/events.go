@@ -0,0 +1,150 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type AppEvent struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	App       string                 `json:"app"`
+	Actor     string                 `json:"actor"`
+	Message   string                 `json:"message"`
+	Instance  string                 `json:"instance,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// platformActor is the actor of events caused by the platform itself
+const platformActor = "platform"
+
+// maxEvents is the number of events kept per app
+const maxEvents = 1000
+
+var (
+	eventsMu sync.Mutex
+	eventLog = map[string][]AppEvent{}
+	serverUp = time.Now()
+)
+
+// recordEvent appends an event to the activity log of its app
+func recordEvent(event AppEvent) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	events := append(eventLog[event.App], event)
+	if len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+	eventLog[event.App] = events
+}
+
+// historyFor simulates the platform activity of an app before the mock
+// server started, anchored on the start time so that it is stable
+func historyFor(app string) []AppEvent {
+	event := func(ago time.Duration, eventType, actor, instance, message string) AppEvent {
+		t := serverUp.Add(-ago).Truncate(time.Second)
+		sum := sha256.Sum256([]byte(app + eventType + t.String()))
+		return AppEvent{
+			ID:        "evt_" + hex.EncodeToString(sum[:8]),
+			Type:      eventType,
+			App:       app,
+			Actor:     actor,
+			Instance:  instance,
+			Message:   message,
+			Timestamp: t,
+		}
+	}
+	return []AppEvent{
+		event(26*time.Hour, "deploy.started", mockUser, "", "Release v1 of "+app+":latest started"),
+		event(26*time.Hour-40*time.Second, "deploy.succeeded", platformActor, "", "Release v1 is serving all traffic"),
+		event(90*time.Minute, "config.changed", mockUser, "", "Environment variables updated: LOG_LEVEL"),
+		event(time.Hour, "app.scaled", mockUser, "", "Scaled from 1 to 2 instances"),
+		event(10*time.Minute, "instance.oom_killed", platformActor, app+"-2", "Container exceeded its memory limit of 512MiB"),
+		event(10*time.Minute-5*time.Second, "instance.restarted", platformActor, app+"-2", "Instance restarted after OOMKilled (restart 1)"),
+	}
+}
+
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("name")
+	if name == "" {
+		http.Error(w, "Name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	var since, until time.Time
+	for param, t := range map[string]*time.Time{"since": &since, "until": &until} {
+		if v := query.Get(param); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return
+			}
+			*t = parsed
+		}
+	}
+	limit := 100
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			http.Error(w, "Limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	types := query["type"]
+
+	eventsMu.Lock()
+	all := append(historyFor(name), eventLog[name]...)
+	eventsMu.Unlock()
+
+	// A type matches itself and, without a dot, its whole category (deploy matches deploy.started)
+	matches := func(eventType string) bool {
+		if len(types) == 0 {
+			return true
+		}
+		for _, t := range types {
+			if eventType == t || strings.HasPrefix(eventType, t+".") {
+				return true
+			}
+		}
+		return false
+	}
+
+	events := []AppEvent{}
+	for _, e := range all {
+		if !since.IsZero() && e.Timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !e.Timestamp.Before(until) {
+			continue
+		}
+		if matches(e.Type) {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+	if len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"events": events})
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Job struct {
+	Schedule  string           `json:"schedule"`
+	Command   []string         `json:"command"`
+	Image     string           `json:"image,omitempty"`
+	Resources *ResourceRequest `json:"resources,omitempty"`
+	Timeout   string           `json:"timeout,omitempty"`
+}
+
+type ResourceRequest struct {
+	CPU     string `json:"cpu,omitempty"`
+	Memory  string `json:"memory,omitempty"`
+	Storage string `json:"storage,omitempty"`
+}
+
+// JobInfo is a scheduled job as listed by the API
+type JobInfo struct {
+	Name string `json:"name"`
+	Job
+	LastRun *JobRun `json:"lastRun,omitempty"`
+}
+
+type JobRun struct {
+	ID         string     `json:"id"`
+	App        string     `json:"app"`
+	Job        string     `json:"job,omitempty"`
+	Command    []string   `json:"command"`
+	Image      string     `json:"image"`
+	Trigger    string     `json:"trigger"`
+	State      string     `json:"state"`
+	ExitCode   *int       `json:"exitCode,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+type LogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// outputLine is a line a simulated run prints, at an offset from its start
+type outputLine struct {
+	at      time.Duration
+	message string
+}
+
+// run is the mock server's record of a job run or task
+type run struct {
+	JobRun
+	output []outputLine
+	failed bool
+}
+
+// runStartDelay is how long a simulated run stays pending
+const runStartDelay = 500 * time.Millisecond
+
+var (
+	runsMu sync.Mutex
+	runs   = map[string][]*run{}
+	// seeded records the jobs given a past scheduled run, by app and job name
+	seeded = map[string]bool{}
+)
+
+// startRun records a new run of a command, which fails if it mentions "fail"
+func startRun(app, job, trigger, image string, command []string, started time.Time) *run {
+	r := &run{
+		JobRun: JobRun{
+			ID:        "run-" + randomHex(4),
+			App:       app,
+			Job:       job,
+			Command:   command,
+			Image:     image,
+			Trigger:   trigger,
+			State:     "pending",
+			StartedAt: started,
+		},
+		failed: strings.Contains(strings.Join(command, " "), "fail"),
+	}
+	r.output = outputFor(r)
+
+	runsMu.Lock()
+	runs[app] = append(runs[app], r)
+	runsMu.Unlock()
+	return r
+}
+
+// outputFor simulates the output of a run
+func outputFor(r *run) []outputLine {
+	command := strings.Join(r.Command, " ")
+	lines := []outputLine{
+		{0, fmt.Sprintf("Pulling image %s", r.Image)},
+		{runStartDelay, fmt.Sprintf("$ %s", command)},
+	}
+	var body []string
+	switch {
+	case strings.Contains(command, "migrate"):
+		body = []string{"Applying migration 0042_add_orders_index... ok", "Applying migration 0043_backfill_totals... ok", "2 migrations applied"}
+	case r.Job != "":
+		body = []string{"Processing batch 1/3", "Processing batch 2/3", "Processing batch 3/3", "Done"}
+	default:
+		body = []string{"Working...", "Done"}
+	}
+	if r.failed {
+		body = append(body[:1], fmt.Sprintf("Error: %s exited with status 1", r.Command[0]))
+	}
+	for i, message := range body {
+		lines = append(lines, outputLine{runStartDelay + time.Duration(i+1)*time.Second, message})
+	}
+	return lines
+}
+
+// advance updates the state of a run at now
+func (r *run) advance(now time.Time) {
+	if r.FinishedAt != nil {
+		return
+	}
+	elapsed := now.Sub(r.StartedAt)
+	end := r.output[len(r.output)-1].at
+	switch {
+	case elapsed < runStartDelay:
+		r.State = "pending"
+	case elapsed < end:
+		r.State = "running"
+	default:
+		finished := r.StartedAt.Add(end)
+		code := 0
+		r.State = "succeeded"
+		if r.failed {
+			code = 1
+			r.State = "failed"
+			r.Message = "command exited with a non-zero status"
+		}
+		r.ExitCode = &code
+		r.FinishedAt = &finished
+	}
+}
+
+// seedRuns gives each job of an app a past scheduled run, as if it had
+// been running for a while
+func seedRuns(app *App) {
+	for name, job := range app.Jobs {
+		key := app.Name + "/" + name
+		runsMu.Lock()
+		done := seeded[key]
+		seeded[key] = true
+		runsMu.Unlock()
+		if done {
+			continue
+		}
+		startRun(app.Name, name, "schedule", jobImage(app, job), job.Command, time.Now().Add(-24*time.Hour).Truncate(time.Hour))
+	}
+}
+
+// jobImage returns the image a job runs in
+func jobImage(app *App, job Job) string {
+	if job.Image != "" {
+		return job.Image
+	}
+	return app.Image
+}
+
+// runsOf returns the up to date runs of an app, most recent first
+func runsOf(app string) []*run {
+	runsMu.Lock()
+	defer runsMu.Unlock()
+	return runsOfLocked(app)
+}
+
+// runsOfLocked is runsOf for callers holding runsMu
+func runsOfLocked(app string) []*run {
+	now := time.Now()
+	list := make([]*run, len(runs[app]))
+	for i, r := range runs[app] {
+		r.advance(now)
+		list[i] = r
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].StartedAt.After(list[j].StartedAt) })
+	return list
+}
+
+// deployedApp returns the app named by the request, answering 404 if it isn't deployed
+func deployedApp(w http.ResponseWriter, name string) (*App, bool) {
+	if name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return nil, false
+	}
+	app, ok := findApp(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Application %s not found", name), http.StatusNotFound)
+		return nil, false
+	}
+	return app, true
+}
+
+func jobsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	app, ok := deployedApp(w, r.URL.Query().Get("name"))
+	if !ok {
+		return
+	}
+	seedRuns(app)
+
+	history := runsOf(app.Name)
+	jobs := []JobInfo{}
+	for name, job := range app.Jobs {
+		info := JobInfo{Name: name, Job: job}
+		for _, ro := range history {
+			if ro.Job == name {
+				last := ro.JobRun
+				info.LastRun = &last
+				break
+			}
+		}
+		jobs = append(jobs, info)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"jobs": jobs})
+}
+
+func runJobHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+		Job  string `json:"job"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	app, ok := deployedApp(w, req.Name)
+	if !ok {
+		return
+	}
+	job, ok := app.Jobs[req.Job]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Job %s not found", req.Job), http.StatusNotFound)
+		return
+	}
+	seedRuns(app)
+
+	ro := startRun(app.Name, req.Job, "manual", jobImage(app, job), job.Command, time.Now())
+	json.NewEncoder(w).Encode(ro.JobRun)
+}
+
+func jobRunsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	app, ok := deployedApp(w, query.Get("name"))
+	if !ok {
+		return
+	}
+	seedRuns(app)
+
+	job := query.Get("job")
+	if _, ok := app.Jobs[job]; job != "" && !ok {
+		http.Error(w, fmt.Sprintf("Job %s not found", job), http.StatusNotFound)
+		return
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	list := []JobRun{}
+	runsMu.Lock()
+	for _, ro := range runsOfLocked(app.Name) {
+		if job != "" && ro.Job != job {
+			continue
+		}
+		if limit > 0 && len(list) >= limit {
+			break
+		}
+		list = append(list, ro.JobRun)
+	}
+	runsMu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"runs": list})
+}
+
+func runLogsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	app, ok := deployedApp(w, query.Get("name"))
+	if !ok {
+		return
+	}
+	cursor, _ := strconv.Atoi(query.Get("cursor"))
+
+	runsMu.Lock()
+	defer runsMu.Unlock()
+	for _, ro := range runsOfLocked(app.Name) {
+		if ro.ID != query.Get("run") {
+			continue
+		}
+		entries := []LogEntry{}
+		next := cursor
+		for i, line := range ro.output {
+			at := ro.StartedAt.Add(line.at)
+			if i < cursor || at.After(time.Now()) {
+				continue
+			}
+			entries = append(entries, LogEntry{Timestamp: at, Message: line.message})
+			next = i + 1
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"run": ro.JobRun, "entries": entries, "next": next})
+		return
+	}
+	http.Error(w, fmt.Sprintf("Run %s not found", query.Get("run")), http.StatusNotFound)
+}
+
+func taskHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name      string            `json:"name"`
+		Command   []string          `json:"command"`
+		Image     string            `json:"image"`
+		Resources *ResourceRequest  `json:"resources"`
+		Env       map[string]string `json:"env"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.Command) == 0 {
+		http.Error(w, "Command is required", http.StatusBadRequest)
+		return
+	}
+
+	app, ok := deployedApp(w, req.Name)
+	if !ok {
+		return
+	}
+	image := req.Image
+	if image == "" {
+		image = app.Image
+	}
+
+	ro := startRun(app.Name, "", "task", image, req.Command, time.Now())
+	json.NewEncoder(w).Encode(ro.JobRun)
+}
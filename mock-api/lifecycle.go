@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// transition is a lifecycle operation in progress: the app is in state
+// until the given time, then in target
+type transition struct {
+	state   string
+	target  string
+	rolling bool
+	started time.Time
+	until   time.Time
+}
+
+// Simulated durations of lifecycle operations
+const (
+	restartDuration = 3 * time.Second
+	stopDuration    = 2 * time.Second
+	startDuration   = 3 * time.Second
+)
+
+// mockInstances is the number of instances of each simulated app
+const mockInstances = 2
+
+var (
+	lifecycleMu sync.Mutex
+	transitions = map[string]*transition{}
+)
+
+// appState returns the current lifecycle state of an app
+func appState(name string) string {
+	lifecycleMu.Lock()
+	defer lifecycleMu.Unlock()
+	t, ok := transitions[name]
+	if !ok {
+		return "running"
+	}
+	if time.Now().Before(t.until) {
+		return t.state
+	}
+	return t.target
+}
+
+// instanceState returns the lifecycle state of the i-th instance (from 0) of
+// an app. A rolling restart restarts the instances one after the other.
+func instanceState(name string, i int) string {
+	state := appState(name)
+	lifecycleMu.Lock()
+	defer lifecycleMu.Unlock()
+	t, ok := transitions[name]
+	if !ok || state != "restarting" || !t.rolling {
+		return state
+	}
+	start := t.started.Add(time.Duration(i) * restartDuration)
+	if now := time.Now(); now.After(start) && now.Before(start.Add(restartDuration)) {
+		return "restarting"
+	}
+	return "running"
+}
+
+// stateMessage describes an app in the given lifecycle state
+func stateMessage(state string) string {
+	switch state {
+	case "restarting":
+		return "Application is restarting"
+	case "stopping":
+		return "Application is stopping"
+	case "stopped":
+		return "Application is stopped"
+	case "starting":
+		return "Application is starting"
+	}
+	return "Application is running normally"
+}
+
+// lifecycleHandler handles restart, stop and start requests
+func lifecycleHandler(action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !validateToken(r) {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req struct {
+			Name    string `json:"name"`
+			Rolling bool   `json:"rolling"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+			http.Error(w, "Name is required", http.StatusBadRequest)
+			return
+		}
+
+		current := appState(req.Name)
+		now := time.Now()
+		next := &transition{rolling: req.Rolling, started: now}
+		event, message := "", ""
+		var data map[string]interface{}
+		switch action {
+		case "restart":
+			if current == "stopped" || current == "stopping" {
+				http.Error(w, fmt.Sprintf("%s is %s, start it instead", req.Name, current), http.StatusConflict)
+				return
+			}
+			next.state, next.target, next.until = "restarting", "running", now.Add(restartDuration)
+			event, message = "app.restarted", "All instances restarted"
+			data = map[string]interface{}{"rolling": req.Rolling}
+			if req.Rolling {
+				next.until = now.Add(mockInstances * restartDuration)
+				message = fmt.Sprintf("Rolling restart of %d instances", mockInstances)
+			}
+		case "stop":
+			if current == "stopped" || current == "stopping" {
+				json.NewEncoder(w).Encode(AppSummary{Name: req.Name, State: current})
+				return
+			}
+			next.state, next.target, next.until = "stopping", "stopped", now.Add(stopDuration)
+			event, message = "app.stopped", "Application stopped"
+		case "start":
+			if current == "running" || current == "starting" {
+				json.NewEncoder(w).Encode(AppSummary{Name: req.Name, State: current})
+				return
+			}
+			next.state, next.target, next.until = "starting", "running", now.Add(startDuration)
+			event, message = "app.started", "Application started"
+		}
+
+		lifecycleMu.Lock()
+		transitions[req.Name] = next
+		lifecycleMu.Unlock()
+		emitEvent(req.Name, event, mockUser, message, data)
+
+		json.NewEncoder(w).Encode(AppSummary{Name: req.Name, State: next.state})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type AppLock struct {
+	Reason   string    `json:"reason"`
+	LockedBy string    `json:"lockedBy,omitempty"`
+	LockedAt time.Time `json:"lockedAt"`
+}
+
+// mockUser is the user owning the mock token
+const mockUser = "dev@example.com"
+
+var (
+	locksMu sync.Mutex
+	locks   = map[string]*AppLock{}
+)
+
+// lockFor returns the lock of an app, or nil if deployments are allowed
+func lockFor(name string) *AppLock {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+	return locks[name]
+}
+
+// rejectLocked answers 423 Locked if deployments of the app are locked
+func rejectLocked(w http.ResponseWriter, name string) bool {
+	lock := lockFor(name)
+	if lock == nil {
+		return false
+	}
+	http.Error(w, fmt.Sprintf("%s (locked by %s at %s)", lock.Reason, lock.LockedBy, lock.LockedAt.Format(time.RFC3339)), http.StatusLocked)
+	return true
+}
+
+func lockHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			Name   string `json:"name"`
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" || req.Reason == "" {
+			http.Error(w, "Name and reason are required", http.StatusBadRequest)
+			return
+		}
+
+		locksMu.Lock()
+		defer locksMu.Unlock()
+		if lock, ok := locks[req.Name]; ok {
+			http.Error(w, fmt.Sprintf("Already locked by %s: %s", lock.LockedBy, lock.Reason), http.StatusConflict)
+			return
+		}
+		lock := &AppLock{Reason: req.Reason, LockedBy: mockUser, LockedAt: time.Now()}
+		locks[req.Name] = lock
+		emitEvent(req.Name, "app.locked", mockUser, "Deployments locked: "+req.Reason, nil)
+		json.NewEncoder(w).Encode(lock)
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		locksMu.Lock()
+		defer locksMu.Unlock()
+		if _, ok := locks[name]; !ok {
+			http.Error(w, fmt.Sprintf("%s is not locked", name), http.StatusNotFound)
+			return
+		}
+		delete(locks, name)
+		emitEvent(name, "app.unlocked", mockUser, "Deployments unlocked", nil)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
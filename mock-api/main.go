@@ -14,23 +14,56 @@ const (
 )
 
 type DeployRequest struct {
-	Name  string `json:"name"`
-	Image string `json:"image"`
+	Name        string            `json:"name"`
+	Image       string            `json:"image"`
+	Region      string            `json:"region"`
+	Regions     []string          `json:"regions"`
+	Labels      map[string]string `json:"labels"`
+	Domains     []string          `json:"domains"`
+	HealthCheck *HealthCheck      `json:"healthCheck"`
+	Strategy    *Strategy         `json:"strategy"`
+	Jobs        map[string]Job    `json:"jobs"`
+	Volumes     []VolumeMount     `json:"volumes"`
+	Env         map[string]string `json:"env"`
+
+	ExpectedVersion *int `json:"expectedVersion"`
 }
 
 type DeployResponse struct {
-	ID      string `json:"id"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	ID      string         `json:"id"`
+	Status  string         `json:"status"`
+	URL     string         `json:"url"`
+	Message string         `json:"message"`
+	Addons  []AddonBinding `json:"addons,omitempty"`
+	Regions []RegionStatus `json:"regions,omitempty"`
+}
+
+type ResourceUsage struct {
+	CPUUsage     float64 `json:"cpuUsage"`
+	MemoryUsage  float64 `json:"memoryUsage"`
+	StorageUsage float64 `json:"storageUsage"`
 }
 
 type StatusResponse struct {
-	Name    string `json:"name"`
-	Status  string `json:"status"`
-	CPU     string `json:"cpu"`
-	Memory  string `json:"memory"`
-	Uptime  string `json:"uptime"`
-	Message string `json:"message"`
+	Name           string        `json:"name"`
+	Status         string        `json:"status"`
+	CPU            string        `json:"cpu"`
+	Memory         string        `json:"memory"`
+	Uptime         string        `json:"uptime"`
+	Message        string        `json:"message"`
+	State          string        `json:"state"`
+	Version        int           `json:"version,omitempty"`
+	Lock           *AppLock      `json:"lock,omitempty"`
+	LastDeployment time.Time     `json:"lastDeployment"`
+	Resources      ResourceUsage `json:"resources"`
+	Health         *HealthStatus `json:"health,omitempty"`
+
+	Release   *ReleaseInfo         `json:"release,omitempty"`
+	Instances []InstanceStatus     `json:"instances,omitempty"`
+	Events    []PlatformEvent      `json:"events,omitempty"`
+	Usage     *ResourceUsageDetail `json:"usage,omitempty"`
+	Volumes   []VolumeUsage        `json:"volumes,omitempty"`
+	Regions   []RegionStatus       `json:"regions,omitempty"`
 }
 
 type LogsResponse struct {
@@ -48,6 +81,30 @@ func deployHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	switch r.Method {
+	case http.MethodGet:
+		selector, err := parseSelector(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"apps": listApps(selector)})
+		return
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "Name parameter is required", http.StatusBadRequest)
+			return
+		}
+		if !deleteApp(name) {
+			http.Error(w, fmt.Sprintf("Application %s not found", name), http.StatusNotFound)
+			return
+		}
+		mountVolumes(name, nil)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -59,10 +116,48 @@ func deployHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if rejectLocked(w, req.Name) {
+		return
+	}
+	appRegions, err := checkRegions(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := checkMounts(req.Name, appRegions, req.Volumes); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	app := &App{
+		Name:        req.Name,
+		Image:       req.Image,
+		Region:      req.Region,
+		Regions:     appRegions,
+		Labels:      req.Labels,
+		HealthCheck: req.HealthCheck,
+		Jobs:        req.Jobs,
+		DeployedAt:  time.Now(),
+	}
+	previous, err := saveApp(app, req.ExpectedVersion)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	startRollout(app, previous, req.Strategy)
+	mountVolumes(req.Name, req.Volumes)
+
+	for _, domain := range req.Domains {
+		addDomain(req.Name, domain)
+	}
+
 	resp := DeployResponse{
 		ID:      "app-123",
 		Status:  "deploying",
+		URL:     appURL(req.Name),
 		Message: fmt.Sprintf("Deploying %s from image %s", req.Name, req.Image),
+		Addons:  addonBindings(req.Name, req.Env),
+		Regions: regionStatuses(app),
 	}
 
 	json.NewEncoder(w).Encode(resp)
@@ -85,13 +180,37 @@ func statusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	app := getApp(name)
+	state := appState(name)
+
 	resp := StatusResponse{
-		Name:    name,
-		Status:  "running",
-		CPU:     "25%",
-		Memory:  "128MB",
-		Uptime:  "2h 15m",
-		Message: "Application is running normally",
+		Name:           name,
+		Status:         state,
+		CPU:            "25%",
+		Memory:         "128MB",
+		Uptime:         time.Since(app.DeployedAt).Round(time.Minute).String(),
+		Message:        stateMessage(state),
+		State:          state,
+		LastDeployment: app.DeployedAt,
+		Resources:      ResourceUsage{CPUUsage: 25, MemoryUsage: 50, StorageUsage: 12.5},
+		Health:         healthFor(app),
+		Lock:           lockFor(name),
+		Volumes:        volumeUsage(name),
+		Regions:        regionStatuses(app),
+	}
+	if _, ok := findApp(name); ok {
+		resp.Version = app.Version
+	}
+	if state == "stopped" {
+		resp.CPU, resp.Memory, resp.Uptime = "0%", "0MB", ""
+		resp.Resources = ResourceUsage{StorageUsage: 12.5}
+	}
+
+	if r.URL.Query().Get("detailed") == "true" {
+		resp.Release = releaseFor(app)
+		resp.Instances = instancesFor(app)
+		resp.Events = eventsFor(app)
+		resp.Usage = usageFor(app)
 	}
 
 	json.NewEncoder(w).Encode(resp)
@@ -136,9 +255,46 @@ func main() {
 	mux.HandleFunc("/apps", deployHandler)
 	mux.HandleFunc("/apps/status", statusHandler)
 	mux.HandleFunc("/apps/logs", logsHandler)
+	mux.HandleFunc("/apps/port-forward", portForwardHandler)
+	mux.HandleFunc("/apps/domains", domainsHandler)
+	mux.HandleFunc("/apps/domains/verify", verifyDomainHandler)
+	mux.HandleFunc("/apps/rollout", rolloutHandler)
+	mux.HandleFunc("/apps/rollout/promote", promoteRolloutHandler)
+	mux.HandleFunc("/apps/rollout/abort", abortRolloutHandler)
+	mux.HandleFunc("/apps/lock", lockHandler)
+	mux.HandleFunc("/apps/restart", lifecycleHandler("restart"))
+	mux.HandleFunc("/apps/stop", lifecycleHandler("stop"))
+	mux.HandleFunc("/apps/start", lifecycleHandler("start"))
+	mux.HandleFunc("/apps/metrics", metricsHandler)
+	mux.HandleFunc("/apps/jobs", jobsHandler)
+	mux.HandleFunc("/apps/jobs/run", runJobHandler)
+	mux.HandleFunc("/apps/jobs/runs", jobRunsHandler)
+	mux.HandleFunc("/apps/jobs/logs", runLogsHandler)
+	mux.HandleFunc("/apps/tasks", taskHandler)
+	mux.HandleFunc("/alerts", alertsHandler)
+	mux.HandleFunc("/alerts/test", testAlertHandler)
+	mux.HandleFunc("/notifications", notificationsHandler)
+	mux.HandleFunc("/apps/webhooks", webhooksHandler)
+	mux.HandleFunc("/apps/webhooks/test", testWebhookHandler)
+	mux.HandleFunc("/apps/events", eventsHandler)
+	mux.HandleFunc("/volumes", volumesHandler)
+	mux.HandleFunc("/volumes/resize", resizeVolumeHandler)
+	mux.HandleFunc("/volumes/snapshots", volumeSnapshotsHandler)
+	mux.HandleFunc("/volumes/restore", restoreVolumeHandler)
+	mux.HandleFunc("/addons", addonsHandler)
+	mux.HandleFunc("/addons/plans", addonPlansHandler)
+	mux.HandleFunc("/addons/info", addonInfoHandler)
+	mux.HandleFunc("/addons/rotate", rotateAddonHandler)
+	mux.HandleFunc("/addons/backups", addonBackupsHandler)
+	mux.HandleFunc("/addons/restore", restoreAddonHandler)
+	mux.HandleFunc("/regions", regionsHandler)
+	mux.HandleFunc("/regions/ping", regionPingHandler)
+
+	startEchoServer()
 
 	port := ":8080"
 	fmt.Printf("Mock API server starting on http://localhost%s\n", port)
 	fmt.Printf("Use token: %s\n", mockToken)
+	fmt.Printf("Port-forward streams are connected to the echo server on %s\n", echoAddr)
 	log.Fatal(http.ListenAndServe(port, mux))
 }
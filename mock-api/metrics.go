@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type MetricPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+type MetricSeries struct {
+	Name   string        `json:"name"`
+	Unit   string        `json:"unit"`
+	Points []MetricPoint `json:"points"`
+}
+
+type MetricsResponse struct {
+	Start       time.Time      `json:"start"`
+	End         time.Time      `json:"end"`
+	StepSeconds int            `json:"stepSeconds"`
+	Series      []MetricSeries `json:"series"`
+}
+
+// metricUnits lists the simulated metrics in order, with their unit
+var metricUnits = []struct{ name, unit string }{
+	{"cpu", "%"},
+	{"memory", "%"},
+	{"storage", "%"},
+	{"requests", "req/s"},
+	{"latency", "ms"},
+	{"errors", "%"},
+}
+
+// maxMetricPoints is the largest number of points returned per series
+const maxMetricPoints = 11000
+
+// servesHTTP reports whether request metrics are available for the app:
+// apps with a tcp or command health check are assumed not to serve HTTP
+func servesHTTP(app *App) bool {
+	return app.HealthCheck == nil || app.HealthCheck.Type == "http"
+}
+
+// noise returns a stable pseudo-random value in [0, 1) for a metric of an app at t,
+// so that repeated queries return the same history
+func noise(app, metric string, t time.Time) float64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%s/%d", app, metric, t.Unix())
+	return float64(h.Sum64()%10000) / 10000
+}
+
+// metricValue simulates the value of a metric: a daily cycle with noise and
+// occasional spikes
+func metricValue(app, metric string, t time.Time) float64 {
+	day := math.Sin(2 * math.Pi * float64(t.Unix()%86400) / 86400)
+	n := noise(app, metric, t)
+	spike := noise(app, metric+"/spike", t) > 0.98
+
+	var v float64
+	switch metric {
+	case "cpu":
+		v = 30 + 15*day + 10*n
+		if spike {
+			v = math.Min(v+40, 100)
+		}
+	case "memory":
+		v = 50 + 5*day + 3*n
+	case "storage":
+		v = 12 + float64(t.Unix()%(7*86400))/(7*86400)*3
+	case "requests":
+		v = 40 + 30*day + 8*n
+	case "latency":
+		v = 80 + 40*day + 20*n
+		if spike {
+			v += 400
+		}
+	case "errors":
+		v = 0.2 + 0.3*n
+		if spike {
+			v += 4
+		}
+	}
+	return math.Round(v*100) / 100
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("name")
+	if name == "" {
+		http.Error(w, "Name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	end := time.Now()
+	if v := query.Get("end"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid end", http.StatusBadRequest)
+			return
+		}
+		end = t
+	}
+	start := end.Add(-time.Hour)
+	if v := query.Get("start"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid start", http.StatusBadRequest)
+			return
+		}
+		start = t
+	}
+	stepSeconds := 60
+	if v := query.Get("step"); v != "" {
+		s, err := strconv.Atoi(v)
+		if err != nil || s < 10 {
+			http.Error(w, "Step must be at least 10 seconds", http.StatusBadRequest)
+			return
+		}
+		stepSeconds = s
+	}
+	step := time.Duration(stepSeconds) * time.Second
+	if !start.Before(end) {
+		http.Error(w, "Start must be before end", http.StatusBadRequest)
+		return
+	}
+	if end.Sub(start)/step > maxMetricPoints {
+		http.Error(w, fmt.Sprintf("Too many points, the maximum is %d per series", maxMetricPoints), http.StatusBadRequest)
+		return
+	}
+
+	wanted := map[string]bool{}
+	for _, metric := range query["metric"] {
+		wanted[metric] = true
+	}
+
+	app := getApp(name)
+	resp := MetricsResponse{Start: start, End: end, StepSeconds: stepSeconds, Series: []MetricSeries{}}
+	for _, m := range metricUnits {
+		if len(wanted) > 0 && !wanted[m.name] {
+			continue
+		}
+		if (m.name == "requests" || m.name == "latency" || m.name == "errors") && !servesHTTP(app) {
+			continue
+		}
+		series := MetricSeries{Name: m.name, Unit: m.unit, Points: []MetricPoint{}}
+		for t := start.Truncate(step); !t.After(end); t = t.Add(step) {
+			if t.Before(start) {
+				continue
+			}
+			series.Points = append(series.Points, MetricPoint{Timestamp: t, Value: metricValue(name, m.name, t)})
+		}
+		resp.Series = append(resp.Series, series)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/binary"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// echoAddr is where every forwarded port is connected to
+var echoAddr = "127.0.0.1:9000"
+
+const (
+	frameOpen  byte = 1
+	frameData  byte = 2
+	frameClose byte = 3
+	frameFin   byte = 4
+	frameAck   byte = 5
+
+	frameHeaderSize = 5
+
+	// Bytes each side of a stream may send before being acked, and the
+	// largest data frame
+	streamWindow = 256 * 1024
+	maxDataSize  = 32 * 1024
+)
+
+var upgrader = websocket.Upgrader{}
+
+// startEchoServer runs a TCP server that writes back everything it receives.
+// Port-forwarded streams fail to open while it isn't running, but the rest of
+// the API keeps working.
+func startEchoServer() {
+	l, err := net.Listen("tcp", echoAddr)
+	if err != nil {
+		log.Printf("Echo server failed, port forwarding is unavailable: %v", err)
+		return
+	}
+	echoAddr = l.Addr().String()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+}
+
+// tunnel multiplexes forwarded streams for one WebSocket connection
+type tunnel struct {
+	ws      *websocket.Conn
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	streams map[uint32]*stream
+}
+
+// stream is a forwarded connection. Data from the client is queued and
+// written by its own goroutine, then acked, so a slow connection doesn't
+// stall the tunnel; data to the client waits for the client's acks.
+type stream struct {
+	conn   net.Conn
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  [][]byte
+	fin    bool
+	closed bool
+	window int
+}
+
+func (t *tunnel) send(kind byte, id uint32, payload []byte) {
+	buf := make([]byte, frameHeaderSize+len(payload))
+	buf[0] = kind
+	binary.BigEndian.PutUint32(buf[1:frameHeaderSize], id)
+	copy(buf[frameHeaderSize:], payload)
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	t.ws.WriteMessage(websocket.BinaryMessage, buf)
+}
+
+func (t *tunnel) ack(id uint32, n int) {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint32(payload, uint32(n))
+	t.send(frameAck, id, payload)
+}
+
+func (t *tunnel) open(id uint32, port uint16) {
+	conn, err := net.Dial("tcp", echoAddr)
+	if err != nil {
+		t.send(frameClose, id, []byte(err.Error()))
+		return
+	}
+	log.Printf("Port-forward stream %d opened for port %d", id, port)
+
+	st := &stream{conn: conn, window: streamWindow}
+	st.cond = sync.NewCond(&st.mu)
+	t.mu.Lock()
+	t.streams[id] = st
+	t.mu.Unlock()
+
+	go t.writeLoop(id, st)
+	go t.readLoop(id, st)
+}
+
+// writeLoop writes the data received from the client to the connection,
+// half-closing it after a fin once the queue is empty
+func (t *tunnel) writeLoop(id uint32, st *stream) {
+	for {
+		st.mu.Lock()
+		for len(st.queue) == 0 && !st.fin && !st.closed {
+			st.cond.Wait()
+		}
+		if st.closed {
+			st.mu.Unlock()
+			return
+		}
+		if len(st.queue) == 0 {
+			st.mu.Unlock()
+			if tcp, ok := st.conn.(*net.TCPConn); ok {
+				tcp.CloseWrite()
+			}
+			return
+		}
+		p := st.queue[0]
+		st.queue = st.queue[1:]
+		st.mu.Unlock()
+
+		if _, err := st.conn.Write(p); err != nil {
+			if t.remove(id) != nil {
+				t.send(frameClose, id, []byte(err.Error()))
+			}
+			return
+		}
+		t.ack(id, len(p))
+	}
+}
+
+// readLoop sends the data of the connection to the client, within the window
+func (t *tunnel) readLoop(id uint32, st *stream) {
+	buf := make([]byte, maxDataSize)
+	for {
+		st.mu.Lock()
+		for st.window == 0 && !st.closed {
+			st.cond.Wait()
+		}
+		n, closed := st.window, st.closed
+		st.mu.Unlock()
+		if closed {
+			return
+		}
+		if n > len(buf) {
+			n = len(buf)
+		}
+
+		n, err := st.conn.Read(buf[:n])
+		if n > 0 {
+			st.mu.Lock()
+			st.window -= n
+			st.mu.Unlock()
+			t.send(frameData, id, buf[:n])
+		}
+		if err == io.EOF {
+			// The client may still be sending: only half-close the stream
+			t.send(frameFin, id, nil)
+			return
+		}
+		if err != nil {
+			break
+		}
+	}
+	if t.remove(id) != nil {
+		t.send(frameClose, id, nil)
+	}
+}
+
+func (t *tunnel) get(id uint32) *stream {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.streams[id]
+}
+
+func (t *tunnel) remove(id uint32) *stream {
+	t.mu.Lock()
+	st := t.streams[id]
+	delete(t.streams, id)
+	t.mu.Unlock()
+	if st != nil {
+		st.close()
+	}
+	return st
+}
+
+func (st *stream) close() {
+	st.mu.Lock()
+	st.closed = true
+	st.cond.Broadcast()
+	st.mu.Unlock()
+	st.conn.Close()
+}
+
+func portForwardHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.URL.Query().Get("name") == "" {
+		http.Error(w, "Name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	t := &tunnel{ws: ws, streams: make(map[uint32]*stream)}
+	defer func() {
+		ws.Close()
+		t.mu.Lock()
+		for id, st := range t.streams {
+			st.close()
+			delete(t.streams, id)
+		}
+		t.mu.Unlock()
+	}()
+
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if len(msg) < frameHeaderSize {
+			continue
+		}
+
+		kind := msg[0]
+		id := binary.BigEndian.Uint32(msg[1:frameHeaderSize])
+		payload := msg[frameHeaderSize:]
+
+		switch kind {
+		case frameOpen:
+			if len(payload) == 2 {
+				t.open(id, binary.BigEndian.Uint16(payload))
+			}
+		case frameData:
+			if st := t.get(id); st != nil {
+				st.mu.Lock()
+				st.queue = append(st.queue, payload)
+				st.cond.Broadcast()
+				st.mu.Unlock()
+			}
+		case frameAck:
+			if st := t.get(id); st != nil && len(payload) == 4 {
+				st.mu.Lock()
+				st.window += int(binary.BigEndian.Uint32(payload))
+				st.cond.Broadcast()
+				st.mu.Unlock()
+			}
+		case frameFin:
+			if st := t.get(id); st != nil {
+				st.mu.Lock()
+				st.fin = true
+				st.cond.Broadcast()
+				st.mu.Unlock()
+			}
+		case frameClose:
+			t.remove(id)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialTunnel starts the echo server and connects to the port-forward endpoint
+func dialTunnel(t *testing.T) *websocket.Conn {
+	echoAddr = "127.0.0.1:0"
+	startEchoServer()
+
+	server := httptest.NewServer(http.HandlerFunc(portForwardHandler))
+	t.Cleanup(server.Close)
+
+	header := http.Header{"Authorization": {"Bearer " + mockToken}}
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?name=shop", header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return ws
+}
+
+func sendFrame(t *testing.T, ws *websocket.Conn, kind byte, id uint32, payload []byte) {
+	buf := make([]byte, frameHeaderSize+len(payload))
+	buf[0] = kind
+	binary.BigEndian.PutUint32(buf[1:frameHeaderSize], id)
+	copy(buf[frameHeaderSize:], payload)
+	if err := ws.WriteMessage(websocket.BinaryMessage, buf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readFrame returns the next frame, counting the bytes acked on acked
+func readFrame(t *testing.T, ws *websocket.Conn, acked *int) (byte, uint32, []byte) {
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(msg) < frameHeaderSize {
+			t.Fatalf("frame too short: %d bytes", len(msg))
+		}
+		if msg[0] == frameAck {
+			*acked += int(binary.BigEndian.Uint32(msg[frameHeaderSize:]))
+			continue
+		}
+		return msg[0], binary.BigEndian.Uint32(msg[1:frameHeaderSize]), msg[frameHeaderSize:]
+	}
+}
+
+func TestPortForwardEchoAndHalfClose(t *testing.T) {
+	ws := dialTunnel(t)
+
+	port := make([]byte, 2)
+	binary.BigEndian.PutUint16(port, 8080)
+	sendFrame(t, ws, frameOpen, 1, port)
+	sendFrame(t, ws, frameData, 1, []byte("hello"))
+
+	echoed, acked := "", 0
+	for len(echoed) < len("hello") {
+		kind, id, payload := readFrame(t, ws, &acked)
+		if kind != frameData || id != 1 {
+			t.Fatalf("got frame %d for stream %d, want data for stream 1", kind, id)
+		}
+		echoed += string(payload)
+	}
+	if echoed != "hello" {
+		t.Errorf("echoed %q, want hello", echoed)
+	}
+
+	// Half-closing the stream ends the echo, which half-closes back
+	sendFrame(t, ws, frameFin, 1, nil)
+	if kind, id, _ := readFrame(t, ws, &acked); kind != frameFin || id != 1 {
+		t.Errorf("got frame %d for stream %d, want fin for stream 1", kind, id)
+	}
+	if acked != len("hello") {
+		t.Errorf("acked %d bytes, want %d", acked, len("hello"))
+	}
+}
+
+func TestPortForwardWaitsForAcks(t *testing.T) {
+	ws := dialTunnel(t)
+
+	port := make([]byte, 2)
+	binary.BigEndian.PutUint16(port, 8080)
+	sendFrame(t, ws, frameOpen, 1, port)
+	sendFrame(t, ws, frameData, 1, make([]byte, streamWindow+maxDataSize))
+
+	// Only a window of the echo is sent until the client acks it
+	echoed, acked := 0, 0
+	for echoed < streamWindow {
+		_, _, payload := readFrame(t, ws, &acked)
+		echoed += len(payload)
+	}
+	ws.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			break
+		}
+		if msg[0] == frameData {
+			t.Fatalf("got %d more bytes beyond the window", len(msg)-frameHeaderSize)
+		}
+	}
+}
+
+func TestPortForwardRequiresName(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/apps/port-forward", nil)
+	req.Header.Set("Authorization", "Bearer "+mockToken)
+	rec := httptest.NewRecorder()
+	portForwardHandler(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestEchoServerPortTaken(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// Used to exit the whole mock API
+	echoAddr = l.Addr().String()
+	startEchoServer()
+}
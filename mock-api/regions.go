@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type Region struct {
+	Name         string  `json:"name"`
+	DisplayName  string  `json:"displayName,omitempty"`
+	Status       string  `json:"status"`
+	CapacityUsed float64 `json:"capacityUsed"`
+	PingURL      string  `json:"pingUrl,omitempty"`
+	Default      bool    `json:"default,omitempty"`
+}
+
+// RegionStatus is the state of an app in one of its regions
+type RegionStatus struct {
+	Region    string `json:"region"`
+	State     string `json:"state"`
+	Version   int    `json:"version,omitempty"`
+	Instances int    `json:"instances"`
+	URL       string `json:"url,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// regions are the simulated regions; the first one is the default
+var regions = []Region{
+	{Name: "us-east-1", DisplayName: "US East (Virginia)", Status: "available", CapacityUsed: 41, Default: true},
+	{Name: "eu-west-1", DisplayName: "EU West (Ireland)", Status: "available", CapacityUsed: 63},
+	{Name: "me-central-1", DisplayName: "Middle East (Riyadh)", Status: "limited", CapacityUsed: 92},
+	{Name: "ap-southeast-1", DisplayName: "Asia Pacific (Singapore)", Status: "unavailable", CapacityUsed: 100},
+}
+
+// regionLatency simulates the network latency to each region
+var regionLatency = map[string]time.Duration{
+	"us-east-1":      15 * time.Millisecond,
+	"eu-west-1":      45 * time.Millisecond,
+	"me-central-1":   90 * time.Millisecond,
+	"ap-southeast-1": 160 * time.Millisecond,
+}
+
+// regionDeployDuration simulates how long a deployment takes in a region;
+// the regions of an app are deployed in parallel
+var regionDeployDuration = map[string]time.Duration{
+	"us-east-1":    2 * time.Second,
+	"eu-west-1":    4 * time.Second,
+	"me-central-1": 6 * time.Second,
+}
+
+// findRegion returns a simulated region
+func findRegion(name string) (Region, bool) {
+	for _, region := range regions {
+		if region.Name == name {
+			return region, true
+		}
+	}
+	return Region{}, false
+}
+
+// checkRegions verifies that an app can be deployed to the requested regions
+// and returns them, the default region meaning none was requested
+func checkRegions(req *DeployRequest) ([]string, error) {
+	if req.Region != "" && len(req.Regions) > 0 {
+		return nil, fmt.Errorf("region and regions can't both be set")
+	}
+	names := req.Regions
+	if req.Region != "" {
+		names = []string{req.Region}
+	}
+	seen := map[string]bool{}
+	for _, name := range names {
+		region, ok := findRegion(name)
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("unknown region %q", name)
+		case region.Status == "unavailable":
+			return nil, fmt.Errorf("region %s has no capacity left", name)
+		case seen[name]:
+			return nil, fmt.Errorf("region %s is listed twice", name)
+		}
+		seen[name] = true
+	}
+	return names, nil
+}
+
+// regionStatuses reports the state of a multi-region app in each region.
+// Single-region apps report none.
+func regionStatuses(app *App) []RegionStatus {
+	if len(app.Regions) < 2 {
+		return nil
+	}
+	state := appState(app.Name)
+	statuses := []RegionStatus{}
+	for _, name := range app.Regions {
+		s := RegionStatus{
+			Region:    name,
+			State:     state,
+			Version:   app.Version,
+			Instances: mockInstances,
+			URL:       fmt.Sprintf("https://%s.%s.ghaymah.app", app.Name, name),
+		}
+		if state == "running" && time.Since(app.DeployedAt) < regionDeployDuration[name] {
+			s.State = "deploying"
+			s.Message = fmt.Sprintf("Rolling out release v%d", app.Version)
+		}
+		if state == "stopped" {
+			s.Instances = 0
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+func regionsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	list := make([]Region, len(regions))
+	for i, region := range regions {
+		region.PingURL = fmt.Sprintf("http://%s/regions/ping?region=%s", r.Host, region.Name)
+		list[i] = region
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"regions": list})
+}
+
+// regionPingHandler answers after the simulated latency of a region. It
+// doesn't require a token, like the ping endpoints of real regions.
+func regionPingHandler(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(regionLatency[r.URL.Query().Get("region")])
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Strategy struct {
+	Type           string       `json:"type"`
+	MaxSurge       string       `json:"maxSurge,omitempty"`
+	MaxUnavailable string       `json:"maxUnavailable,omitempty"`
+	AutoPromote    bool         `json:"autoPromote,omitempty"`
+	Steps          []CanaryStep `json:"steps,omitempty"`
+}
+
+type CanaryStep struct {
+	Weight int    `json:"weight"`
+	Pause  string `json:"pause,omitempty"`
+}
+
+type Rollout struct {
+	Strategy        string    `json:"strategy"`
+	State           string    `json:"state"`
+	Version         int       `json:"version"`
+	PreviousVersion int       `json:"previousVersion,omitempty"`
+	Step            int       `json:"step"`
+	TotalSteps      int       `json:"totalSteps"`
+	TrafficWeight   int       `json:"trafficWeight"`
+	Health          string    `json:"health,omitempty"`
+	Message         string    `json:"message,omitempty"`
+	StartedAt       time.Time `json:"startedAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// rolloutStep is a simulated step: traffic weight, then a pause before the
+// next step, or a wait for promotion when manual is set
+type rolloutStep struct {
+	weight int
+	pause  time.Duration
+	manual bool
+}
+
+// rollout is the mock server's record of a rollout in progress
+type rollout struct {
+	Rollout
+	steps       []rolloutStep
+	stepStarted time.Time
+	failing     bool
+	previous    *App
+	app         string
+}
+
+// Simulated durations of the rollout phases
+const (
+	rolloutStepDuration  = 3 * time.Second
+	rolloutHealthTimeout = 2 * time.Second
+)
+
+var (
+	rolloutsMu sync.Mutex
+	rollouts   = map[string]*rollout{}
+)
+
+// startRollout records the rollout of a newly deployed release. Releases
+// whose health check fails (path /fail) are aborted automatically.
+func startRollout(app *App, previous *App, strategy *Strategy) {
+	if strategy == nil {
+		strategy = &Strategy{Type: "rolling"}
+	}
+
+	var steps []rolloutStep
+	switch strategy.Type {
+	case "blue-green":
+		steps = []rolloutStep{{weight: 0, pause: rolloutStepDuration}}
+		if !strategy.AutoPromote {
+			steps = append(steps, rolloutStep{weight: 0, manual: true})
+		}
+	case "canary":
+		for _, s := range strategy.Steps {
+			pause, err := time.ParseDuration(s.Pause)
+			steps = append(steps, rolloutStep{weight: s.Weight, pause: pause, manual: s.Pause == "" || err != nil})
+		}
+	default:
+		for i := 1; i <= 3; i++ {
+			steps = append(steps, rolloutStep{weight: i * 100 / 3, pause: rolloutStepDuration})
+		}
+	}
+
+	now := time.Now()
+	r := &rollout{
+		Rollout: Rollout{
+			Strategy:   strategy.Type,
+			State:      "progressing",
+			Version:    app.Version,
+			TotalSteps: len(steps),
+			Health:     "pending",
+			StartedAt:  now,
+			UpdatedAt:  now,
+		},
+		steps:       steps,
+		stepStarted: now,
+		failing:     app.HealthCheck != nil && app.HealthCheck.Path == "/fail",
+		previous:    previous,
+		app:         app.Name,
+	}
+	if previous != nil {
+		r.PreviousVersion = previous.Version
+	}
+	r.enter(0, now)
+
+	emitEvent(app.Name, "deploy.started", mockUser, fmt.Sprintf("Release v%d of %s started (%s)", app.Version, app.Image, strategy.Type), map[string]interface{}{"version": app.Version, "image": app.Image, "strategy": strategy.Type})
+
+	rolloutsMu.Lock()
+	rollouts[app.Name] = r
+	rolloutsMu.Unlock()
+	go watchRollout(app.Name, r)
+}
+
+// watchRollout advances a rollout in the background, so that its events are
+// emitted without waiting for a status request. It stops when the rollout
+// is done or replaced by a newer deployment.
+func watchRollout(name string, r *rollout) {
+	for {
+		time.Sleep(500 * time.Millisecond)
+		rolloutsMu.Lock()
+		current := rollouts[name] == r
+		if current {
+			r.advance(time.Now())
+		}
+		done := !current || r.State == "completed" || r.State == "aborted"
+		rolloutsMu.Unlock()
+		if done {
+			return
+		}
+	}
+}
+
+// enter moves the rollout to step i, completing it past the last step
+func (r *rollout) enter(i int, now time.Time) {
+	r.UpdatedAt = now
+	r.stepStarted = now
+	if i >= len(r.steps) {
+		r.State = "completed"
+		r.Step = len(r.steps)
+		r.TrafficWeight = 100
+		r.Health = "healthy"
+		r.Message = fmt.Sprintf("release v%d is serving all traffic", r.Version)
+		emitEvent(r.app, "deploy.succeeded", platformActor, r.Message, map[string]interface{}{"version": r.Version, "strategy": r.Strategy})
+		return
+	}
+
+	step := r.steps[i]
+	r.Step = i + 1
+	r.TrafficWeight = step.weight
+	if step.manual {
+		r.State = "paused"
+		r.Message = "waiting for promotion"
+	} else {
+		r.State = "progressing"
+		r.Message = fmt.Sprintf("next step in %s", step.pause)
+	}
+}
+
+// advance simulates the progress made since the last update
+func (r *rollout) advance(now time.Time) {
+	for r.State == "progressing" || r.State == "paused" {
+		if r.failing && now.Sub(r.StartedAt) >= rolloutHealthTimeout {
+			emitEvent(r.app, "health.failed", platformActor, "Health check failed on instance 2: HTTP 500 from /fail", map[string]interface{}{"version": r.Version})
+			r.abort(r.StartedAt.Add(rolloutHealthTimeout), platformActor, "new release failed health checks on instance 2 (HTTP 500 from /fail)")
+			return
+		}
+		if now.Sub(r.StartedAt) >= rolloutHealthTimeout {
+			r.Health = "healthy"
+		}
+		if r.State == "paused" {
+			return
+		}
+		step := r.steps[r.Step-1]
+		if now.Sub(r.stepStarted) < step.pause {
+			return
+		}
+		r.enter(r.Step, r.stepStarted.Add(step.pause))
+	}
+}
+
+// abort sends traffic back to the previous release
+func (r *rollout) abort(at time.Time, actor, reason string) {
+	r.State = "aborted"
+	r.TrafficWeight = 0
+	r.UpdatedAt = at
+	r.Message = reason
+	if r.failing {
+		r.Health = "unhealthy"
+	}
+	emitEvent(r.app, "deploy.failed", actor, fmt.Sprintf("Release v%d rolled back: %s", r.Version, reason), map[string]interface{}{"version": r.Version, "reason": reason})
+
+	if r.previous != nil {
+		appsMu.Lock()
+		apps[r.previous.Name] = r.previous
+		appsMu.Unlock()
+	}
+}
+
+// currentRollout returns the up to date rollout of an app
+func currentRollout(name string) (*rollout, bool) {
+	rolloutsMu.Lock()
+	defer rolloutsMu.Unlock()
+	r, ok := rollouts[name]
+	if ok {
+		r.advance(time.Now())
+	}
+	return r, ok
+}
+
+func rolloutHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	ro, ok := currentRollout(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("No rollout found for %s", name), http.StatusNotFound)
+		return
+	}
+
+	rolloutsMu.Lock()
+	defer rolloutsMu.Unlock()
+	json.NewEncoder(w).Encode(ro.Rollout)
+}
+
+type rolloutAction struct {
+	Name string `json:"name"`
+	Full bool   `json:"full"`
+}
+
+func promoteRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	handleRolloutAction(w, r, func(ro *rollout, action rolloutAction) {
+		if rejectLocked(w, action.Name) {
+			return
+		}
+		now := time.Now()
+		if action.Full {
+			ro.enter(len(ro.steps), now)
+			return
+		}
+		ro.enter(ro.Step, now)
+		ro.advance(now)
+	})
+}
+
+func abortRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	handleRolloutAction(w, r, func(ro *rollout, action rolloutAction) {
+		ro.abort(time.Now(), mockUser, "aborted by user")
+	})
+}
+
+// handleRolloutAction applies an action to a rollout that is still running
+func handleRolloutAction(w http.ResponseWriter, r *http.Request, apply func(*rollout, rolloutAction)) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var action rolloutAction
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil || action.Name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	ro, ok := currentRollout(action.Name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("No rollout found for %s", action.Name), http.StatusNotFound)
+		return
+	}
+
+	rolloutsMu.Lock()
+	defer rolloutsMu.Unlock()
+	if ro.State == "completed" || ro.State == "aborted" {
+		http.Error(w, fmt.Sprintf("Rollout of release v%d is already %s", ro.Version, ro.State), http.StatusConflict)
+		return
+	}
+	apply(ro, action)
+	json.NewEncoder(w).Encode(ro.Rollout)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postRolloutAction calls a rollout action handler and decodes the rollout
+func postRolloutAction(t *testing.T, handler http.HandlerFunc, body string) (int, Rollout) {
+	req := httptest.NewRequest(http.MethodPost, "/apps/rollout/promote", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+mockToken)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	var ro Rollout
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&ro); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, ro
+}
+
+// advanceRollout simulates the progress of a rollout after d
+func advanceRollout(name string, d time.Duration) Rollout {
+	rolloutsMu.Lock()
+	defer rolloutsMu.Unlock()
+	r := rollouts[name]
+	r.advance(r.StartedAt.Add(d))
+	return r.Rollout
+}
+
+func TestRolloutPromoteToCompleted(t *testing.T) {
+	app := &App{Name: "canary-promote", Image: "shop:2", Version: 2}
+	startRollout(app, &App{Name: app.Name, Version: 1}, &Strategy{
+		Type:  "canary",
+		Steps: []CanaryStep{{Weight: 10}, {Weight: 50, Pause: "1s"}},
+	})
+
+	// The first step has no pause: it waits for promotion
+	ro := advanceRollout(app.Name, rolloutHealthTimeout)
+	if ro.State != "paused" || ro.TrafficWeight != 10 || ro.Health != "healthy" {
+		t.Fatalf("before promotion: %+v, want paused at 10%% and healthy", ro)
+	}
+
+	code, ro := postRolloutAction(t, promoteRolloutHandler, `{"name":"canary-promote"}`)
+	if code != http.StatusOK || ro.State != "progressing" || ro.Step != 2 || ro.TrafficWeight != 50 {
+		t.Fatalf("after promotion: %d %+v, want progressing at step 2 and 50%%", code, ro)
+	}
+
+	rolloutsMu.Lock()
+	r := rollouts[app.Name]
+	r.advance(r.stepStarted.Add(time.Second))
+	ro = r.Rollout
+	rolloutsMu.Unlock()
+	if ro.State != "completed" || ro.TrafficWeight != 100 || ro.Step != ro.TotalSteps {
+		t.Fatalf("after the last pause: %+v, want completed at 100%%", ro)
+	}
+
+	if code, _ := postRolloutAction(t, promoteRolloutHandler, `{"name":"canary-promote"}`); code != http.StatusConflict {
+		t.Errorf("promoting a completed rollout returned %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestRolloutFullPromote(t *testing.T) {
+	app := &App{Name: "blue-green-promote", Image: "shop:2", Version: 2}
+	startRollout(app, nil, &Strategy{Type: "blue-green"})
+
+	code, ro := postRolloutAction(t, promoteRolloutHandler, `{"name":"blue-green-promote","full":true}`)
+	if code != http.StatusOK || ro.State != "completed" || ro.TrafficWeight != 100 {
+		t.Fatalf("full promotion: %d %+v, want completed at 100%%", code, ro)
+	}
+}
+
+func TestRolloutAbortsOnFailingHealth(t *testing.T) {
+	previous := &App{Name: "failing-release", Image: "shop:1", Version: 1}
+	app := &App{Name: previous.Name, Image: "shop:2", Version: 2, HealthCheck: &HealthCheck{Type: "http", Path: "/fail", Port: 8080}}
+	appsMu.Lock()
+	apps[app.Name] = app
+	appsMu.Unlock()
+	startRollout(app, previous, &Strategy{Type: "rolling"})
+
+	// Health checks haven't failed yet
+	if ro := advanceRollout(app.Name, rolloutHealthTimeout/2); ro.State != "progressing" {
+		t.Fatalf("before the health timeout: %+v, want progressing", ro)
+	}
+
+	ro := advanceRollout(app.Name, rolloutHealthTimeout)
+	if ro.State != "aborted" || ro.Health != "unhealthy" || ro.TrafficWeight != 0 {
+		t.Fatalf("after the health timeout: %+v, want aborted and unhealthy with no traffic", ro)
+	}
+	if !strings.Contains(ro.Message, "failed health checks") {
+		t.Errorf("abort message = %q, want the failing health check", ro.Message)
+	}
+
+	appsMu.Lock()
+	restored := apps[app.Name]
+	appsMu.Unlock()
+	if restored != previous {
+		t.Errorf("current release after the abort is v%d, want v%d", restored.Version, previous.Version)
+	}
+
+	if code, _ := postRolloutAction(t, promoteRolloutHandler, `{"name":"failing-release"}`); code != http.StatusConflict {
+		t.Errorf("promoting an aborted rollout returned %d, want %d", code, http.StatusConflict)
+	}
+}
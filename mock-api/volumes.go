@@ -0,0 +1,392 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
+}
+
+type Volume struct {
+	Name       string    `json:"name"`
+	Region     string    `json:"region,omitempty"`
+	Size       string    `json:"size"`
+	SizeBytes  float64   `json:"sizeBytes"`
+	UsedBytes  float64   `json:"usedBytes"`
+	State      string    `json:"state"`
+	AttachedTo string    `json:"attachedTo,omitempty"`
+	MountPath  string    `json:"mountPath,omitempty"`
+	ReadOnly   bool      `json:"readOnly,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type VolumeSnapshot struct {
+	ID          string    `json:"id"`
+	Volume      string    `json:"volume"`
+	Description string    `json:"description,omitempty"`
+	SizeBytes   float64   `json:"sizeBytes"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type VolumeUsage struct {
+	Name      string  `json:"name"`
+	MountPath string  `json:"mountPath"`
+	ReadOnly  bool    `json:"readOnly,omitempty"`
+	UsedBytes float64 `json:"usedBytes"`
+	SizeBytes float64 `json:"sizeBytes"`
+}
+
+var (
+	volumesMu sync.Mutex
+	volumes   = map[string]*Volume{}
+	snapshots = map[string][]VolumeSnapshot{}
+
+	sizeRe     = regexp.MustCompile(`(?i)^([0-9]+)\s*([KMGT])i?B?$`)
+	volumeName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+)
+
+// parseSize parses sizes such as 10G or 512Mi into bytes
+func parseSize(s string) (float64, error) {
+	m := sizeRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	shift := map[string]uint{"K": 10, "M": 20, "G": 30, "T": 40}[strings.ToUpper(m[2])]
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || n == 0 || n > math.MaxInt64>>shift {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return float64(n << shift), nil
+}
+
+// usedBytes simulates a stable usage of a volume, between 10% and 60% of its
+// size when it was created
+func usedBytes(name string, size float64) float64 {
+	sum := sha256.Sum256([]byte(name))
+	percent := 10 + binary.BigEndian.Uint16(sum[:2])%50
+	return float64(int64(size) / 100 * int64(percent))
+}
+
+// volumeUsage returns the volumes mounted by an app, sorted by mount path
+func volumeUsage(app string) []VolumeUsage {
+	volumesMu.Lock()
+	defer volumesMu.Unlock()
+	usage := []VolumeUsage{}
+	for _, volume := range volumes {
+		if volume.AttachedTo != app {
+			continue
+		}
+		usage = append(usage, VolumeUsage{
+			Name:      volume.Name,
+			MountPath: volume.MountPath,
+			ReadOnly:  volume.ReadOnly,
+			UsedBytes: volume.UsedBytes,
+			SizeBytes: volume.SizeBytes,
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].MountPath < usage[j].MountPath })
+	return usage
+}
+
+// checkMounts verifies that the volumes an app mounts exist, are in its only
+// region and aren't mounted by another app
+func checkMounts(app string, regions []string, mounts []VolumeMount) error {
+	if len(mounts) > 0 && len(regions) > 1 {
+		return fmt.Errorf("volumes can't be mounted by an application deployed to several regions")
+	}
+	region := ""
+	if len(regions) == 1 {
+		region = regions[0]
+	}
+
+	volumesMu.Lock()
+	defer volumesMu.Unlock()
+	for _, mount := range mounts {
+		volume, ok := volumes[mount.Name]
+		switch {
+		case !ok:
+			return fmt.Errorf("volume %s not found. Create it with: ghaymah volumes create %s --size 10G", mount.Name, mount.Name)
+		case volume.AttachedTo != "" && volume.AttachedTo != app:
+			return fmt.Errorf("volume %s is mounted by %s", mount.Name, volume.AttachedTo)
+		case region != "" && volume.Region != "" && volume.Region != region:
+			return fmt.Errorf("volume %s is in %s, not in %s", mount.Name, volume.Region, region)
+		}
+	}
+	return nil
+}
+
+// mountVolumes attaches the volumes an app mounts and detaches the ones it
+// no longer lists
+func mountVolumes(app string, mounts []VolumeMount) {
+	volumesMu.Lock()
+	defer volumesMu.Unlock()
+	for _, volume := range volumes {
+		if volume.AttachedTo == app {
+			volume.AttachedTo, volume.MountPath, volume.ReadOnly, volume.State = "", "", false, "available"
+		}
+	}
+	for _, mount := range mounts {
+		if volume, ok := volumes[mount.Name]; ok {
+			volume.AttachedTo, volume.MountPath, volume.ReadOnly, volume.State = app, mount.MountPath, mount.ReadOnly, "attached"
+		}
+	}
+}
+
+// createVolume records a new volume, failing if the name is taken
+func createVolume(name, size, region string, used float64) (*Volume, int, error) {
+	if !volumeName.MatchString(name) {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid volume name %q", name)
+	}
+	bytes, err := parseSize(size)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if region == "" {
+		region = regions[0].Name
+	}
+
+	volumesMu.Lock()
+	defer volumesMu.Unlock()
+	if _, ok := volumes[name]; ok {
+		return nil, http.StatusConflict, fmt.Errorf("volume %s already exists", name)
+	}
+	if used < 0 {
+		used = usedBytes(name, bytes)
+	}
+	volume := &Volume{
+		Name:      name,
+		Region:    region,
+		Size:      size,
+		SizeBytes: bytes,
+		UsedBytes: used,
+		State:     "available",
+		CreatedAt: time.Now(),
+	}
+	volumes[name] = volume
+	return volume, http.StatusOK, nil
+}
+
+func volumesHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		volumesMu.Lock()
+		list := []Volume{}
+		for _, volume := range volumes {
+			list = append(list, *volume)
+		}
+		volumesMu.Unlock()
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		json.NewEncoder(w).Encode(map[string]interface{}{"volumes": list})
+
+	case http.MethodPost:
+		var req struct {
+			Name   string `json:"name"`
+			Size   string `json:"size"`
+			Region string `json:"region"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		volume, status, err := createVolume(req.Name, req.Size, req.Region, -1)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		json.NewEncoder(w).Encode(volume)
+
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		volumesMu.Lock()
+		defer volumesMu.Unlock()
+		volume, ok := volumes[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Volume %s not found", name), http.StatusNotFound)
+			return
+		}
+		if volume.AttachedTo != "" {
+			http.Error(w, fmt.Sprintf("volume is mounted by %s at %s", volume.AttachedTo, volume.MountPath), http.StatusConflict)
+			return
+		}
+		delete(volumes, name)
+		delete(snapshots, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func resizeVolumeHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+		Size string `json:"size"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	bytes, err := parseSize(req.Size)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	volumesMu.Lock()
+	defer volumesMu.Unlock()
+	volume, ok := volumes[req.Name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Volume %s not found", req.Name), http.StatusNotFound)
+		return
+	}
+	if bytes <= volume.SizeBytes {
+		http.Error(w, fmt.Sprintf("volumes can only grow, and %s is already %s", req.Name, volume.Size), http.StatusBadRequest)
+		return
+	}
+	volume.Size, volume.SizeBytes = req.Size, bytes
+	json.NewEncoder(w).Encode(volume)
+}
+
+func volumeSnapshotsHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("volume")
+		volumesMu.Lock()
+		defer volumesMu.Unlock()
+		if _, ok := volumes[name]; !ok {
+			http.Error(w, fmt.Sprintf("Volume %s not found", name), http.StatusNotFound)
+			return
+		}
+		list := []VolumeSnapshot{}
+		for i := len(snapshots[name]) - 1; i >= 0; i-- {
+			list = append(list, snapshots[name][i])
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"snapshots": list})
+
+	case http.MethodPost:
+		var req struct {
+			Volume      string `json:"volume"`
+			Description string `json:"description"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		volumesMu.Lock()
+		defer volumesMu.Unlock()
+		volume, ok := volumes[req.Volume]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Volume %s not found", req.Volume), http.StatusNotFound)
+			return
+		}
+		snapshot := VolumeSnapshot{
+			ID:          "snap-" + randomHex(4),
+			Volume:      volume.Name,
+			Description: req.Description,
+			SizeBytes:   volume.UsedBytes,
+			CreatedAt:   time.Now(),
+		}
+		snapshots[volume.Name] = append(snapshots[volume.Name], snapshot)
+		json.NewEncoder(w).Encode(snapshot)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func restoreVolumeHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name     string `json:"name"`
+		Snapshot string `json:"snapshot"`
+		To       string `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	volumesMu.Lock()
+	volume, ok := volumes[req.Name]
+	if !ok {
+		volumesMu.Unlock()
+		http.Error(w, fmt.Sprintf("Volume %s not found", req.Name), http.StatusNotFound)
+		return
+	}
+	var snapshot *VolumeSnapshot
+	for i := range snapshots[req.Name] {
+		if snapshots[req.Name][i].ID == req.Snapshot {
+			snapshot = &snapshots[req.Name][i]
+		}
+	}
+	if snapshot == nil {
+		volumesMu.Unlock()
+		http.Error(w, fmt.Sprintf("Snapshot %s of volume %s not found", req.Snapshot, req.Name), http.StatusNotFound)
+		return
+	}
+	size, region, used := volume.Size, volume.Region, snapshot.SizeBytes
+
+	if req.To == "" {
+		if volume.AttachedTo != "" && appState(volume.AttachedTo) != "stopped" {
+			volumesMu.Unlock()
+			http.Error(w, fmt.Sprintf("it is mounted by %s, which must be stopped first (ghaymah stop %s), or restore into a new volume with --to", volume.AttachedTo, volume.AttachedTo), http.StatusConflict)
+			return
+		}
+		volume.UsedBytes = used
+		restored := *volume
+		volumesMu.Unlock()
+		json.NewEncoder(w).Encode(restored)
+		return
+	}
+	volumesMu.Unlock()
+
+	restored, status, err := createVolume(req.To, size, region, used)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	json.NewEncoder(w).Encode(restored)
+}
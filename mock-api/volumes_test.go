@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]float64{
+		"10G":   10 << 30,
+		"512Mi": 512 << 20,
+		"20Gi":  20 << 30,
+		"1GiB":  1 << 30,
+		"2tb":   2 << 40,
+	}
+	for size, want := range tests {
+		if got, err := parseSize(size); err != nil || got != want {
+			t.Errorf("parseSize(%q) = %v, %v, want %v", size, got, err, want)
+		}
+	}
+	for _, size := range []string{"0G", "10", "1.5G", "99999999T", "99999999999999999999K"} {
+		if _, err := parseSize(size); err == nil {
+			t.Errorf("parseSize(%q): expected an error", size)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type Webhook struct {
+	ID        string    `json:"id"`
+	App       string    `json:"app"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type WebhookEvent struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	App       string                 `json:"app"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+type WebhookDelivery struct {
+	Event      string `json:"event"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	DurationMs int    `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+var (
+	webhooksMu    sync.Mutex
+	webhooks      = map[string]*Webhook{}
+	nextWebhookID = 1
+)
+
+// randomHex returns n random bytes, hex encoded
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// subscribed reports whether the webhook receives events of the given type
+func (h *Webhook) subscribed(eventType string) bool {
+	for _, e := range h.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver posts a signed event to the webhook
+func (h *Webhook) deliver(event WebhookEvent) WebhookDelivery {
+	body, _ := json.Marshal(event)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(h.Secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	delivery := WebhookDelivery{Event: event.Type}
+	req, _ := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Ghaymah-Event", event.Type)
+	req.Header.Set("X-Ghaymah-Delivery", event.ID)
+	req.Header.Set("X-Ghaymah-Signature", fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil))))
+
+	start := time.Now()
+	resp, err := notifyClient.Do(req)
+	delivery.DurationMs = int(time.Since(start).Milliseconds())
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	resp.Body.Close()
+	delivery.StatusCode = resp.StatusCode
+	return delivery
+}
+
+// emitEvent records an event of an app and sends it to the webhooks subscribed to it
+func emitEvent(app, eventType, actor, message string, data map[string]interface{}) {
+	event := WebhookEvent{
+		ID:        "evt_" + randomHex(8),
+		Type:      eventType,
+		App:       app,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+	recordEvent(AppEvent{
+		ID:        event.ID,
+		Type:      eventType,
+		App:       app,
+		Actor:     actor,
+		Message:   message,
+		Data:      data,
+		Timestamp: event.Timestamp,
+	})
+
+	webhooksMu.Lock()
+	defer webhooksMu.Unlock()
+	for _, h := range webhooks {
+		if h.App != app || !h.subscribed(eventType) {
+			continue
+		}
+		go func(h Webhook) {
+			delivery := h.deliver(event)
+			log.Printf("Webhook %s: %s delivered with status %d %s", h.ID, eventType, delivery.StatusCode, delivery.Error)
+		}(*h)
+	}
+}
+
+func webhooksHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	webhooksMu.Lock()
+	defer webhooksMu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		list := []Webhook{}
+		for _, h := range webhooks {
+			if h.App == name {
+				listed := *h
+				listed.Secret = ""
+				list = append(list, listed)
+			}
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+		json.NewEncoder(w).Encode(map[string]interface{}{"webhooks": list})
+	case http.MethodPost:
+		var h Webhook
+		if err := json.NewDecoder(r.Body).Decode(&h); err != nil || h.App == "" || h.URL == "" || len(h.Events) == 0 {
+			http.Error(w, "App, URL and events are required", http.StatusBadRequest)
+			return
+		}
+		h.ID = fmt.Sprintf("wh-%d", nextWebhookID)
+		nextWebhookID++
+		if h.Secret == "" {
+			h.Secret = "whsec_" + randomHex(16)
+		}
+		h.CreatedAt = time.Now()
+		webhooks[h.ID] = &h
+		json.NewEncoder(w).Encode(h)
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if _, ok := webhooks[id]; !ok {
+			http.Error(w, fmt.Sprintf("Webhook %s not found", id), http.StatusNotFound)
+			return
+		}
+		delete(webhooks, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func testWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	if !validateToken(r) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ID    string `json:"id"`
+		Event string `json:"event"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	if req.Event == "" {
+		req.Event = "deploy.succeeded"
+	}
+
+	webhooksMu.Lock()
+	h, ok := webhooks[req.ID]
+	webhooksMu.Unlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("Webhook %s not found", req.ID), http.StatusNotFound)
+		return
+	}
+
+	app := getApp(h.App)
+	event := WebhookEvent{
+		ID:        "evt_test_" + randomHex(8),
+		Type:      req.Event,
+		App:       h.App,
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"test": true, "version": app.Version, "image": app.Image},
+	}
+	json.NewEncoder(w).Encode(h.deliver(event))
+}